@@ -20,6 +20,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/datas"
 )
 
 func TestParseDate(t *testing.T) {
@@ -84,3 +86,114 @@ func TestParseAuthor(t *testing.T) {
 		})
 	}
 }
+
+func TestAddSignoff(t *testing.T) {
+	tests := []struct {
+		msg    string
+		expMsg string
+	}{
+		{"Fix data", "Fix data\n\nSigned-off-by: John Doe <hi@hi.com>"},
+		{"Fix data\n", "Fix data\n\nSigned-off-by: John Doe <hi@hi.com>"},
+		{"Fix data\n\nSigned-off-by: Jane Doe <jane@hi.com>", "Fix data\n\nSigned-off-by: Jane Doe <jane@hi.com>\nSigned-off-by: John Doe <hi@hi.com>"},
+		{"Fix data\n\nSigned-off-by: John Doe <hi@hi.com>", "Fix data\n\nSigned-off-by: John Doe <hi@hi.com>"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.msg, func(t *testing.T) {
+			assert.Equal(t, test.expMsg, AddSignoff(test.msg, "John Doe", "hi@hi.com"))
+		})
+	}
+}
+
+func TestGetCommitMetadata(t *testing.T) {
+	ap := CreateCommitArgParser()
+
+	apr, err := ap.Parse([]string{"-m", "msg"})
+	require.NoError(t, err)
+	md, err := GetCommitMetadata(apr)
+	require.NoError(t, err)
+	assert.Nil(t, md)
+
+	apr, err = ap.Parse([]string{"--meta", "ticket=DOLT-123", "--meta", "note=a=b", "--meta", "empty="})
+	require.NoError(t, err)
+	md, err = GetCommitMetadata(apr)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"ticket": "DOLT-123", "note": "a=b", "empty": ""}, md)
+
+	apr, err = ap.Parse([]string{"--meta", "timezone=mine", "--timezone", "+05:30"})
+	require.NoError(t, err)
+	md, err = GetCommitMetadata(apr)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"timezone": "mine", datas.TimeZoneMetadataKey: "+05:30"}, md)
+
+	for _, args := range [][]string{
+		{"--meta", "ticket"},
+		{"--meta", "=value"},
+		{"--meta", "bad key=value"},
+		{"--meta", "ticket=1", "--meta", "ticket=2"},
+		{"--meta", datas.TimeZoneMetadataKey + "=+05:30"},
+		{"--meta", datas.EpochNanosMetadataKey + "=1"},
+	} {
+		apr, err = ap.Parse(args)
+		require.NoError(t, err)
+		_, err = GetCommitMetadata(apr)
+		assert.Error(t, err, "%v", args)
+	}
+}
+
+func TestGetCommitMessage(t *testing.T) {
+	apr, err := CreateCommitArgParser().Parse([]string{"-m", "subject", "-m", "body"})
+	require.NoError(t, err)
+	msg, ok := GetCommitMessage(apr)
+	require.True(t, ok)
+	assert.Equal(t, "subject\n\nbody", msg)
+
+	apr, err = CreateCommitArgParser().Parse([]string{"--message-line", "subject", "--message-line", "", "--message-line", "- fixed -m handling", "--allow-empty"})
+	require.NoError(t, err)
+	require.NoError(t, VerifyCommitArgs(apr))
+	msg, ok = GetCommitMessage(apr)
+	require.True(t, ok)
+	assert.Equal(t, "subject\n\n- fixed -m handling", msg)
+	assert.True(t, apr.Contains(AllowEmptyFlag))
+
+	apr, err = CreateCommitArgParser().Parse([]string{"-m", "subject", "--message-line", "body"})
+	require.NoError(t, err)
+	assert.Error(t, VerifyCommitArgs(apr))
+
+	apr, err = CreateCommitArgParser().Parse([]string{"--allow-empty"})
+	require.NoError(t, err)
+	_, ok = GetCommitMessage(apr)
+	assert.False(t, ok)
+}
+
+func TestNormalizeLineEndings(t *testing.T) {
+	assert.Equal(t, "Fix data\n\nMore detail\n", NormalizeLineEndings("Fix data\r\n\r\nMore detail\r\n"))
+	assert.Equal(t, "Fix data\nMore detail", NormalizeLineEndings("Fix data\rMore detail"))
+	assert.Equal(t, "Fix data\nMore detail", NormalizeLineEndings("Fix data\nMore detail"))
+}
+
+func TestCleanupCommitMessage(t *testing.T) {
+	msg := "Fix data\n\nMore detail\n\n" + ScissorsLine + "\ndiff --dolt a/t b/t\n"
+	cleaned, err := CleanupCommitMessage(msg, CleanupScissors)
+	require.NoError(t, err)
+	assert.Equal(t, "Fix data\n\nMore detail", cleaned)
+
+	cleaned, err = CleanupCommitMessage("Fix data\n", CleanupScissors)
+	require.NoError(t, err)
+	assert.Equal(t, "Fix data\n", cleaned)
+
+	cleaned, err = CleanupCommitMessage(msg, CleanupVerbatim)
+	require.NoError(t, err)
+	assert.Equal(t, msg, cleaned)
+
+	_, err = CleanupCommitMessage(msg, "strip")
+	assert.Error(t, err)
+}
+
+func TestAbbreviateHash(t *testing.T) {
+	h := "a1b2c3d4e5f6g7h8i9j0k1l2m3n4o5p6"
+	assert.Equal(t, "a1b2c3d4", AbbreviateHash(h, 8))
+	assert.Equal(t, "a1b2", AbbreviateHash(h, 1))
+	assert.Equal(t, h, AbbreviateHash(h, 0))
+	assert.Equal(t, h, AbbreviateHash(h, 40))
+}
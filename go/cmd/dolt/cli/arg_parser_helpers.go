@@ -17,12 +17,15 @@ package cli
 import (
 	"errors"
 	"fmt"
+	"math"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/store/datas"
 )
 
 const VerboseFlag = "verbose"
@@ -44,8 +47,13 @@ var SupportedLayouts = []string{
 
 // Parses a date string. Used by multiple commands.
 func ParseDate(dateStr string) (time.Time, error) {
+	return ParseDateInLocation(dateStr, time.UTC)
+}
+
+// ParseDateInLocation is like ParseDate, but dates which don't give a time zone are taken to be in |loc|.
+func ParseDateInLocation(dateStr string, loc *time.Location) (time.Time, error) {
 	for _, layout := range SupportedLayouts {
-		t, err := time.Parse(layout, dateStr)
+		t, err := time.ParseInLocation(layout, dateStr, loc)
 
 		if err == nil {
 			return t, nil
@@ -119,6 +127,49 @@ const (
 	UserParam        = "user"
 	NoPrettyFlag     = "no-pretty"
 	ShowIgnoredFlag  = "ignored"
+	AbbrevParam      = "abbrev"
+)
+
+// MinAbbrev is the fewest characters a hash is abbreviated to with --abbrev, to keep abbreviated hashes unlikely to be
+// ambiguous.
+const MinAbbrev = 4
+
+// AbbreviateHash returns the first |n| characters of the hash string |h|, or at least MinAbbrev of them. The whole hash
+// is returned if |n| is 0 or not less than its length.
+func AbbreviateHash(h string, n int) string {
+	if n <= 0 || n >= len(h) {
+		return h
+	}
+	if n < MinAbbrev {
+		n = MinAbbrev
+	}
+	return h[:n]
+}
+
+const (
+	RowStatsFlag           = "row-stats"
+	ExactRowStatsFlag      = "exact-row-stats"
+	ReflogFlag             = "reflog"
+	AmendMetadataOnlyFlag  = "amend-metadata-only"
+	SignoffFlag            = "signoff"
+	WorkingSetParam        = "working-set"
+	MetaParam              = "meta"
+	KeepLineEndingsFlag    = "keep-line-endings"
+	RetryParam             = "retry"
+	TreeParam              = "tree"
+	MaxSubjectLengthParam  = "max-subject-length"
+	CleanupParam           = "cleanup"
+	MaxTablesParam         = "max-tables"
+	TimeZoneParam          = "timezone"
+	ViolationsResolvedFlag = "violations-resolved"
+	ResetAuthorFlag        = "reset-author"
+	MessageLineParam       = "message-line"
+	NoFlushFlag            = "no-flush"
+	NoMergesFlag           = "no-merges"
+	DateEpochNanosParam    = "date-epoch-nanos"
+	KeepFlag               = "keep"
+	NoPartialFlag          = "no-partial"
+	ReproducibleFlag       = "reproducible"
 )
 
 const (
@@ -140,15 +191,38 @@ var branchForceFlagDesc = "Reset {{.LessThan}}branchname{{.GreaterThan}} to {{.L
 // CreateCommitArgParser creates the argparser shared dolt commit cli and DOLT_COMMIT.
 func CreateCommitArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParserWithMaxArgs("commit", 0)
-	ap.SupportsString(MessageArg, "m", "msg", "Use the given {{.LessThan}}msg{{.GreaterThan}} as the commit message.")
+	ap.SupportsRepeatedString(MessageArg, "m", "msg", "Use the given {{.LessThan}}msg{{.GreaterThan}} as the commit message. If multiple {{.EmphasisLeft}}-m{{.EmphasisRight}} options are given, their values are concatenated as separate paragraphs.")
+	ap.SupportsRepeatedString(MessageLineParam, "", "line", "Use the given {{.LessThan}}line{{.GreaterThan}} as the next line of the commit message, exactly as given, even if it's empty or starts with {{.EmphasisLeft}}-{{.EmphasisRight}}. May be given more than once, so that a message built by a client can be passed one argument per line. Cannot be used with {{.EmphasisLeft}}-m{{.EmphasisRight}}. Only supported by {{.EmphasisLeft}}DOLT_COMMIT(){{.EmphasisRight}}.")
 	ap.SupportsFlag(AllowEmptyFlag, "", "Allow recording a commit that has the exact same data as its sole parent. This is usually a mistake, so it is disabled by default. This option bypasses that safety. Cannot be used with --skip-empty.")
 	ap.SupportsFlag(SkipEmptyFlag, "", "Only create a commit if there are staged changes. If no changes are staged, the call to commit is a no-op. Cannot be used with --allow-empty.")
 	ap.SupportsString(DateParam, "", "date", "Specify the date used in the commit. If not specified the current system time is used.")
+	ap.SupportsUint(DateEpochNanosParam, "", "nanos", "Use the time {{.LessThan}}nanos{{.GreaterThan}} nanoseconds after the Unix epoch as the date of the commit, e.g. to keep the order of commits imported from a source with sub-second timestamps. The commit's timestamps only have millisecond precision, so the exact time is also recorded in the {{.EmphasisLeft}}"+datas.EpochNanosMetadataKey+"{{.EmphasisRight}} commit metadata. Cannot be used with {{.EmphasisLeft}}--date{{.EmphasisRight}}.")
 	ap.SupportsFlag(ForceFlag, "f", "Ignores any foreign key warnings and proceeds with the commit.")
 	ap.SupportsString(AuthorParam, "", "author", "Specify an explicit author using the standard A U Thor {{.LessThan}}author@example.com{{.GreaterThan}} format.")
 	ap.SupportsFlag(AllFlag, "a", "Adds all existing, changed tables (but not new tables) in the working set to the staged set.")
 	ap.SupportsFlag(UpperCaseAllFlag, "A", "Adds all tables (including new tables) in the working set to the staged set.")
 	ap.SupportsFlag(AmendFlag, "", "Amend previous commit")
+	ap.SupportsFlag(AmendMetadataOnlyFlag, "", "Amend the message, author or date of the previous commit, keeping its exact contents. Staged and working changes are left untouched. The author and date of the previous commit are kept unless given explicitly.")
+	ap.SupportsFlag(ResetAuthorFlag, "", "When amending, make the current user the author of the commit and set its date to now, unless {{.EmphasisLeft}}--date{{.EmphasisRight}} is given. This is what {{.EmphasisLeft}}--amend{{.EmphasisRight}} does by default, and changes {{.EmphasisLeft}}--amend-metadata-only{{.EmphasisRight}}, which otherwise keeps the author and date of the previous commit, while still keeping its exact contents. Cannot be used with {{.EmphasisLeft}}--author{{.EmphasisRight}}.")
+	ap.SupportsFlag(RowStatsFlag, "", "Report an estimate of the number of rows added and deleted by the commit: the net change in the row count of each changed table. Modified rows aren't counted.")
+	ap.SupportsFlag(ExactRowStatsFlag, "", "Report the exact number of rows added, modified and deleted by the commit. This diffs every changed table and can be slow for large changes.")
+	ap.SupportsFlag(ReflogFlag, "", "Record the update to the branch head in the reflog, which can be queried with the {{.EmphasisLeft}}dolt_reflog{{.EmphasisRight}} system table.")
+	ap.SupportsString(WorkingSetParam, "", "ref", "Commit the staged changes of the working set {{.LessThan}}ref{{.GreaterThan}}, e.g. {{.EmphasisLeft}}heads/feature{{.EmphasisRight}}, to its branch, without checking it out. Only supported by {{.EmphasisLeft}}DOLT_COMMIT(){{.EmphasisRight}}.")
+	ap.SupportsFlag(SignoffFlag, "s", "Add a {{.EmphasisLeft}}Signed-off-by{{.EmphasisRight}} trailer by the commit author at the end of the commit message.")
+	ap.SupportsFlag(KeepLineEndingsFlag, "", "Store the commit message exactly as given. By default, Windows (CRLF) line endings in the message are converted to LF.")
+	ap.SupportsRepeatedString(MetaParam, "", "key=value", "Attach the metadata {{.LessThan}}key{{.GreaterThan}}={{.LessThan}}value{{.GreaterThan}} to the commit, e.g. a ticket or build ID. May be given more than once. The metadata is shown in the {{.EmphasisLeft}}metadata{{.EmphasisRight}} column of {{.EmphasisLeft}}dolt_log{{.EmphasisRight}}.")
+	ap.SupportsString(TimeZoneParam, "", "zone", "Record the commit as made in the time zone {{.LessThan}}zone{{.GreaterThan}}, an IANA name such as {{.EmphasisLeft}}America/New_York{{.EmphasisRight}} or a UTC offset such as {{.EmphasisLeft}}+05:30{{.EmphasisRight}}. A {{.EmphasisLeft}}--date{{.EmphasisRight}} without a time zone is taken to be in it, and {{.EmphasisLeft}}dolt log{{.EmphasisRight}} shows the commit's date in it. Defaults to the local time zone.")
+	ap.SupportsString(CleanupParam, "", "mode", "How to clean up the commit message before committing. {{.EmphasisLeft}}verbatim{{.EmphasisRight}}, the default, keeps it as given. {{.EmphasisLeft}}scissors{{.EmphasisRight}} removes the line {{.EmphasisLeft}}"+ScissorsLine+"{{.EmphasisRight}} and everything after it, such as a diff shown by an editor.")
+	ap.SupportsString(TreeParam, "", "hash", "Use the root value with the given {{.LessThan}}hash{{.GreaterThan}} as the tree of the commit, instead of the staged changes. The working set must have no changes, and is set to the new tree. Requires {{.EmphasisLeft}}--force{{.EmphasisRight}}. Only supported by {{.EmphasisLeft}}DOLT_COMMIT(){{.EmphasisRight}}.")
+	ap.SupportsUint(MaxSubjectLengthParam, "", "n", "Reject the commit if the first line of its message is longer than {{.LessThan}}n{{.GreaterThan}} characters. Defaults to the value of {{.EmphasisLeft}}@@dolt_commit_max_subject_length{{.EmphasisRight}}, and 0 means there's no limit. Only supported by {{.EmphasisLeft}}DOLT_COMMIT(){{.EmphasisRight}}.")
+	ap.SupportsUint(MaxTablesParam, "", "n", "Reject the commit if it changes more than {{.LessThan}}n{{.GreaterThan}} tables, as a guard against accidentally rewriting a whole database. Defaults to the value of {{.EmphasisLeft}}@@<database>_commit_max_tables{{.EmphasisRight}}, and 0 means there's no limit. Only supported by {{.EmphasisLeft}}DOLT_COMMIT(){{.EmphasisRight}}.")
+	ap.SupportsFlag(ViolationsResolvedFlag, "", "Commit only the tables whose constraint violations in HEAD have all been resolved in the working set, leaving any other staged or working changes uncommitted. Cannot be used while a merge is in progress. Only supported by {{.EmphasisLeft}}DOLT_COMMIT(){{.EmphasisRight}}.")
+	ap.SupportsFlag(NoMergesFlag, "", "Fail instead of creating a merge commit, e.g. while a merge is in progress or when amending a merge commit, to keep the history of the branch linear. Merge commits on protected branches can also be refused for every commit by the server with {{.EmphasisLeft}}@@dolt_protected_branches_linear_history{{.EmphasisRight}}.")
+	ap.SupportsFlag(NoFlushFlag, "", "Don't sync the commit to disk, for faster bulk loads. This is unsafe: the commit, and any earlier commits made with this option, can be lost in a crash until a later commit made without it syncs the database. Only affects databases stored in a chunk journal, and requires the server to set {{.EmphasisLeft}}@@dolt_commit_allow_no_flush{{.EmphasisRight}}. Only supported by {{.EmphasisLeft}}DOLT_COMMIT(){{.EmphasisRight}}.")
+	ap.SupportsFlag(NoPartialFlag, "", "Fail, listing the tables with unstaged changes, if any changes in the working set are left unstaged after the staging done by {{.EmphasisLeft}}-a{{.EmphasisRight}} or {{.EmphasisLeft}}-A{{.EmphasisRight}}, so that nothing is left out of the commit. New tables ignored by {{.EmphasisLeft}}dolt_ignore{{.EmphasisRight}} aren't counted.")
+	ap.SupportsFlag(KeepFlag, "", "Create the commit without moving the branch or changing the working set, so the staged and working changes stay as they were, e.g. to checkpoint work in progress. Tables staged with {{.EmphasisLeft}}-a{{.EmphasisRight}} or {{.EmphasisLeft}}-A{{.EmphasisRight}} are included in the commit but not left staged. The commit's hash is returned, and it isn't on any branch, so it can be removed by garbage collection unless a branch or tag is created for it. Only supported by {{.EmphasisLeft}}DOLT_COMMIT(){{.EmphasisRight}}.")
+	ap.SupportsFlag(ReproducibleFlag, "", "Use the date given with {{.EmphasisLeft}}--date{{.EmphasisRight}} or {{.EmphasisLeft}}--date-epoch-nanos{{.EmphasisRight}} for the commit's internal timestamp too, instead of the time the commit is written, so that the commit hash only depends on the arguments and the changes committed. This is the hash reported by {{.EmphasisLeft}}DOLT_PREVIEW_COMMIT(){{.EmphasisRight}}. Only supported by {{.EmphasisLeft}}DOLT_COMMIT(){{.EmphasisRight}}.")
+	ap.SupportsUint(RetryParam, "", "n", "If the commit fails because it conflicts with a transaction committed concurrently by another client, retry it up to {{.LessThan}}n{{.GreaterThan}} times, waiting a little longer before each attempt. Only supported by {{.EmphasisLeft}}DOLT_COMMIT(){{.EmphasisRight}}.")
 	return ap
 }
 
@@ -259,6 +333,16 @@ func CreateRevertArgParser() *argparser.ArgParser {
 	return ap
 }
 
+// CreateRewordArgParser creates the argparser for DOLT_REWORD.
+func CreateRewordArgParser() *argparser.ArgParser {
+	ap := argparser.NewArgParserWithMaxArgs("reword", 2)
+	ap.SupportsString(AuthorParam, "", "author", "Also replace the author of the commit, using the standard A U Thor {{.LessThan}}author@example.com{{.GreaterThan}} format.")
+	ap.SupportsFlag(ForceFlag, "f", "Rewrite the commit even if it's reachable from other branches, which keep the original history.")
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"revision", "The commit to reword. It must be on the first-parent history of the current branch."})
+	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"message", "The new commit message."})
+	return ap
+}
+
 func CreatePullArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParserWithMaxArgs("pull", 2)
 	ap.ArgListHelp = append(ap.ArgListHelp, [2]string{"remote", "The name of the remote to pull from."})
@@ -325,6 +409,7 @@ func CreateLogArgParser() *argparser.ArgParser {
 	ap.SupportsString(DecorateFlag, "", "decorate_fmt", "Shows refs next to commits. Valid options are short, full, no, and auto")
 	ap.SupportsFlag(OneLineFlag, "", "Shows logs in a compact format.")
 	ap.SupportsStringList(NotFlag, "", "revision", "Excludes commits from revision.")
+	ap.SupportsInt(AbbrevParam, "", "n", "Shows only the first n characters of commit hashes, or at least 4.")
 	return ap
 }
 
@@ -406,6 +491,140 @@ func VerifyNoAwsParams(apr *argparser.ArgParseResults) error {
 	return nil
 }
 
+// GetCommitMessage returns the commit message given with -m. When more than one is given, they are joined as separate
+// paragraphs, the same way git does. A message given with --message-line instead is made of its values as lines.
+func GetCommitMessage(apr *argparser.ArgParseResults) (string, bool) {
+	if lines, ok := apr.GetValueRepeated(MessageLineParam); ok {
+		return strings.Join(lines, "\n"), true
+	}
+	msgs, ok := apr.GetValueRepeated(MessageArg)
+	if !ok {
+		return "", false
+	}
+	return strings.Join(msgs, "\n\n"), true
+}
+
+// lineEndingReplacer converts Windows and classic Mac OS line endings to Unix ones.
+var lineEndingReplacer = strings.NewReplacer("\r\n", "\n", "\r", "\n")
+
+// NormalizeLineEndings returns |msg| with all of its line endings converted to "\n", so that the same message written
+// on different platforms is stored identically.
+func NormalizeLineEndings(msg string) string {
+	return lineEndingReplacer.Replace(msg)
+}
+
+const (
+	CleanupVerbatim = "verbatim"
+	CleanupScissors = "scissors"
+)
+
+// ScissorsLine is the line after which the commit message is cut with --cleanup=scissors. The same as Git's.
+const ScissorsLine = "# ------------------------ >8 ------------------------"
+
+// CleanupCommitMessage returns |msg| cleaned up as given by the --cleanup |mode|, or an error for an unknown mode.
+func CleanupCommitMessage(msg, mode string) (string, error) {
+	switch mode {
+	case "", CleanupVerbatim:
+		return msg, nil
+	case CleanupScissors:
+		lines := strings.Split(msg, "\n")
+		for i, line := range lines {
+			if strings.TrimRight(line, " \t") == ScissorsLine {
+				return strings.TrimRight(strings.Join(lines[:i], "\n"), " \t\n"), nil
+			}
+		}
+		return msg, nil
+	default:
+		return "", fmt.Errorf("error: invalid --%s mode '%s', expected %s or %s", CleanupParam, mode, CleanupVerbatim, CleanupScissors)
+	}
+}
+
+// AddSignoff returns |msg| with a Signed-off-by trailer for the identity given appended to it. If the message already
+// ends in Signed-off-by trailers, the new one is added to them, and if it already ends with this exact trailer, the
+// message is returned unchanged.
+func AddSignoff(msg, name, email string) string {
+	trailer := fmt.Sprintf("Signed-off-by: %s <%s>", name, email)
+
+	msg = strings.TrimRight(msg, "\n")
+	lines := strings.Split(msg, "\n")
+	lastLine := lines[len(lines)-1]
+	switch {
+	case lastLine == trailer:
+		return msg
+	case strings.HasPrefix(lastLine, "Signed-off-by: "):
+		return msg + "\n" + trailer
+	default:
+		return msg + "\n\n" + trailer
+	}
+}
+
+// GetCommitMetadata returns the commit metadata given with --meta, along with the time zone given with --timezone and
+// the exact time given with --date-epoch-nanos, or nil if there is none. It's an error for a key to be given more than
+// once, for the time zone to be unknown, or for the metadata not to pass datas.ValidateCommitMetadata.
+func GetCommitMetadata(apr *argparser.ArgParseResults) (map[string]string, error) {
+	pairs, ok := apr.GetValueRepeated(MetaParam)
+	tz, hasTz := apr.GetValue(TimeZoneParam)
+	nanos, hasNanos := apr.GetUint(DateEpochNanosParam)
+	if !ok && !hasTz && !hasNanos {
+		return nil, nil
+	}
+
+	md := make(map[string]string, len(pairs)+2)
+	for _, pair := range pairs {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("error: invalid --%s '%s', expected key=value", MetaParam, pair)
+		}
+		if _, ok := md[k]; ok {
+			return nil, fmt.Errorf("error: commit metadata key '%s' given more than once", k)
+		}
+		md[k] = v
+	}
+	if err := datas.ValidateUserCommitMetadata(md); err != nil {
+		return nil, err
+	}
+	if hasTz {
+		if _, err := datas.ParseTimeZone(tz); err != nil {
+			return nil, fmt.Errorf("error: invalid --%s: %w", TimeZoneParam, err)
+		}
+		md[datas.TimeZoneMetadataKey] = tz
+	}
+	if hasNanos {
+		md[datas.EpochNanosMetadataKey] = strconv.FormatUint(nanos, 10)
+	}
+
+	if err := datas.ValidateCommitMetadata(md); err != nil {
+		return nil, err
+	}
+	return md, nil
+}
+
+// ParseCommitDate parses |dateStr|, given with --date, as ParseDate does. A date without a time zone is taken to be
+// in the one given with --timezone, if any.
+func ParseCommitDate(apr *argparser.ArgParseResults, dateStr string) (time.Time, error) {
+	loc := time.UTC
+	if tz, ok := apr.GetValue(TimeZoneParam); ok {
+		var err error
+		loc, err = datas.ParseTimeZone(tz)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("error: invalid --%s: %w", TimeZoneParam, err)
+		}
+	}
+	return ParseDateInLocation(dateStr, loc)
+}
+
+// GetCommitEpochNanos returns the date given with --date-epoch-nanos, and whether it was given.
+func GetCommitEpochNanos(apr *argparser.ArgParseResults) (time.Time, bool, error) {
+	nanos, ok := apr.GetUint(DateEpochNanosParam)
+	if !ok {
+		return time.Time{}, false, nil
+	}
+	if nanos > math.MaxInt64 {
+		return time.Time{}, false, fmt.Errorf("error: --%s %d is out of range", DateEpochNanosParam, nanos)
+	}
+	return time.Unix(0, int64(nanos)), true, nil
+}
+
 // VerifyCommitArgs validates the arguments in |apr| for `dolt commit` and returns an error
 // if any validation problems were encountered.
 func VerifyCommitArgs(apr *argparser.ArgParseResults) error {
@@ -413,5 +632,71 @@ func VerifyCommitArgs(apr *argparser.ArgParseResults) error {
 		return fmt.Errorf("error: cannot use both --allow-empty and --skip-empty")
 	}
 
+	if apr.Contains(AmendMetadataOnlyFlag) && (apr.Contains(AllFlag) || apr.Contains(UpperCaseAllFlag)) {
+		return fmt.Errorf("error: cannot stage tables with --amend-metadata-only")
+	}
+
+	if apr.Contains(MessageArg) && apr.Contains(MessageLineParam) {
+		return fmt.Errorf("error: cannot use both --%s and --%s", MessageArg, MessageLineParam)
+	}
+
+	if apr.Contains(DateParam) && apr.Contains(DateEpochNanosParam) {
+		return fmt.Errorf("error: cannot use both --%s and --%s", DateParam, DateEpochNanosParam)
+	}
+
+	if apr.Contains(ReproducibleFlag) && !apr.Contains(DateParam) && !apr.Contains(DateEpochNanosParam) {
+		return fmt.Errorf("error: --%s requires --%s or --%s", ReproducibleFlag, DateParam, DateEpochNanosParam)
+	}
+
+	if mode, ok := apr.GetValue(CleanupParam); ok {
+		if _, err := CleanupCommitMessage("", mode); err != nil {
+			return err
+		}
+	}
+
+	if apr.Contains(TreeParam) {
+		if !apr.Contains(ForceFlag) {
+			return fmt.Errorf("error: --%s requires --%s", TreeParam, ForceFlag)
+		}
+		for _, arg := range []string{AllFlag, UpperCaseAllFlag, AmendFlag, AmendMetadataOnlyFlag} {
+			if apr.Contains(arg) {
+				return fmt.Errorf("error: --%s cannot be used with --%s", arg, TreeParam)
+			}
+		}
+	}
+
+	if apr.Contains(NoPartialFlag) {
+		for _, arg := range []string{ViolationsResolvedFlag, AmendMetadataOnlyFlag} {
+			if apr.Contains(arg) {
+				return fmt.Errorf("error: --%s cannot be used with --%s", arg, NoPartialFlag)
+			}
+		}
+	}
+
+	if apr.Contains(KeepFlag) {
+		for _, arg := range []string{AmendFlag, AmendMetadataOnlyFlag, WorkingSetParam, TreeParam, ReflogFlag} {
+			if apr.Contains(arg) {
+				return fmt.Errorf("error: --%s cannot be used with --%s", arg, KeepFlag)
+			}
+		}
+	}
+
+	if apr.Contains(ViolationsResolvedFlag) {
+		for _, arg := range []string{AllFlag, UpperCaseAllFlag, AmendFlag, AmendMetadataOnlyFlag, TreeParam} {
+			if apr.Contains(arg) {
+				return fmt.Errorf("error: --%s cannot be used with --%s", arg, ViolationsResolvedFlag)
+			}
+		}
+	}
+
+	if apr.Contains(ResetAuthorFlag) {
+		if !apr.Contains(AmendFlag) && !apr.Contains(AmendMetadataOnlyFlag) {
+			return fmt.Errorf("error: --%s can only be used with --%s or --%s", ResetAuthorFlag, AmendFlag, AmendMetadataOnlyFlag)
+		}
+		if apr.Contains(AuthorParam) {
+			return fmt.Errorf("error: cannot use both --%s and --%s", ResetAuthorFlag, AuthorParam)
+		}
+	}
+
 	return nil
 }
@@ -17,9 +17,11 @@ package commands
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/fatih/color"
@@ -67,12 +69,33 @@ func (cmd CommitCmd) Description() string {
 }
 
 func (cmd CommitCmd) Docs() *cli.CommandDocumentation {
-	ap := cli.CreateCommitArgParser()
+	ap := createCommitCmdArgParser()
 	return cli.NewCommandDocumentation(commitDocs, ap)
 }
 
 func (cmd CommitCmd) ArgParser() *argparser.ArgParser {
-	return cli.CreateCommitArgParser()
+	return createCommitCmdArgParser()
+}
+
+// createCommitCmdArgParser returns the arguments of DOLT_COMMIT() along with those which only apply to the output of
+// dolt commit.
+func createCommitCmdArgParser() *argparser.ArgParser {
+	ap := cli.CreateCommitArgParser()
+	ap.SupportsInt(cli.AbbrevParam, "", "n", "Show only the first n characters of the new commit's hash, or at least 4.")
+	ap.SupportsString(commitOutputParam, "", "format", "Print the result in the given format instead of the new commit. Only {{.EmphasisLeft}}json{{.EmphasisRight}} is supported, which prints a single object with the {{.EmphasisLeft}}commit{{.EmphasisRight}} hash, or null if the commit was skipped, whether it was {{.EmphasisLeft}}skipped{{.EmphasisRight}}, and the {{.EmphasisLeft}}tables{{.EmphasisRight}} it changed.")
+	return ap
+}
+
+const (
+	commitOutputParam = "output"
+	commitOutputJSON  = "json"
+)
+
+// commitJSONResult is the result of dolt commit printed with --output json.
+type commitJSONResult struct {
+	Commit  *string  `json:"commit"`
+	Skipped bool     `json:"skipped"`
+	Tables  []string `json:"tables"`
 }
 
 // Exec executes the command
@@ -82,26 +105,148 @@ func (cmd CommitCmd) Exec(ctx context.Context, commandStr string, args []string,
 		return res
 	}
 
+	apr, err := createCommitCmdArgParser().Parse(args)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), nil)
+	}
+
+	if apr.Contains(commitOutputParam) {
+		return printCommitJSON(ctx, dEnv, skipped)
+	}
+
 	if skipped {
 		iohelp.WriteLine(cli.CliOut, "Skipping empty commit")
 		return res
 	}
 
 	// if the commit was successful, print it out using the log command
-	return LogCmd{}.Exec(ctx, "log", []string{"-n=1"}, dEnv, nil)
+	logArgs := []string{"-n=1"}
+	if abbrev, ok := apr.GetInt(cli.AbbrevParam); ok {
+		logArgs = append(logArgs, fmt.Sprintf("--%s=%d", cli.AbbrevParam, abbrev))
+	}
+	res = LogCmd{}.Exec(ctx, "log", logArgs, dEnv, nil)
+	if res != 0 {
+		return res
+	}
+	if apr.Contains(cli.RowStatsFlag) || apr.Contains(cli.ExactRowStatsFlag) {
+		return printCommitRowStats(ctx, dEnv, apr.Contains(cli.ExactRowStatsFlag))
+	}
+
+	return 0
+}
+
+// printCommitJSON prints the result of dolt commit with --output json: the HEAD commit and the tables it changed from
+// its first parent, or that the commit was skipped.
+func printCommitJSON(ctx context.Context, dEnv *env.DoltEnv, skipped bool) int {
+	result := commitJSONResult{Skipped: skipped, Tables: []string{}}
+	if !skipped {
+		headCommit, err := dEnv.HeadCommit(ctx)
+		if err != nil {
+			return HandleVErrAndExitCode(errhand.BuildDError("Couldn't get HEAD commit").AddCause(err).Build(), nil)
+		}
+		h, err := headCommit.HashOf()
+		if err != nil {
+			return HandleVErrAndExitCode(errhand.BuildDError("Couldn't get HEAD commit").AddCause(err).Build(), nil)
+		}
+		hashStr := h.String()
+		result.Commit = &hashStr
+
+		result.Tables, err = commitChangedTables(ctx, headCommit)
+		if err != nil {
+			return HandleVErrAndExitCode(errhand.BuildDError("Couldn't get the tables changed by the commit").AddCause(err).Build(), nil)
+		}
+	}
+
+	out, err := json.Marshal(result)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), nil)
+	}
+	cli.Println(string(out))
+	return 0
+}
+
+// commitChangedTables returns the sorted names of the tables changed by |cm| from its first parent, or all of its
+// tables if it has no parent.
+func commitChangedTables(ctx context.Context, cm *doltdb.Commit) ([]string, error) {
+	root, err := cm.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var parentRoot *doltdb.RootValue
+	if cm.NumParents() > 0 {
+		parent, err := cm.GetParent(ctx, 0)
+		if err != nil {
+			return nil, err
+		}
+		parentRoot, err = parent.GetRootValue(ctx)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		parentRoot, err = doltdb.EmptyRootValue(ctx, root.VRW(), root.NodeStore())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	deltas, err := diff.GetTableDeltas(ctx, parentRoot, root)
+	if err != nil {
+		return nil, err
+	}
+	tables := make([]string, 0, len(deltas))
+	for _, td := range deltas {
+		tables = append(tables, td.CurName())
+	}
+	sort.Strings(tables)
+	return tables, nil
+}
+
+// printCommitRowStats prints the number of rows changed by the HEAD commit.
+func printCommitRowStats(ctx context.Context, dEnv *env.DoltEnv, exact bool) int {
+	headCommit, err := dEnv.HeadCommit(ctx)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.BuildDError("Couldn't get HEAD commit").AddCause(err).Build(), nil)
+	}
+
+	stats, err := actions.GetCommitRowStatsForCommit(ctx, headCommit, exact)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.BuildDError("Couldn't compute row stats for commit").AddCause(err).Build(), nil)
+	}
+
+	if exact {
+		cli.Printf("%d rows added, %d rows modified, %d rows deleted\n", stats.Added, stats.Modified, stats.Deleted)
+	} else {
+		cli.Printf("~%d rows added, ~%d rows deleted (estimated)\n", stats.Added, stats.Deleted)
+	}
+	return 0
 }
 
 // performCommit creates a new Dolt commit using the specified |commandStr| and |args| for the specified Dolt environment
 // |dEnv|. The response is an integer status code indicating success or failure, as well as a boolean that indicates
 // if the commit was skipped (e.g. because --skip-empty was specified as an argument).
 func performCommit(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv) (int, bool) {
-	ap := cli.CreateCommitArgParser()
+	ap := createCommitCmdArgParser()
 	help, usage := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, commitDocs, ap))
 	apr := cli.ParseArgsOrDie(ap, args, help)
 
 	if err := cli.VerifyCommitArgs(apr); err != nil {
 		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), help), false
 	}
+	for _, param := range []string{cli.WorkingSetParam, cli.RetryParam, cli.TreeParam, cli.MaxSubjectLengthParam, cli.MaxTablesParam, cli.ViolationsResolvedFlag, cli.MessageLineParam, cli.NoFlushFlag, cli.KeepFlag, cli.ReproducibleFlag} {
+		if apr.Contains(param) {
+			return HandleVErrAndExitCode(errhand.BuildDError("error: --%s is only supported by DOLT_COMMIT()", param).Build(), usage), false
+		}
+	}
+	if format, ok := apr.GetValue(commitOutputParam); ok {
+		if format != commitOutputJSON {
+			return HandleVErrAndExitCode(errhand.BuildDError("error: invalid value '%s' for --%s, expected '%s'", format, commitOutputParam, commitOutputJSON).Build(), usage), false
+		}
+		for _, param := range []string{cli.RowStatsFlag, cli.ExactRowStatsFlag, cli.AbbrevParam} {
+			if apr.Contains(param) {
+				return HandleVErrAndExitCode(errhand.BuildDError("error: cannot use both --%s and --%s", param, commitOutputParam).Build(), usage), false
+			}
+		}
+	}
 
 	allFlag := apr.Contains(cli.AllFlag)
 	upperCaseAllFlag := apr.Contains(cli.UpperCaseAllFlag)
@@ -126,14 +271,42 @@ func performCommit(ctx context.Context, commandStr string, args []string, dEnv *
 			return handleCommitErr(ctx, dEnv, err, help), false
 		}
 	}
+	if apr.Contains(cli.NoPartialFlag) {
+		if err := actions.CheckNoUnstagedChanges(ctx, roots); err != nil {
+			return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage), false
+		}
+	}
 
 	headCommit, _ := dEnv.HeadCommit(ctx)
 	headHash, _ := headCommit.HashOf()
 
+	amendMetadataOnly := apr.Contains(cli.AmendMetadataOnlyFlag)
+	amend := apr.Contains(cli.AmendFlag) || amendMetadataOnly
+	resetAuthor := apr.Contains(cli.ResetAuthorFlag)
+
+	var headMeta *datas.CommitMeta
+	if amend {
+		headMeta, err = headCommit.GetCommitMeta(ctx)
+		if err != nil {
+			return handleCommitErr(ctx, dEnv, err, usage), false
+		}
+	}
+
+	metadata, err := cli.GetCommitMetadata(apr)
+	if err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage), false
+	}
+	if metadata == nil && amend {
+		// amending keeps the metadata of the commit amended unless new metadata is given
+		metadata = headMeta.Metadata
+	}
+
 	var name, email string
 	// Check if the author flag is provided otherwise get the name and email stored in configs
 	if authorStr, ok := apr.GetValue(cli.AuthorParam); ok {
 		name, email, err = cli.ParseAuthor(authorStr)
+	} else if amendMetadataOnly && !resetAuthor {
+		name, email = headMeta.Name, headMeta.Email
 	} else {
 		// This command creates a commit, so we need user identity
 		if !cli.CheckUserNameAndEmail(dEnv) {
@@ -146,34 +319,66 @@ func performCommit(ctx context.Context, commandStr string, args []string, dEnv *
 		return handleCommitErr(ctx, dEnv, err, usage), false
 	}
 
-	msg, msgOk := apr.GetValue(cli.MessageArg)
+	msg, msgOk := cli.GetCommitMessage(apr)
 	if !msgOk {
 		amendStr := ""
-		if apr.Contains(cli.AmendFlag) {
-			commitMeta, cmErr := headCommit.GetCommitMeta(ctx)
-			if cmErr != nil {
-				return handleCommitErr(ctx, dEnv, cmErr, usage), false
-			}
-			amendStr = commitMeta.Description
+		if amend {
+			amendStr = headMeta.Description
 		}
 		msg, err = getCommitMessageFromEditor(ctx, dEnv, "", amendStr, false)
 		if err != nil {
 			return handleCommitErr(ctx, dEnv, err, usage), false
 		}
 	}
+	if !apr.Contains(cli.KeepLineEndingsFlag) {
+		msg = cli.NormalizeLineEndings(msg)
+	}
+	msg, err = cli.CleanupCommitMessage(msg, apr.GetValueOrDefault(cli.CleanupParam, cli.CleanupVerbatim))
+	if err != nil {
+		return handleCommitErr(ctx, dEnv, err, usage), false
+	}
+	if apr.Contains(cli.SignoffFlag) {
+		msg = cli.AddSignoff(msg, name, email)
+	}
 
 	t := datas.CommitNowFunc()
 	if commitTimeStr, ok := apr.GetValue(cli.DateParam); ok {
 		var err error
-		t, err = cli.ParseDate(commitTimeStr)
+		t, err = cli.ParseCommitDate(apr, commitTimeStr)
 
 		if err != nil {
 			return HandleVErrAndExitCode(errhand.BuildDError("error: invalid date").AddCause(err).Build(), usage), false
 		}
+	} else if nanosTime, ok, err := cli.GetCommitEpochNanos(apr); err != nil {
+		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage), false
+	} else if ok {
+		t = nanosTime
+	} else if amendMetadataOnly && !resetAuthor {
+		t = headMeta.Time()
+	}
+
+	if amendMetadataOnly {
+		ws, err := dEnv.WorkingSet(ctx)
+		if err != nil {
+			return HandleVErrAndExitCode(errhand.BuildDError("Couldn't get working set").AddCause(err).Build(), usage), false
+		}
+		if ws.MergeActive() {
+			return HandleVErrAndExitCode(errhand.BuildDError("error: cannot use --amend-metadata-only while a merge is in progress").Build(), usage), false
+		}
+	}
+
+	if apr.Contains(cli.NoMergesFlag) {
+		ws, err := dEnv.WorkingSet(ctx)
+		if err != nil {
+			return HandleVErrAndExitCode(errhand.BuildDError("Couldn't get working set").AddCause(err).Build(), usage), false
+		}
+		if ws.MergeActive() || (amend && headCommit.NumParents() > 1) {
+			return HandleVErrAndExitCode(errhand.BuildDError("error: cannot create a merge commit with --%s; rebase instead", cli.NoMergesFlag).Build(), usage), false
+		}
 	}
 
 	var parentsHeadForAmend []*doltdb.Commit
-	if apr.Contains(cli.AmendFlag) {
+	if amend {
 		numParentsHeadForAmend := headCommit.NumParents()
 		for i := 0; i < numParentsHeadForAmend; i++ {
 			parentCommit, err := headCommit.GetParent(ctx, i)
@@ -206,21 +411,28 @@ func performCommit(ctx context.Context, commandStr string, args []string, dEnv *
 	var mergeParentCommits []*doltdb.Commit
 	if ws.MergeActive() {
 		mergeParentCommits = []*doltdb.Commit{ws.MergeState().Commit()}
-	} else if apr.Contains(cli.AmendFlag) && len(parentsHeadForAmend) > 1 {
+	} else if amend && len(parentsHeadForAmend) > 1 {
 		mergeParentCommits = parentsHeadForAmend
 	}
 
+	stagedRoot, workingRoot := roots.Staged, roots.Working
+	if amendMetadataOnly {
+		// Commit exactly the tree of the existing HEAD commit, ignoring any staged or working changes
+		roots.Staged, roots.Working = roots.Head, roots.Head
+	}
+
 	pendingCommit, err := actions.GetCommitStaged(ctx, roots, ws, mergeParentCommits, dEnv.DbData().Ddb, actions.CommitStagedProps{
 		Message:    msg,
 		Date:       t,
-		AllowEmpty: apr.Contains(cli.AllowEmptyFlag) || apr.Contains(cli.AmendFlag),
+		AllowEmpty: apr.Contains(cli.AllowEmptyFlag) || amend,
 		SkipEmpty:  apr.Contains(cli.SkipEmptyFlag),
 		Force:      apr.Contains(cli.ForceFlag),
 		Name:       name,
 		Email:      email,
+		Metadata:   metadata,
 	})
 	if err != nil {
-		if apr.Contains(cli.AmendFlag) {
+		if amend {
 			newRoots, errRes := actions.ResetSoftToRef(ctx, dEnv.DbData(), headHash.String())
 			if errRes != nil {
 				return handleResetError(errRes, usage), false
@@ -240,6 +452,11 @@ func performCommit(ctx context.Context, commandStr string, args []string, dEnv *
 		return 0, true
 	}
 
+	if amendMetadataOnly {
+		// The commit's tree is already fixed, so leave the staged and working roots as they were
+		pendingCommit.Roots.Staged, pendingCommit.Roots.Working = stagedRoot, workingRoot
+	}
+
 	headRef, err := dEnv.RepoStateReader().CWBHeadRef()
 	if err != nil {
 		return handleCommitErr(ctx, dEnv, err, usage), false
@@ -255,7 +472,7 @@ func performCommit(ctx context.Context, commandStr string, args []string, dEnv *
 		nil,
 	)
 	if err != nil {
-		if apr.Contains(cli.AmendFlag) {
+		if amend {
 			newRoots, errRes := actions.ResetSoftToRef(ctx, dEnv.DbData(), headHash.String())
 			if errRes != nil {
 				return handleResetError(errRes, usage), false
@@ -300,7 +517,7 @@ func handleCommitErr(ctx context.Context, dEnv *env.DoltEnv, err error, usage cl
 
 	if actions.IsNothingStaged(err) {
 		notStagedTbls := actions.NothingStagedTblDiffs(err)
-		n, newErr := PrintDiffsNotStaged(ctx, dEnv, cli.CliOut, notStagedTbls, false, false, 0, merge.ArtifactStatus{})
+		n, newErr := PrintDiffsNotStaged(ctx, dEnv, cli.CliOut, notStagedTbls, false, HideIgnoredTables, 0, merge.ArtifactStatus{})
 		if newErr != nil {
 			bdr := errhand.BuildDError(`No changes added to commit (use "dolt add")\nCould not print diff because of additional error`)
 			bdr.AddCause(newErr)
@@ -398,8 +615,11 @@ func buildInitalCommitMsg(ctx context.Context, dEnv *env.DoltEnv, suggestedMsg s
 	}
 
 	buf := bytes.NewBuffer([]byte{})
-	n := printStagedDiffs(buf, stagedTblDiffs, true)
-	n, err = PrintDiffsNotStaged(ctx, dEnv, buf, notStagedTblDiffs, true, false, n, as)
+	n, err := printStagedDiffs(ctx, buf, stagedTblDiffs, true, "", nil)
+	if err != nil {
+		return "", err
+	}
+	n, err = PrintDiffsNotStaged(ctx, dEnv, buf, notStagedTblDiffs, true, HideIgnoredTables, n, as)
 	if err != nil {
 		return "", err
 	}
@@ -434,15 +654,47 @@ func parseCommitMessage(cm string) string {
 	return strings.Join(filtered, "\n")
 }
 
+// IgnoredTablesMode controls how PrintDiffsNotStaged reports untracked tables which are ignored by dolt_ignore.
+type IgnoredTablesMode int
+
+const (
+	// HideIgnoredTables leaves ignored tables out entirely.
+	HideIgnoredTables IgnoredTablesMode = iota
+	// ShowIgnoredTables lists ignored tables in a section of their own.
+	ShowIgnoredTables
+	// ShowIgnoredTablesWithRules lists ignored tables like ShowIgnoredTables, and annotates both ignored and untracked
+	// tables with the dolt_ignore pattern which decided whether they are ignored.
+	ShowIgnoredTablesWithRules
+)
+
 func PrintDiffsNotStaged(
 	ctx context.Context,
 	dEnv *env.DoltEnv,
 	wr io.Writer,
 	notStagedTbls []diff.TableDelta,
 	printHelp bool,
-	printIgnored bool,
+	ignoredMode IgnoredTablesMode,
 	linesPrinted int,
 	as merge.ArtifactStatus,
+) (int, error) {
+	return printDiffsNotStaged(ctx, dEnv, wr, notStagedTbls, printHelp, ignoredMode, linesPrinted, as, true, "", nil)
+}
+
+// printDiffsNotStaged is PrintDiffsNotStaged, but only prints the tables in |as| if |printUnmerged| is true. They're
+// left out of the other sections either way. Table names are printed relative to the namespace |relative|, if any,
+// and the lines of modified tables are followed by their entry in |annotations|, if any.
+func printDiffsNotStaged(
+	ctx context.Context,
+	dEnv *env.DoltEnv,
+	wr io.Writer,
+	notStagedTbls []diff.TableDelta,
+	printHelp bool,
+	ignoredMode IgnoredTablesMode,
+	linesPrinted int,
+	as merge.ArtifactStatus,
+	printUnmerged bool,
+	relative string,
+	annotations map[string]string,
 ) (int, error) {
 	roots, err := dEnv.Roots(ctx)
 	if err != nil {
@@ -453,7 +705,7 @@ func PrintDiffsNotStaged(
 	inCnfSet.Add(as.SchemaConflictsTables...)
 	violationSet := set.NewStrSet(as.ConstraintViolationsTables)
 
-	if as.HasConflicts() || as.HasConstraintViolations() {
+	if printUnmerged && (as.HasConflicts() || as.HasConstraintViolations()) {
 		if linesPrinted > 0 {
 			cli.Println()
 		}
@@ -465,10 +717,10 @@ func PrintDiffsNotStaged(
 		if as.HasConflicts() {
 			lines := make([]string, 0, len(notStagedTbls))
 			for _, tblName := range as.SchemaConflictsTables {
-				lines = append(lines, fmt.Sprintf(statusFmt, schemaConflictLabel, tblName))
+				lines = append(lines, fmt.Sprintf(statusFmt, schemaConflictLabel, relativeTableName(tblName, relative)))
 			}
 			for _, tblName := range as.DataConflictTables {
-				lines = append(lines, fmt.Sprintf(statusFmt, bothModifiedLabel, tblName))
+				lines = append(lines, fmt.Sprintf(statusFmt, bothModifiedLabel, relativeTableName(tblName, relative)))
 			}
 			iohelp.WriteLine(wr, color.RedString(strings.Join(lines, "\n")))
 			linesPrinted += len(lines)
@@ -478,7 +730,7 @@ func PrintDiffsNotStaged(
 			violationOnly, _, _ := violationSet.LeftIntersectionRight(inCnfSet)
 			lines := make([]string, 0, len(notStagedTbls))
 			for _, tblName := range violationOnly.AsSortedSlice() {
-				lines = append(lines, fmt.Sprintf(statusFmt, "modified", tblName))
+				lines = append(lines, fmt.Sprintf(statusFmt, "modified", relativeTableName(tblName, relative)))
 			}
 			iohelp.WriteLine(wr, color.RedString(strings.Join(lines, "\n")))
 			linesPrinted += len(lines)
@@ -488,9 +740,14 @@ func PrintDiffsNotStaged(
 	added := 0
 	removeModified := 0
 	for _, td := range notStagedTbls {
+		renamedAndModified, err := td.IsRenameAndModify()
+		if err != nil {
+			return 0, err
+		}
+
 		if td.IsAdd() {
 			added++
-		} else if td.IsRename() {
+		} else if td.IsRename() && !renamedAndModified {
 			added++
 			removeModified++
 		} else {
@@ -511,7 +768,10 @@ func PrintDiffsNotStaged(
 			iohelp.WriteLine(wr, workingHeaderHelp)
 		}
 
-		lines := getModifiedAndRemovedNotStaged(notStagedTbls, inCnfSet, violationSet)
+		lines, err := getModifiedAndRemovedNotStaged(ctx, notStagedTbls, inCnfSet, violationSet, relative, annotations)
+		if err != nil {
+			return 0, err
+		}
 
 		iohelp.WriteLine(wr, color.RedString(strings.Join(lines, "\n")))
 		linesPrinted += len(lines)
@@ -528,21 +788,23 @@ func PrintDiffsNotStaged(
 			iohelp.WriteLine(wr, untrackedHeaderHelp)
 		}
 
-		addedNotStagedTables := getAddedNotStagedTables(notStagedTbls)
-		filteredTables, err := doltdb.FilterIgnoredTables(ctx, addedNotStagedTables, roots)
-		if err != nil && doltdb.AsDoltIgnoreInConflict(err) == nil {
+		filteredTables, err := filterIgnoredNotStagedTables(ctx, notStagedTbls, roots)
+		if err != nil {
 			return 0, err
 		}
 
 		lines := make([]string, len(filteredTables.DontIgnore))
 		for i, tableName := range filteredTables.DontIgnore {
-			lines[i] = fmt.Sprintf(statusFmt, tblDiffTypeToLabel[diff.AddedTable], tableName)
+			lines[i] = fmt.Sprintf(statusFmt, tblDiffTypeToLabel[diff.AddedTable], relativeTableName(tableName, relative))
+			if rule, ok := filteredTables.Rules[tableName]; ok && ignoredMode == ShowIgnoredTablesWithRules {
+				lines[i] += fmt.Sprintf(" (not ignored by pattern '%s')", rule.Pattern)
+			}
 		}
 
 		iohelp.WriteLine(wr, color.RedString(strings.Join(lines, "\n")))
 		linesPrinted += len(lines)
 
-		if printIgnored && len(filteredTables.Ignore) > 0 {
+		if ignoredMode != HideIgnoredTables && len(filteredTables.Ignore) > 0 {
 			if linesPrinted > 0 {
 				cli.Println()
 			}
@@ -555,7 +817,10 @@ func PrintDiffsNotStaged(
 
 			lines := make([]string, len(filteredTables.Ignore))
 			for i, tableName := range filteredTables.Ignore {
-				lines[i] = fmt.Sprintf(statusFmt, tblDiffTypeToLabel[diff.AddedTable], tableName)
+				lines[i] = fmt.Sprintf(statusFmt, tblDiffTypeToLabel[diff.AddedTable], relativeTableName(tableName, relative))
+				if rule, ok := filteredTables.Rules[tableName]; ok && ignoredMode == ShowIgnoredTablesWithRules {
+					lines[i] += fmt.Sprintf(" (ignored by pattern '%s')", rule.Pattern)
+				}
 			}
 
 			iohelp.WriteLine(wr, color.RedString(strings.Join(lines, "\n")))
@@ -575,7 +840,7 @@ func PrintDiffsNotStaged(
 
 			lines := make([]string, len(filteredTables.Conflicts))
 			for i, conflict := range filteredTables.Conflicts {
-				lines[i] = fmt.Sprintf(statusFmt, tblDiffTypeToLabel[diff.AddedTable], conflict.Table)
+				lines[i] = fmt.Sprintf(statusFmt, tblDiffTypeToLabel[diff.AddedTable], relativeTableName(conflict.Table, relative))
 			}
 
 			iohelp.WriteLine(wr, color.RedString(strings.Join(lines, "\n")))
@@ -583,35 +848,114 @@ func PrintDiffsNotStaged(
 		}
 	}
 
+	warnings, err := trackedIgnoredTableWarnings(ctx, roots, relative)
+	if err != nil {
+		return 0, err
+	}
+	if len(warnings) > 0 {
+		// warnings aren't changes, so they aren't counted as lines printed, and a clean working set is still reported
+		// as such
+		if linesPrinted > 0 {
+			cli.Println()
+		}
+		iohelp.WriteLine(wr, color.YellowString(strings.Join(warnings, "\n")))
+	}
+
 	return linesPrinted, nil
 }
 
-func getModifiedAndRemovedNotStaged(notStagedTbls []diff.TableDelta, inCnfSet, violationSet *set.StrSet) (lines []string) {
+// trackedIgnoredTableWarnings returns a warning for each tracked table in the namespace |relative|, i.e. each table in
+// the staged root, which is ignored by a dolt_ignore pattern. Patterns only apply to untracked tables, so the pattern
+// has no effect on it, which is likely a mistake.
+func trackedIgnoredTableWarnings(ctx context.Context, roots doltdb.Roots, relative string) ([]string, error) {
+	patterns, err := doltdb.GetIgnoredTablePatterns(ctx, roots)
+	if err != nil || len(patterns) == 0 {
+		return nil, err
+	}
+
+	tableNames, err := roots.Staged.GetTableNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []string
+	for _, tableName := range tableNames {
+		if doltdb.HasDoltPrefix(tableName) || !strings.HasPrefix(tableName, relative) {
+			continue
+		}
+		ignored, rule, err := patterns.IgnoreRuleForTableName(tableName)
+		if doltdb.AsDoltIgnoreInConflict(err) != nil {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if ignored == doltdb.Ignore && rule != nil {
+			warnings = append(warnings, fmt.Sprintf(trackedIgnoredWarningFmt, tableName, rule.Pattern))
+		}
+	}
+	return warnings, nil
+}
+
+func getModifiedAndRemovedNotStaged(ctx context.Context, notStagedTbls []diff.TableDelta, inCnfSet, violationSet *set.StrSet, relative string, annotations map[string]string) (lines []string, err error) {
 	lines = make([]string, 0, len(notStagedTbls))
 	for _, td := range notStagedTbls {
 		if td.IsAdd() || inCnfSet.Contains(td.CurName()) || violationSet.Contains(td.CurName()) {
 			continue
 		}
+		renamedAndModified, err := td.IsRenameAndModify()
+		if err != nil {
+			return nil, err
+		}
+
 		if td.IsDrop() {
-			lines = append(lines, fmt.Sprintf(statusFmt, tblDiffTypeToLabel[diff.RemovedTable], td.CurName()))
+			lines = append(lines, fmt.Sprintf(statusFmt, tblDiffTypeToLabel[diff.RemovedTable], relativeTableName(td.CurName(), relative)))
+		} else if renamedAndModified {
+			// a table that was renamed and also changed is a single change, so it gets a single line
+			lines = append(lines, fmt.Sprintf(statusRenameFmt, tblDiffTypeToLabel[diff.RenamedModifiedTable], relativeTableName(td.FromName, relative), relativeTableName(td.ToName, relative))+annotations[td.CurName()])
 		} else if td.IsRename() {
 			// per Git, unstaged renames are shown as drop + add
-			lines = append(lines, fmt.Sprintf(statusFmt, tblDiffTypeToLabel[diff.RemovedTable], td.FromName))
+			lines = append(lines, fmt.Sprintf(statusFmt, tblDiffTypeToLabel[diff.RemovedTable], relativeTableName(td.FromName, relative)))
 		} else {
-			lines = append(lines, fmt.Sprintf(statusFmt, tblDiffTypeToLabel[diff.ModifiedTable], td.CurName()))
+			label, err := modifiedTableLabel(ctx, td)
+			if err != nil {
+				return nil, err
+			}
+			lines = append(lines, fmt.Sprintf(statusFmt, label, relativeTableName(td.CurName(), relative))+annotations[td.CurName()])
 		}
 	}
-	return lines
+	return lines, nil
 }
 
-func getAddedNotStagedTables(notStagedTbls []diff.TableDelta) (tables []string) {
+// filterIgnoredNotStagedTables divides the untracked tables in |notStagedTbls| by whether they're ignored by
+// dolt_ignore, or match conflicting patterns.
+func filterIgnoredNotStagedTables(ctx context.Context, notStagedTbls []diff.TableDelta, roots doltdb.Roots) (doltdb.IgnoredTables, error) {
+	addedNotStagedTables, err := getAddedNotStagedTables(notStagedTbls)
+	if err != nil {
+		return doltdb.IgnoredTables{}, err
+	}
+	filteredTables, err := doltdb.FilterIgnoredTables(ctx, addedNotStagedTables, roots)
+	if err != nil && doltdb.AsDoltIgnoreInConflict(err) == nil {
+		return doltdb.IgnoredTables{}, err
+	}
+	return filteredTables, nil
+}
+
+func getAddedNotStagedTables(notStagedTbls []diff.TableDelta) (tables []string, err error) {
 	tables = make([]string, 0, len(notStagedTbls))
 	for _, td := range notStagedTbls {
-		if td.IsAdd() || td.IsRename() {
+		if td.IsAdd() {
 			tables = append(tables, td.CurName())
+		} else if td.IsRename() {
+			renamedAndModified, err := td.IsRenameAndModify()
+			if err != nil {
+				return nil, err
+			}
+			if !renamedAndModified {
+				tables = append(tables, td.CurName())
+			}
 		}
 	}
-	return tables
+	return tables, nil
 }
 
 const (
@@ -619,8 +963,8 @@ const (
 	stagedHeader     = `Changes to be committed:`
 	stagedHeaderHelp = `  (use "dolt reset <table>..." to unstage)`
 
-	unmergedTablesHeader = `You have unmerged tables.
-  (fix %s and run "dolt commit")
+	unmergedTablesHeader = `You have unmerged tables.`
+	unmergedTablesHelp   = `  (fix %s and run "dolt commit")
   (use "dolt merge --abort" to abort the merge)
 `
 
@@ -643,20 +987,42 @@ const (
 	conflictedIgnoredHeader     = `Tables with conflicting dolt_ignore patterns:`
 	conflictedIgnoredHeaderHelp = `  (use "dolt add -f <table>" to include in what will be committed)`
 
-	statusFmt           = "\t%-18s%s"
-	statusRenameFmt     = "\t%-18s%s -> %s"
-	schemaConflictLabel = "schema conflict:"
-	bothModifiedLabel   = "both modified:"
+	specialTablesHeader = `Special tables with changes:`
+
+	trackedIgnoredWarningFmt = `warning: tracked table %s matches the dolt_ignore pattern '%s', which has no effect on tables already tracked`
+
+	// labels are padded to line up the table names, but longer ones are still followed by a space
+	statusFmt             = "\t%-17s %s"
+	statusRenameFmt       = "\t%-17s %s -> %s"
+	schemaConflictLabel   = "schema conflict:"
+	bothModifiedLabel     = "both modified:"
+	collationChangedLabel = "collation-changed:"
 )
 
 var tblDiffTypeToLabel = map[diff.TableDiffType]string{
-	diff.ModifiedTable: "modified:",
-	diff.RenamedTable:  "renamed:",
-	diff.RemovedTable:  "deleted:",
-	diff.AddedTable:    "new table:",
+	diff.ModifiedTable:        "modified:",
+	diff.RenamedTable:         "renamed:",
+	diff.RemovedTable:         "deleted:",
+	diff.AddedTable:           "new table:",
+	diff.RenamedModifiedTable: "renamed+modified:",
+}
+
+// modifiedTableLabel returns the label of the table modified by |td|. A table whose collation or character set is all
+// that changed gets its own label, since such a change is easily made by accident and otherwise hard to spot.
+func modifiedTableLabel(ctx context.Context, td diff.TableDelta) (string, error) {
+	onlyCollation, err := td.HasOnlyCollationChanged(ctx)
+	if err != nil {
+		return "", err
+	}
+	if onlyCollation {
+		return collationChangedLabel, nil
+	}
+	return tblDiffTypeToLabel[diff.ModifiedTable], nil
 }
 
-func printStagedDiffs(wr io.Writer, stagedTbls []diff.TableDelta, printHelp bool) int {
+// printStagedDiffs prints the changes staged for commit, with table names relative to the namespace |relative|, if any.
+// The line of each table is followed by its entry in |annotations|, if any.
+func printStagedDiffs(ctx context.Context, wr io.Writer, stagedTbls []diff.TableDelta, printHelp bool, relative string, annotations map[string]string) (int, error) {
 	if len(stagedTbls) > 0 {
 		iohelp.WriteLine(wr, stagedHeader)
 
@@ -668,20 +1034,32 @@ func printStagedDiffs(wr io.Writer, stagedTbls []diff.TableDelta, printHelp bool
 		for _, td := range stagedTbls {
 			if !doltdb.IsReadOnlySystemTable(td.CurName()) {
 				if td.IsAdd() {
-					lines = append(lines, fmt.Sprintf(statusFmt, tblDiffTypeToLabel[diff.AddedTable], td.CurName()))
+					lines = append(lines, fmt.Sprintf(statusFmt, tblDiffTypeToLabel[diff.AddedTable], relativeTableName(td.CurName(), relative))+annotations[td.CurName()])
 				} else if td.IsDrop() {
-					lines = append(lines, fmt.Sprintf(statusFmt, tblDiffTypeToLabel[diff.RemovedTable], td.CurName()))
+					lines = append(lines, fmt.Sprintf(statusFmt, tblDiffTypeToLabel[diff.RemovedTable], relativeTableName(td.CurName(), relative))+annotations[td.CurName()])
 				} else if td.IsRename() {
-					lines = append(lines, fmt.Sprintf(statusRenameFmt, tblDiffTypeToLabel[diff.RenamedTable], td.FromName, td.ToName))
+					renamedAndModified, err := td.IsRenameAndModify()
+					if err != nil {
+						return 0, err
+					}
+					diffType := diff.RenamedTable
+					if renamedAndModified {
+						diffType = diff.RenamedModifiedTable
+					}
+					lines = append(lines, fmt.Sprintf(statusRenameFmt, tblDiffTypeToLabel[diffType], relativeTableName(td.FromName, relative), relativeTableName(td.ToName, relative))+annotations[td.CurName()])
 				} else {
-					lines = append(lines, fmt.Sprintf(statusFmt, tblDiffTypeToLabel[diff.ModifiedTable], td.CurName()))
+					label, err := modifiedTableLabel(ctx, td)
+					if err != nil {
+						return 0, err
+					}
+					lines = append(lines, fmt.Sprintf(statusFmt, label, relativeTableName(td.CurName(), relative))+annotations[td.CurName()])
 				}
 
 			}
 		}
 		iohelp.WriteLine(wr, color.GreenString(strings.Join(lines, "\n")))
-		return len(stagedTbls)
+		return len(lines), nil
 	}
 
-	return 0
+	return 0, nil
 }
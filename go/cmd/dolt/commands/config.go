@@ -68,6 +68,8 @@ Valid configuration variables:
 	- remotes.default_port - sets default port for authenticating with doltremoteapi.
 
 	- push.autoSetupRemote - if set to "true" assume --set-upstream on default push when no upstream tracking exists for the current branch.
+
+	- remote.pushDefault - the remote that changes are pushed to, for workflows which fetch from one remote and push to another. 'dolt status' reports the current branch's relation to both. 'dolt push' doesn't use it, so the remote must be given when pushing.
 `,
 
 	Synopsis: []string{
@@ -34,6 +34,7 @@ import (
 	dsqle "github.com/dolthub/dolt/go/libraries/doltcore/sqle"
 	dblr "github.com/dolthub/dolt/go/libraries/doltcore/sqle/binlogreplication"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/cluster"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dprocedures"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/mysql_file_handler"
 	"github.com/dolthub/dolt/go/libraries/utils/config"
@@ -172,6 +173,12 @@ func NewSqlEngine(
 		return nil, err
 	}
 
+	// dolt_commit calls the procedure set with @@<db>_pre_commit_procedure with this engine
+	dprocedures.SetQueryRunner(func(ctx *sql.Context, query string) (sql.RowIter, error) {
+		_, iter, err := engine.Query(ctx, query)
+		return iter, err
+	})
+
 	sessionFactory := doltSessionFactory(pro, mrEnv.Config(), bcController, config.Autocommit)
 
 	if config.BinlogReplicaController != nil {
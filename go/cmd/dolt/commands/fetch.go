@@ -17,6 +17,8 @@ package commands
 import (
 	"context"
 
+	"github.com/fatih/color"
+
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
 	eventsapi "github.com/dolthub/dolt/go/gen/proto/dolt/services/eventsapi/v1alpha1"
@@ -25,6 +27,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
 	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/store/datas"
 )
 
 var fetchDocs = cli.CommandDocumentationContent{
@@ -89,9 +92,29 @@ func (cmd FetchCmd) Exec(ctx context.Context, commandStr string, args []string,
 		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
 	}
 
-	err = actions.FetchRefSpecs(ctx, dEnv.DbData(), srcDB, refSpecs, r, ref.UpdateMode{Force: true}, buildProgStarter(downloadLanguage), stopProgFuncs)
+	updates, err := actions.FetchRefSpecs(ctx, dEnv.DbData(), srcDB, refSpecs, r, ref.UpdateMode{Force: true}, buildProgStarter(downloadLanguage), stopProgFuncs)
 	if err != nil && err != doltdb.ErrUpToDate {
 		return HandleVErrAndExitCode(errhand.VerboseErrorFromError(err), usage)
 	}
-	return HandleVErrAndExitCode(nil, usage)
+	recordTrackingRefUpdates(dEnv, "fetch", updates...)
+	return 0
+}
+
+// recordTrackingRefUpdates records the |updates| to remote-tracking refs made by the operation |op| in the reflog, so
+// that status can tell when a remote branch was rewritten. The refs have already been updated by then, so a failure to
+// write the reflog is only printed as a warning.
+func recordTrackingRefUpdates(dEnv *env.DoltEnv, op string, updates ...actions.TrackingRefUpdate) {
+	name := dEnv.Config.GetStringOrDefault(env.UserNameKey, "")
+	email := dEnv.Config.GetStringOrDefault(env.UserEmailKey, "")
+	now := datas.CommitNowFunc()
+	for _, u := range updates {
+		if u.From == u.To {
+			continue
+		}
+		err := env.AppendReflogEntry(dEnv.FS, u.ReflogEntry(op, name, email, now))
+		if err != nil {
+			cli.PrintErrln(color.YellowString("warning: the %s succeeded, but it couldn't be recorded in the reflog: %s", op, err.Error()))
+			return
+		}
+	}
 }
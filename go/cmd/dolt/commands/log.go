@@ -41,6 +41,7 @@ type logOpts struct {
 	minParents           int
 	decoration           string
 	oneLine              bool
+	abbrev               int
 	excludingCommitSpecs []*doltdb.CommitSpec
 	commitSpecs          []*doltdb.CommitSpec
 	tableName            string
@@ -154,6 +155,7 @@ func parseLogArgs(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgPars
 		minParents:  minParents,
 		oneLine:     apr.Contains(cli.OneLineFlag),
 		decoration:  decorateOption,
+		abbrev:      apr.GetIntOrDefault(cli.AbbrevParam, 0),
 	}
 
 	err := opts.parseRefsAndTable(ctx, apr, dEnv)
@@ -576,10 +578,10 @@ func logCompact(pager *outputpager.Pager, opts *logOpts, commits []logNode) {
 			return
 		}
 
-		chStr := comm.commitHash.String()
+		chStr := cli.AbbreviateHash(comm.commitHash.String(), opts.abbrev)
 		if opts.showParents {
 			for _, h := range comm.parentHashes {
-				chStr += " " + h.String()
+				chStr += " " + cli.AbbreviateHash(h.String(), opts.abbrev)
 			}
 		}
 
@@ -596,15 +598,17 @@ func logCompact(pager *outputpager.Pager, opts *logOpts, commits []logNode) {
 	}
 }
 
-func PrintCommit(pager *outputpager.Pager, minParents int, showParents bool, decoration string, comm logNode) {
+// PrintCommit prints |comm| in the default format of dolt log. Hashes are abbreviated to |abbrev| characters, or
+// printed in full if |abbrev| is 0.
+func PrintCommit(pager *outputpager.Pager, minParents int, showParents bool, decoration string, abbrev int, comm logNode) {
 	if len(comm.parentHashes) < minParents {
 		return
 	}
 
-	chStr := comm.commitHash.String()
+	chStr := cli.AbbreviateHash(comm.commitHash.String(), abbrev)
 	if showParents {
 		for _, h := range comm.parentHashes {
-			chStr += " " + h.String()
+			chStr += " " + cli.AbbreviateHash(h.String(), abbrev)
 		}
 	}
 
@@ -619,7 +623,7 @@ func PrintCommit(pager *outputpager.Pager, minParents int, showParents bool, dec
 	if len(comm.parentHashes) > 1 {
 		pager.Writer.Write([]byte(fmt.Sprintf("\nMerge:")))
 		for _, h := range comm.parentHashes {
-			pager.Writer.Write([]byte(fmt.Sprintf(" " + h.String())))
+			pager.Writer.Write([]byte(fmt.Sprintf(" " + cli.AbbreviateHash(h.String(), abbrev))))
 		}
 	}
 
@@ -634,7 +638,7 @@ func PrintCommit(pager *outputpager.Pager, minParents int, showParents bool, dec
 
 func logDefault(pager *outputpager.Pager, opts *logOpts, commits []logNode) {
 	for _, comm := range commits {
-		PrintCommit(pager, opts.minParents, opts.showParents, opts.decoration, comm)
+		PrintCommit(pager, opts.minParents, opts.showParents, opts.decoration, opts.abbrev, comm)
 	}
 }
 
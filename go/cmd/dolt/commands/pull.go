@@ -149,10 +149,11 @@ func pullHelper(ctx context.Context, dEnv *env.DoltEnv, pullSpec *env.PullSpec)
 				return err
 			}
 
-			err = dEnv.DoltDB.FastForward(ctx, remoteTrackRef, srcDBCommit)
+			update, err := actions.FastForwardTrackingRef(ctx, dEnv.DoltDB, remoteTrackRef, srcDBCommit)
 			if err != nil {
 				return fmt.Errorf("fetch failed; %w", err)
 			}
+			recordTrackingRefUpdates(dEnv, "pull", update)
 
 			// Merge iff branch is current branch and there is an upstream set (pullSpec.Branch is set to nil if there is no upstream)
 			if branchRef != pullSpec.Branch {
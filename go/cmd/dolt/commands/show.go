@@ -305,7 +305,7 @@ func showCommit(ctx context.Context, dEnv *env.DoltEnv, opts *showOpts, comm *do
 		pager := outputpager.Start()
 		defer pager.Stop()
 
-		PrintCommit(pager, 0, opts.showParents, opts.decoration, logNode{
+		PrintCommit(pager, 0, opts.showParents, opts.decoration, 0, logNode{
 			commitMeta:   meta,
 			commitHash:   cmHash,
 			parentHashes: pHashes,
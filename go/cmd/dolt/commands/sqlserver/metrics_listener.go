@@ -24,6 +24,7 @@ import (
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/cluster"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/clusterdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dprocedures"
 	"github.com/dolthub/dolt/go/libraries/utils/version"
 )
 
@@ -126,6 +127,9 @@ func newMetricsListener(labels prometheus.Labels, versionStr string, clusterStat
 	prometheus.MustRegister(ml.histQueryDur)
 	prometheus.MustRegister(ml.replicationLagGauges)
 	prometheus.MustRegister(ml.isReplicaGauges)
+	for _, c := range dprocedures.CommitMetricsCollectors() {
+		prometheus.MustRegister(c)
+	}
 
 	go func() {
 		for ml.updateReplMetrics() {
@@ -207,6 +211,9 @@ func (ml *metricsListener) Close() {
 	prometheus.Unregister(ml.gaugeConcurrentConn)
 	prometheus.Unregister(ml.gaugeConcurrentQueries)
 	prometheus.Unregister(ml.histQueryDur)
+	for _, c := range dprocedures.CommitMetricsCollectors() {
+		prometheus.Unregister(c)
+	}
 
 	ml.closeReplicationMetrics()
 }
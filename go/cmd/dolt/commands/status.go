@@ -16,26 +16,64 @@ package commands
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"io"
+	"strings"
+	"time"
 
-	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
-	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
-	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/fatih/color"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/cmd/dolt/errhand"
 	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
 	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/libraries/utils/iohelp"
+	"github.com/dolthub/dolt/go/libraries/utils/set"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+const (
+	statusTypeParam      = "type"
+	statusAgainstParam   = "against"
+	statusShowHashesFlag = "show-hashes"
+	statusStagedFlag     = "staged"
+	statusUnstagedFlag   = "unstaged"
+	statusUpstreamTime   = "show-upstream-time"
+	statusGraphFlag      = "graph"
+	statusSinceParam     = "since"
+	statusUntilParam     = "until"
+	statusSchemaFlag     = "schema"
+	statusAheadOnlyFlag  = "ahead-only"
+	statusBehindOnlyFlag = "behind-only"
+	statusNoTrackingFlag = "no-tracking"
+	statusWorktreesFlag  = "worktrees"
+	statusNameOnlyFlag   = "name-only"
+	statusLogUnpushed    = "log-unpushed"
+	statusRelativeParam  = "relative"
+
+	statusIgnoreAutoIncFlag = "ignore-autoincrement"
+	statusWatchFlag         = "watch"
+	statusFormatParam       = "format"
+	statusFormatOnceFlag    = "format-once"
+	statusStorageFlag       = "storage"
+	statusFailOnIgnoredFlag = "fail-on-ignored"
+	statusWorktreeVsIndex   = "worktree-vs-index"
+	statusSchemaAgainst     = "schema-against"
+	statusFastFlag          = "fast"
+	statusIndexWorkFlag     = "show-index-work"
+	statusConflictsOnlyFlag = "conflicts-only"
+	statusDebugTimingFlag   = "debug-timing"
 )
 
 var statusDocs = cli.CommandDocumentationContent{
 	ShortDesc: "Show the working status",
-	LongDesc:  `Displays working tables that differ from the current HEAD commit, tables that differ from the staged tables, and tables that are in the working tree that are not tracked by dolt. The first are what you would commit by running {{.EmphasisLeft}}dolt commit{{.EmphasisRight}}; the second and third are what you could commit by running {{.EmphasisLeft}}dolt add .{{.EmphasisRight}} before running {{.EmphasisLeft}}dolt commit{{.EmphasisRight}}.`,
-	Synopsis:  []string{""},
+	LongDesc: `Displays working tables that differ from the current HEAD commit, tables that differ from the staged tables, and tables that are in the working tree that are not tracked by dolt. The first are what you would commit by running {{.EmphasisLeft}}dolt commit{{.EmphasisRight}}; the second and third are what you could commit by running {{.EmphasisLeft}}dolt add .{{.EmphasisRight}} before running {{.EmphasisLeft}}dolt commit{{.EmphasisRight}}.
+
+Tables with merge conflicts or constraint violations are listed first, under {{.EmphasisLeft}}Unmerged paths{{.EmphasisRight}}, as they're found, ahead of the staged and unstaged changes.`,
+	Synopsis: []string{""},
 }
 
 type StatusCmd struct{}
@@ -57,221 +95,648 @@ func (cmd StatusCmd) Docs() *cli.CommandDocumentation {
 
 func (cmd StatusCmd) ArgParser() *argparser.ArgParser {
 	ap := argparser.NewArgParserWithMaxArgs(cmd.Name(), 0)
-	ap.SupportsFlag(cli.ShowIgnoredFlag, "", "Show tables that are ignored (according to dolt_ignore)")
+	ap.SupportsFlagWithOptionalValue(cli.ShowIgnoredFlag, "", "mode", "Show tables that are ignored (according to dolt_ignore). With {{.EmphasisLeft}}--ignored=matching{{.EmphasisRight}}, also show the dolt_ignore pattern which decided whether each untracked table is ignored.")
+	ap.SupportsString(statusTypeParam, "", "types", "Only show tables with the given comma-separated change types. Valid types are added, modified, deleted and conflicted.")
+	ap.SupportsString(statusAgainstParam, "", "commit", "Show staged changes relative to the given {{.LessThan}}commit{{.GreaterThan}} instead of HEAD.")
+	ap.SupportsString(statusRelativeParam, "", "namespace", "Only show tables whose names start with {{.LessThan}}namespace{{.GreaterThan}}, e.g. {{.EmphasisLeft}}tenant1_{{.EmphasisRight}}, and print their names without it.")
+	ap.SupportsFlag(statusShowHashesFlag, "", "Print the working set hash, the working and staged root hashes and the HEAD commit hash.")
+	ap.SupportsInt(cli.AbbrevParam, "", "n", "With {{.EmphasisLeft}}--show-hashes{{.EmphasisRight}}, print only the first n characters of each hash, or at least 4.")
+	ap.SupportsFlag(statusStagedFlag, "", "Only show changes staged for commit. Cannot be used with --unstaged.")
+	ap.SupportsFlag(statusUnstagedFlag, "", "Only show changes not staged for commit, including untracked tables. Cannot be used with --staged.")
+	ap.SupportsFlag(statusUpstreamTime, "", "When the branch is behind its upstream, show how long ago the upstream branch was last updated.")
+	ap.SupportsString(statusSinceParam, "", "time", "Only count commits made since {{.LessThan}}time{{.GreaterThan}} when comparing the branch with its upstream. Either a date, or a duration such as {{.EmphasisLeft}}24h{{.EmphasisRight}} before now.")
+	ap.SupportsString(statusUntilParam, "", "time", "Only count commits made until {{.LessThan}}time{{.GreaterThan}} when comparing the branch with its upstream. Either a date, or a duration such as {{.EmphasisLeft}}24h{{.EmphasisRight}} before now.")
+	ap.SupportsFlag(statusIgnoreAutoIncFlag, "", "Don't report tables as modified when their auto increment value is all that changed, e.g. after rows were inserted and deleted again.")
+	ap.SupportsFlag(statusSchemaFlag, "", "Summarize the columns added, dropped, renamed and retyped in each modified table.")
+	ap.SupportsFlag(statusStorageFlag, "", "Show an estimate of the storage each changed table has gained since HEAD, as the size of the chunks of its rows and indexes which aren't in HEAD, followed by the size of those in HEAD it no longer uses. Large TEXT and BLOB values aren't counted.")
+	ap.SupportsString(statusSchemaAgainst, "", "branch", "Also list the tables whose schema in the working set differs from their schema on {{.LessThan}}branch{{.GreaterThan}}, e.g. a branch keeping the canonical schema, regardless of any changes to their data. Tables missing on either side are listed too.")
+	ap.SupportsFlag(statusGraphFlag, "", "Show a graph of the most recent commits on the current branch and its upstream, and where they diverge.")
+	ap.SupportsFlag(statusLogUnpushed, "", "When the branch is ahead of its upstream, list the short hash and subject of each commit which hasn't been pushed.")
+	ap.SupportsFlag(statusNoTrackingFlag, "", "Don't compare the current branch with its upstream, and only report the state of the working set. Cannot be used with the other options about the upstream.")
+	ap.SupportsFlag(statusAheadOnlyFlag, "", "Print only the number of commits the current branch is ahead of its upstream. Exits with an error if the branch has no upstream. Cannot be used with --behind-only.")
+	ap.SupportsFlag(statusNameOnlyFlag, "", "Print only the names of the tables which can be added, one per line, with no headers or hints: modified, deleted and untracked tables by default, or the staged tables with {{.EmphasisLeft}}--staged{{.EmphasisRight}}. Ignored tables and tables with conflicts or constraint violations are left out.")
+	ap.SupportsFlag(statusWorktreesFlag, "", "List each branch with a working set, along with its HEAD commit and whether it has staged or unstaged changes, instead of the status of the current branch. The current branch is marked with {{.EmphasisLeft}}*{{.EmphasisRight}}.")
+	ap.SupportsString(statusFormatParam, "", "template", "Print the status using the Go template {{.LessThan}}template{{.GreaterThan}} instead of the usual output. The template is rendered once for each table, with the fields {{.EmphasisLeft}}.Name{{.EmphasisRight}}, {{.EmphasisLeft}}.OldName{{.EmphasisRight}}, {{.EmphasisLeft}}.Status{{.EmphasisRight}} and {{.EmphasisLeft}}.Staged{{.EmphasisRight}}.")
+	ap.SupportsFlag(statusFormatOnceFlag, "", "Render the {{.EmphasisLeft}}--format{{.EmphasisRight}} template once for the whole status rather than once per table, with the fields {{.EmphasisLeft}}.Branch{{.EmphasisRight}}, {{.EmphasisLeft}}.Upstream{{.EmphasisRight}}, {{.EmphasisLeft}}.Ahead{{.EmphasisRight}}, {{.EmphasisLeft}}.Behind{{.EmphasisRight}}, {{.EmphasisLeft}}.MergeActive{{.EmphasisRight}}, {{.EmphasisLeft}}.Clean{{.EmphasisRight}}, {{.EmphasisLeft}}.Tables{{.EmphasisRight}} and {{.EmphasisLeft}}.Changes{{.EmphasisRight}}, the number of tables with staged, unstaged or untracked changes.")
+	ap.SupportsFlag(statusWatchFlag, "", "Keep running, and print the status again, after clearing the terminal, whenever the working set, the staged tables or the current branch change. Stop with Ctrl-C. When the output isn't a terminal, the status is printed once.")
+	ap.SupportsFlag(statusFailOnIgnoredFlag, "", "Exit with an error, after printing the status, if there are changes to tables ignored by dolt_ignore, i.e. new tables which aren't tracked because they match an ignore pattern. Use with {{.EmphasisLeft}}--ignored{{.EmphasisRight}} to list them.")
+	ap.SupportsFlag(statusFastFlag, "", "Find the changed tables only by comparing their hashes, without loading their schemas or rows. This is faster for very large databases, but renamed tables are listed as deleted and new tables.")
+	ap.SupportsFlag(statusWorktreeVsIndex, "", "Only show the changes between the working set and the staging area, i.e. what {{.EmphasisLeft}}dolt add{{.EmphasisRight}} would pick up, without the branch header, the comparison with the upstream, the staged changes or the tables with conflicts.")
+	ap.SupportsFlag(statusIndexWorkFlag, "", "Annotate each modified table whose change builds secondary indexes from its rows, because they were added, redefined or the primary key changed, with the names of those indexes. Building them is a large part of the cost of committing the change for big tables.")
+	ap.SupportsFlag(statusConflictsOnlyFlag, "", "Print only the tables with conflicts or constraint violations, one per line, for tools resolving them: the table name, whether it has a schema conflict, and its numbers of data conflicts and constraint violations, separated by tabs. Exits with status 1, printing nothing, if there are none.")
+	ap.SupportsFlag(statusDebugTimingFlag, "", "Print how long computing the table changes, finding the merge artifacts and comparing the branch with its upstream each took to stderr, one line per step, e.g. to include in a report about a slow status.")
+	ap.SupportsFlag(statusBehindOnlyFlag, "", "Print only the number of commits the current branch is behind its upstream. Exits with an error if the branch has no upstream. Cannot be used with --ahead-only.")
 	return ap
 }
 
+// statusExclusiveArgs lists the dolt status options which can't be used together: each option |arg| and the options
+// which can't be used with it.
+var statusExclusiveArgs = []struct {
+	arg   string
+	other []string
+}{
+	{statusUnstagedFlag, []string{statusStagedFlag}},
+	{statusNoTrackingFlag, []string{statusUpstreamTime, statusSinceParam, statusUntilParam, statusGraphFlag, statusLogUnpushed, statusAheadOnlyFlag, statusBehindOnlyFlag}},
+	{statusFormatParam, []string{statusNameOnlyFlag, statusWorktreesFlag, statusAheadOnlyFlag, statusBehindOnlyFlag, statusShowHashesFlag, statusSchemaFlag, statusStorageFlag, statusSchemaAgainst, statusIndexWorkFlag, statusGraphFlag}},
+	{statusStorageFlag, []string{statusNameOnlyFlag}},
+	{statusSchemaAgainst, []string{statusNameOnlyFlag}},
+	{statusIndexWorkFlag, []string{statusNameOnlyFlag}},
+	{statusFailOnIgnoredFlag, []string{statusWorktreesFlag, statusAheadOnlyFlag, statusBehindOnlyFlag, statusWatchFlag}},
+	{statusFastFlag, []string{statusFormatParam, statusNameOnlyFlag, statusSchemaFlag, statusStorageFlag, statusSchemaAgainst, statusIgnoreAutoIncFlag, statusWorktreeVsIndex, statusIndexWorkFlag, statusGraphFlag, cli.ShowIgnoredFlag}},
+	{statusWorktreeVsIndex, []string{statusStagedFlag, statusAgainstParam, statusFormatParam, statusNameOnlyFlag, statusWorktreesFlag, statusAheadOnlyFlag, statusBehindOnlyFlag, statusShowHashesFlag, statusIndexWorkFlag, statusGraphFlag}},
+	{statusConflictsOnlyFlag, []string{statusStagedFlag, statusAgainstParam, statusFormatParam, statusNameOnlyFlag, statusWorktreesFlag, statusAheadOnlyFlag, statusBehindOnlyFlag, statusShowHashesFlag, statusSchemaFlag, statusStorageFlag, statusSchemaAgainst, statusIndexWorkFlag, statusFastFlag, statusWorktreeVsIndex, statusGraphFlag, statusWatchFlag}},
+	{statusWatchFlag, []string{statusWorktreesFlag, statusAheadOnlyFlag, statusBehindOnlyFlag}},
+}
+
+// checkStatusExclusiveArgs returns an error naming the first pair of options in |apr| which can't be used together,
+// according to statusExclusiveArgs.
+func checkStatusExclusiveArgs(apr *argparser.ArgParseResults) error {
+	for _, ex := range statusExclusiveArgs {
+		if !apr.Contains(ex.arg) {
+			continue
+		}
+		for _, other := range ex.other {
+			if apr.Contains(other) {
+				return fmt.Errorf("error: cannot use both --%s and --%s", other, ex.arg)
+			}
+		}
+	}
+	return nil
+}
+
 // Exec executes the command
 func (cmd StatusCmd) Exec(ctx context.Context, commandStr string, args []string, dEnv *env.DoltEnv, cliCtx cli.CliContext) int {
 	ap := cmd.ArgParser()
 	help, _ := cli.HelpAndUsagePrinters(cli.CommandDocsForCommandString(commandStr, statusDocs, ap))
 	apr := cli.ParseArgsOrDie(ap, args, help)
 
-	roots, err := dEnv.Roots(ctx)
-	if err != nil {
+	if err := checkStatusExclusiveArgs(apr); err != nil {
 		return handleStatusVErr(err)
 	}
+	if apr.Contains(statusFormatOnceFlag) && !apr.Contains(statusFormatParam) {
+		return handleStatusVErr(fmt.Errorf("error: --%s requires --%s", statusFormatOnceFlag, statusFormatParam))
+	}
 
-	staged, notStaged, err := diff.GetStagedUnstagedTableDeltas(ctx, roots)
-	if err != nil {
-		return handleStatusVErr(err)
+	filter := StatusFilter{
+		StagedOnly:   apr.Contains(statusStagedFlag),
+		UnstagedOnly: apr.Contains(statusUnstagedFlag),
+		Relative:     apr.GetValueOrDefault(statusRelativeParam, ""),
+	}
+	if typeStr, ok := apr.GetValue(statusTypeParam); ok {
+		var err error
+		filter.Types, err = parseStatusTypeFilter(typeStr)
+		if err != nil {
+			return handleStatusVErr(err)
+		}
 	}
 
-	ws, err := dEnv.WorkingSet(ctx)
-	if err != nil {
-		handleStatusVErr(err)
+	ignoredMode := HideIgnoredTables
+	if modeStr, ok := apr.GetValue(cli.ShowIgnoredFlag); ok {
+		switch modeStr {
+		case "":
+			ignoredMode = ShowIgnoredTables
+		case "matching":
+			ignoredMode = ShowIgnoredTablesWithRules
+		default:
+			return handleStatusVErr(fmt.Errorf("error: invalid value '%s' for --%s, expected 'matching'", modeStr, cli.ShowIgnoredFlag))
+		}
 	}
 
-	as, err := merge.GetMergeArtifactStatus(ctx, ws)
-	if err != nil {
-		handleStatusVErr(err)
+	tracking := TrackingOptions{
+		ShowUpstreamTime: apr.Contains(statusUpstreamTime),
+		Skip:             apr.Contains(statusNoTrackingFlag),
+		LogUnpushed:      apr.Contains(statusLogUnpushed),
+	}
+	now := time.Now()
+	if sinceStr, ok := apr.GetValue(statusSinceParam); ok {
+		var err error
+		tracking.Since, err = parseTrackingTime(sinceStr, now)
+		if err != nil {
+			return handleStatusVErr(fmt.Errorf("error: invalid value '%s' for --%s: %w", sinceStr, statusSinceParam, err))
+		}
+	}
+	if untilStr, ok := apr.GetValue(statusUntilParam); ok {
+		var err error
+		tracking.Until, err = parseTrackingTime(untilStr, now)
+		if err != nil {
+			return handleStatusVErr(fmt.Errorf("error: invalid value '%s' for --%s: %w", untilStr, statusUntilParam, err))
+		}
+	}
+
+	if apr.Contains(statusDebugTimingFlag) {
+		ctx = withStatusTimings(ctx)
 	}
 
-	err = PrintStatus(ctx, dEnv, staged, notStaged, apr.Contains(cli.ShowIgnoredFlag), as)
+	if apr.Contains(statusWorktreesFlag) {
+		err := printStatusWorktrees(ctx, dEnv)
+		if err != nil {
+			return handleStatusVErr(err)
+		}
+		return 0
+	}
+
+	if apr.Contains(statusAheadOnlyFlag) || apr.Contains(statusBehindOnlyFlag) {
+		return printAheadBehindCount(ctx, dEnv, apr, tracking)
+	}
+
+	if apr.Contains(statusConflictsOnlyFlag) {
+		n, err := printConflictsOnly(ctx, dEnv, filter.Relative)
+		if err != nil {
+			return handleStatusVErr(err)
+		}
+		if n == 0 {
+			return 1
+		}
+		return 0
+	}
+
+	if apr.Contains(statusWatchFlag) && cli.ExecuteWithStdioRestored != nil && checkIsTerminal() {
+		return watchStatus(ctx, dEnv, func() error {
+			return renderStatus(ctx, dEnv, apr, filter, ignoredMode, tracking)
+		})
+	}
+
+	stateKey, err := statusStateKey(ctx, dEnv)
+	if err != nil {
+		return handleStatusVErr(err)
+	}
+	err = renderStatus(ctx, dEnv, apr, filter, ignoredMode, tracking)
+	if err != nil {
+		return handleStatusVErr(err)
+	}
+	err = printStaleStatusWarning(ctx, dEnv, stateKey)
 	if err != nil {
 		return handleStatusVErr(err)
 	}
+	if apr.Contains(statusFailOnIgnoredFlag) {
+		err = checkIgnoredTableChanges(ctx, dEnv, filter.Relative)
+		if err != nil {
+			return handleStatusVErr(err)
+		}
+	}
 	return 0
 }
 
-func PrintStatus(ctx context.Context, dEnv *env.DoltEnv, stagedTbls, notStagedTbls []diff.TableDelta, showIgnoredTables bool, as merge.ArtifactStatus) error {
-	headRef, err := dEnv.RepoStateReader().CWBHeadRef()
+// statusTimingsKey is the context key marking a dolt status run with --debug-timing.
+type statusTimingsKey struct{}
+
+// withStatusTimings returns |ctx| marked so that logStatusTiming reports the steps timed in it.
+func withStatusTimings(ctx context.Context) context.Context {
+	return context.WithValue(ctx, statusTimingsKey{}, true)
+}
+
+// logStatusTiming prints how long the step |step| of dolt status took, since |start|, to stderr as a line of key=value
+// pairs, if |ctx| was marked by withStatusTimings.
+func logStatusTiming(ctx context.Context, step string, start time.Time) {
+	if ctx.Value(statusTimingsKey{}) == nil {
+		return
+	}
+	cli.PrintErrf("status timing: step=%s duration_ms=%.3f\n", step, float64(time.Since(start).Microseconds())/1000)
+}
+
+// renderStatus prints the status of the current branch as selected by the arguments given. It's the main body of
+// dolt status, and is called again for each change with --watch.
+func renderStatus(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgParseResults, filter StatusFilter, ignoredMode IgnoredTablesMode, tracking TrackingOptions) error {
+	roots, err := dEnv.Roots(ctx)
 	if err != nil {
 		return err
 	}
 
-	cli.Printf(branchHeader, headRef.GetPath())
+	if againstStr, ok := apr.GetValue(statusAgainstParam); ok {
+		var againstHash hash.Hash
+		roots.Head, againstHash, err = resolveStatusAgainstRoot(ctx, dEnv, againstStr)
+		if err != nil {
+			return err
+		}
+		cli.Printf("Comparing against %s (%s)\n", againstStr, againstHash.String())
+	}
 
-	err = printRemoteRefTrackingInfo(ctx, dEnv)
+	if apr.Contains(statusFastFlag) {
+		if apr.Contains(statusShowHashesFlag) {
+			ws, err := dEnv.WorkingSet(ctx)
+			if err != nil {
+				return err
+			}
+			err = printStatusHashes(ctx, dEnv, ws, apr.GetIntOrDefault(cli.AbbrevParam, 0))
+			if err != nil {
+				return err
+			}
+		}
+		return printFastStatus(ctx, dEnv, roots, tracking, filter)
+	}
+
+	start := time.Now()
+	staged, notStaged, err := diff.GetStagedUnstagedTableDeltas(ctx, roots)
 	if err != nil {
 		return err
 	}
+	logStatusTiming(ctx, "table_deltas", start)
+	if apr.Contains(statusIgnoreAutoIncFlag) {
+		staged, err = withoutAutoIncrementOnlyChanges(ctx, staged)
+		if err != nil {
+			return err
+		}
+		notStaged, err = withoutAutoIncrementOnlyChanges(ctx, notStaged)
+		if err != nil {
+			return err
+		}
+	}
 
-	mergeActive, err := isMergeActive(ctx, dEnv)
+	ws, err := dEnv.WorkingSet(ctx)
 	if err != nil {
 		return err
 	}
 
-	if mergeActive {
-		if as.HasConflicts() && as.HasConstraintViolations() {
-			cli.Println(fmt.Sprintf(unmergedTablesHeader, "conflicts and constraint violations"))
-		} else if as.HasConflicts() {
-			cli.Println(fmt.Sprintf(unmergedTablesHeader, "conflicts"))
-		} else if as.HasConstraintViolations() {
-			cli.Println(fmt.Sprintf(unmergedTablesHeader, "constraint violations"))
-		} else {
-			cli.Println(allMergedHeader)
+	if apr.Contains(statusWorktreeVsIndex) {
+		return printWorktreeVsIndex(ctx, dEnv, ws, notStaged, ignoredMode, filter)
+	}
+
+	if formatStr, ok := apr.GetValue(statusFormatParam); ok {
+		tmpl, err := parseStatusFormat(formatStr)
+		if err != nil {
+			return err
+		}
+		result, err := ComputeStatus(ctx, dEnv, roots, ws, staged, notStaged, tracking, filter)
+		if err != nil {
+			return err
 		}
+		return printStatusFormat(cli.CliOut, tmpl, result, apr.Contains(statusFormatOnceFlag))
+	}
+
+	if apr.Contains(statusNameOnlyFlag) {
+		names, err := statusTableNames(ctx, roots, ws, staged, notStaged, filter)
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			cli.Println(name)
+		}
+		return nil
 	}
 
-	n := printStagedDiffs(cli.CliOut, stagedTbls, true)
-	n, err = PrintDiffsNotStaged(ctx, dEnv, cli.CliOut, notStagedTbls, true, showIgnoredTables, n, as)
+	if apr.Contains(statusShowHashesFlag) {
+		err = printStatusHashes(ctx, dEnv, ws, apr.GetIntOrDefault(cli.AbbrevParam, 0))
+		if err != nil {
+			return err
+		}
+	}
+
+	as, err := PrintStatus(ctx, dEnv, staged, notStaged, ignoredMode, tracking, ws, filter, apr.Contains(statusIndexWorkFlag))
 	if err != nil {
 		return err
 	}
 
-	if !mergeActive && n == 0 {
-		cli.Println("nothing to commit, working tree clean")
+	if apr.Contains(statusSchemaFlag) {
+		if filter.Types != nil {
+			staged, notStaged, _ = filterStatusByType(filter.Types, staged, notStaged, as)
+		}
+		if filter.StagedOnly {
+			notStaged = nil
+		} else if filter.UnstagedOnly {
+			staged = nil
+		}
+		staged = filterStatusByNamespace(filter.Relative, staged)
+		notStaged = filterStatusByNamespace(filter.Relative, notStaged)
+		printStatusSchemaChanges(staged, notStaged, filter.Relative)
 	}
 
+	if apr.Contains(statusStorageFlag) {
+		err = printStatusStorageGrowth(ctx, roots, filter)
+		if err != nil {
+			return err
+		}
+	}
+
+	if refStr, ok := apr.GetValue(statusSchemaAgainst); ok {
+		err = printStatusSchemaDrift(ctx, dEnv, roots.Working, refStr, filter.Relative)
+		if err != nil {
+			return err
+		}
+	}
+
+	if apr.Contains(statusGraphFlag) {
+		err = printStatusGraph(ctx, dEnv, statusGraphCommits)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func handleStatusVErr(err error) int {
-	cli.PrintErrln(errhand.VerboseErrorFromError(err).Verbose())
-	return 1
-}
+// printWorktreeVsIndex prints only the tables in |notStaged| which differ between the working set and the staging
+// area, for dolt status --worktree-vs-index. Tables with conflicts or constraint violations aren't listed, since
+// dolt add doesn't resolve them.
+func printWorktreeVsIndex(ctx context.Context, dEnv *env.DoltEnv, ws *doltdb.WorkingSet, notStaged []diff.TableDelta, ignoredMode IgnoredTablesMode, filter StatusFilter) error {
+	as, err := merge.GetMergeArtifactStatus(ctx, ws)
+	if err != nil {
+		return err
+	}
+	unmerged := set.NewStrSet(as.DataConflictTables)
+	unmerged.Add(as.SchemaConflictsTables...)
+	unmerged.Add(as.ConstraintViolationsTables...)
+	var addable []diff.TableDelta
+	for _, td := range notStaged {
+		if !unmerged.Contains(td.CurName()) {
+			addable = append(addable, td)
+		}
+	}
 
-// printRemoteRefTrackingInfo prints remote tracking information if there is a remote branch set upstream from current branch
-func printRemoteRefTrackingInfo(ctx context.Context, dEnv *env.DoltEnv) error {
-	ddb := dEnv.DoltDB
-	rsr := dEnv.RepoStateReader()
-	headRef, err := rsr.CWBHeadRef()
+	notStaged = filterStatusByNamespace(filter.Relative, addable)
+	if filter.Types != nil {
+		_, notStaged, _ = filterStatusByType(filter.Types, nil, notStaged, merge.ArtifactStatus{})
+	}
+	notStaged, special := splitSpecialStatusTables(notStaged)
+	notStaged, classified := classifyStatusTables(notStaged, false)
+
+	n, err := printDiffsNotStaged(ctx, dEnv, cli.CliOut, notStaged, true, ignoredMode, 0, merge.ArtifactStatus{}, false, filter.Relative, nil)
 	if err != nil {
 		return err
 	}
-	branches, err := rsr.GetBranches()
+	n = printSpecialStatusTables(cli.CliOut, nil, special, n)
+	n, err = printClassifiedStatusTables(cli.CliOut, classified, n, filter.Relative)
 	if err != nil {
 		return err
 	}
-	upstream, hasUpstream := branches[headRef.GetPath()]
-	if !hasUpstream {
-		return nil
+
+	if n == 0 {
+		cli.Println("nothing to add, working tree matches the staging area")
 	}
+	return nil
+}
 
-	// Get local head branch
-	headCommitSpec, err := doltdb.NewCommitSpec(headRef.GetPath())
+// checkIgnoredTableChanges returns an error listing the untracked tables in the namespace |relative| which are ignored
+// by dolt_ignore, if there are any, for dolt status --fail-on-ignored.
+func checkIgnoredTableChanges(ctx context.Context, dEnv *env.DoltEnv, relative string) error {
+	roots, err := dEnv.Roots(ctx)
 	if err != nil {
 		return err
 	}
-	headCommit, err := ddb.Resolve(ctx, headCommitSpec, headRef)
+	_, notStaged, err := diff.GetStagedUnstagedTableDeltas(ctx, roots)
 	if err != nil {
 		return err
 	}
-	headHash, err := headCommit.HashOf()
+	notStaged = filterStatusByNamespace(relative, notStaged)
+
+	filteredTables, err := filterIgnoredNotStagedTables(ctx, notStaged, roots)
 	if err != nil {
 		return err
 	}
+	if len(filteredTables.Ignore) == 0 {
+		return nil
+	}
+	names := make([]string, len(filteredTables.Ignore))
+	for i, tableName := range filteredTables.Ignore {
+		names[i] = relativeTableName(tableName, relative)
+	}
+	return fmt.Errorf("error: ignored tables have changes: %s", strings.Join(names, ", "))
+}
 
-	// Get remote tracking branch
-	remotes, err := rsr.GetRemotes()
+// PrintStatus prints the status of the working set given, limited to the changes selected by |filter|, and returns the
+// merge artifacts of its tables. The relation of the current branch to its upstream is reported as configured by
+// |tracking|. Tables with conflicts or constraint violations are printed as soon as they're found, before the rest of
+// the status, since finding them all can take a while after a merge touching many tables. While a merge is active,
+// staged tables taken from the commit being merged are annotated as such. If |showIndexWork| is true, modified tables
+// are annotated with the indexes their change builds.
+func PrintStatus(ctx context.Context, dEnv *env.DoltEnv, stagedTbls, notStagedTbls []diff.TableDelta, ignoredMode IgnoredTablesMode, tracking TrackingOptions, ws *doltdb.WorkingSet, filter StatusFilter, showIndexWork bool) (merge.ArtifactStatus, error) {
+	headRef, err := dEnv.RepoStateReader().CWBHeadRef()
 	if err != nil {
-		return err
+		return merge.ArtifactStatus{}, err
 	}
-	remote, remoteOK := remotes[upstream.Remote]
-	if !remoteOK {
-		return nil
+
+	cli.Printf(branchHeader, headRef.GetPath())
+
+	if !tracking.Skip {
+		start := time.Now()
+		err = printRemoteRefTrackingInfo(ctx, dEnv, tracking)
+		if err != nil {
+			return merge.ArtifactStatus{}, err
+		}
+		logStatusTiming(ctx, "remote_tracking", start)
 	}
-	remoteTrackingRef, err := env.GetTrackingRef(upstream.Merge.Ref, remote)
+
+	// conflicts are reported along with unstaged changes
+	showUnmerged := !filter.StagedOnly && (filter.Types == nil || filter.Types.Contains(conflictedStatusType))
+	start := time.Now()
+	as, unmergedLines, err := printUnmergedTables(ctx, cli.CliOut, ws, showUnmerged, filter.Relative)
 	if err != nil {
-		return err
+		return as, err
+	}
+	logStatusTiming(ctx, "merge_artifacts", start)
+
+	stagedTbls = filterStatusByNamespace(filter.Relative, stagedTbls)
+	notStagedTbls = filterStatusByNamespace(filter.Relative, notStagedTbls)
+
+	filteredAs := as
+	if filter.Types != nil {
+		stagedTbls, notStagedTbls, filteredAs = filterStatusByType(filter.Types, stagedTbls, notStagedTbls, filteredAs)
+	}
+	if filter.StagedOnly {
+		notStagedTbls, filteredAs = nil, merge.ArtifactStatus{}
+	} else if filter.UnstagedOnly {
+		stagedTbls = nil
 	}
-	remoteCommitSpec, err := doltdb.NewCommitSpec(remoteTrackingRef.GetPath())
+
+	stagedTbls, stagedSpecial := splitSpecialStatusTables(stagedTbls)
+	notStagedTbls, notStagedSpecial := splitSpecialStatusTables(notStagedTbls)
+	stagedTbls, stagedClassified := classifyStatusTables(stagedTbls, true)
+	notStagedTbls, notStagedClassified := classifyStatusTables(notStagedTbls, false)
+
+	stagedAnnotations, notStagedAnnotations := make(map[string]string), make(map[string]string)
+	if showIndexWork {
+		stagedAnnotations = indexWorkAnnotations(stagedTbls)
+		notStagedAnnotations = indexWorkAnnotations(notStagedTbls)
+	}
+	if ws.MergeActive() {
+		err = addMergeSourceAnnotations(ctx, ws.MergeState(), stagedTbls, stagedAnnotations)
+		if err != nil {
+			return as, err
+		}
+	}
+
+	n, err := printStagedDiffs(ctx, cli.CliOut, stagedTbls, true, filter.Relative, stagedAnnotations)
 	if err != nil {
-		return err
+		return as, err
 	}
-	remoteCommit, err := ddb.Resolve(ctx, remoteCommitSpec, remoteTrackingRef)
+	n, err = printDiffsNotStaged(ctx, dEnv, cli.CliOut, notStagedTbls, true, ignoredMode, n, filteredAs, false, filter.Relative, notStagedAnnotations)
 	if err != nil {
-		return err
+		return as, err
 	}
-	remoteHash, err := remoteCommit.HashOf()
+	n = printSpecialStatusTables(cli.CliOut, stagedSpecial, notStagedSpecial, n)
+	n, err = printClassifiedStatusTables(cli.CliOut, append(stagedClassified, notStagedClassified...), n, filter.Relative)
 	if err != nil {
-		return err
+		return as, err
 	}
 
-	// get common ancestor
-	ancCommit, err := doltdb.GetCommitAncestor(ctx, headCommit, remoteCommit)
-	if err != nil {
-		return err
+	if !ws.MergeActive() && unmergedLines+n == 0 && filter.isEmpty() {
+		cli.Println("nothing to commit, working tree clean")
+	}
+
+	return as, nil
+}
+
+// printUnmergedTables finds the merge artifacts of the tables in |ws| and, if |show| is true, prints a line to |wr| for
+// each table with conflicts or constraint violations as soon as it's found. While a merge is active, this is preceded
+// by a header and followed by advice on concluding the merge, which is printed even if |show| is false. Returns the
+// artifacts found, and the number of table lines printed. Only tables in the namespace |relative| are included, if it's
+// not empty.
+func printUnmergedTables(ctx context.Context, wr io.Writer, ws *doltdb.WorkingSet, show bool, relative string) (merge.ArtifactStatus, int, error) {
+	mergeActive := ws.MergeActive()
+
+	var as merge.ArtifactStatus
+	if mergeActive {
+		for _, tblName := range ws.MergeState().TablesWithSchemaConflicts() {
+			if strings.HasPrefix(tblName, relative) {
+				as.SchemaConflictsTables = append(as.SchemaConflictsTables, tblName)
+			}
+		}
 	}
-	ancHash, err := ancCommit.HashOf()
+
+	linesPrinted := 0
+	err := merge.IterMergeArtifactStatus(ctx, ws, func(tas merge.TableArtifactStatus) (bool, error) {
+		if !strings.HasPrefix(tas.Name, relative) {
+			return false, nil
+		}
+		if tas.DataConflicts {
+			as.DataConflictTables = append(as.DataConflictTables, tas.Name)
+		}
+		if tas.ConstraintViolations {
+			as.ConstraintViolationsTables = append(as.ConstraintViolationsTables, tas.Name)
+		}
+		if !show {
+			return false, nil
+		}
+
+		if linesPrinted == 0 {
+			if mergeActive {
+				iohelp.WriteLine(wr, unmergedTablesHeader)
+				iohelp.WriteLine(wr, "")
+			}
+			iohelp.WriteLine(wr, unmergedPathsHeader)
+			iohelp.WriteLine(wr, mergedTableHelp)
+		}
+		tas.Name = relativeTableName(tas.Name, relative)
+		for _, line := range unmergedTableLines(tas) {
+			iohelp.WriteLine(wr, color.RedString(line))
+			linesPrinted++
+		}
+		return false, nil
+	})
 	if err != nil {
-		return err
+		return as, linesPrinted, err
 	}
 
-	ahead := 0
-	behind := 0
-	if headHash != remoteHash {
-		behind, err = countCommitsInRange(ctx, ddb, remoteHash, ancHash)
-		if err != nil {
-			return err
+	if mergeActive {
+		var unmerged string
+		if as.HasConflicts() && as.HasConstraintViolations() {
+			unmerged = "conflicts and constraint violations"
+		} else if as.HasConflicts() {
+			unmerged = "conflicts"
+		} else if as.HasConstraintViolations() {
+			unmerged = "constraint violations"
 		}
-		ahead, err = countCommitsInRange(ctx, ddb, headHash, ancHash)
-		if err != nil {
-			return err
+
+		if unmerged == "" {
+			iohelp.WriteLine(wr, allMergedHeader)
+		} else {
+			if linesPrinted == 0 {
+				iohelp.WriteLine(wr, unmergedTablesHeader)
+			}
+			iohelp.WriteLine(wr, fmt.Sprintf(unmergedTablesHelp, unmerged))
 		}
+	} else if linesPrinted > 0 {
+		iohelp.WriteLine(wr, "")
 	}
 
-	cli.Println(getRemoteTrackingMsg(remoteTrackingRef.GetPath(), ahead, behind))
-	return nil
+	return as, linesPrinted, nil
 }
 
-// countCommitsInRange returns the number of commits between the given starting point to trace back to the given target point.
-// The starting commit must be a descendant of the target commit. Target commit must be a common ancestor commit.
-func countCommitsInRange(ctx context.Context, ddb *doltdb.DoltDB, startCommitHash, targetCommitHash hash.Hash) (int, error) {
-	itr, iErr := commitwalk.GetTopologicalOrderIterator(ctx, ddb, []hash.Hash{startCommitHash}, nil)
-	if iErr != nil {
-		return 0, iErr
+// printConflictsOnly prints a line for each table in the namespace |relative| with conflicts or constraint violations,
+// for dolt status --conflicts-only: its name, whether it has a schema conflict, and its numbers of data conflicts and
+// constraint violations, separated by tabs. Returns the number of tables printed.
+func printConflictsOnly(ctx context.Context, dEnv *env.DoltEnv, relative string) (int, error) {
+	ws, err := dEnv.WorkingSet(ctx)
+	if err != nil {
+		return 0, err
 	}
-	count := 0
-	for {
-		hash, _, err := itr.Next(ctx)
-		if err == io.EOF {
-			return 0, errors.New("no match found to ancestor commit")
-		} else if err != nil {
-			return 0, err
-		}
+	root := ws.WorkingRoot()
 
-		if hash == targetCommitHash {
-			break
+	n := 0
+	err = merge.IterMergeArtifactStatus(ctx, ws, func(tas merge.TableArtifactStatus) (bool, error) {
+		if !strings.HasPrefix(tas.Name, relative) {
+			return false, nil
 		}
-		count += 1
-	}
+		var conflicts, violations uint64
+		tbl, ok, err := root.GetTable(ctx, tas.Name)
+		if err != nil {
+			return false, err
+		}
+		if ok && tas.DataConflicts {
+			conflicts, err = tbl.NumRowsInConflict(ctx)
+			if err != nil {
+				return false, err
+			}
+		}
+		if ok && tas.ConstraintViolations {
+			violations, err = tbl.NumConstraintViolations(ctx)
+			if err != nil {
+				return false, err
+			}
+		}
+		cli.Printf("%s\t%t\t%d\t%d\n", relativeTableName(tas.Name, relative), tas.SchemaConflict, conflicts, violations)
+		n++
+		return false, nil
+	})
+	return n, err
+}
 
-	return count, nil
+// unmergedTableLines returns the lines describing the merge artifacts of a table in the unmerged paths section of the
+// status.
+func unmergedTableLines(tas merge.TableArtifactStatus) []string {
+	var lines []string
+	if tas.SchemaConflict {
+		lines = append(lines, fmt.Sprintf(statusFmt, schemaConflictLabel, tas.Name))
+	}
+	if tas.DataConflicts {
+		lines = append(lines, fmt.Sprintf(statusFmt, bothModifiedLabel, tas.Name))
+	}
+	if len(lines) == 0 && tas.ConstraintViolations {
+		lines = append(lines, fmt.Sprintf(statusFmt, "modified", tas.Name))
+	}
+	return lines
 }
 
-// getRemoteTrackingMsg returns remote tracking information with given remote branch name, number of commits ahead and/or behind.
-func getRemoteTrackingMsg(remoteBranchName string, ahead int, behind int) string {
-	if ahead > 0 && behind > 0 {
-		return fmt.Sprintf(`Your branch and '%s' have diverged,
-and have %v and %v different commits each, respectively.
-  (use "dolt pull" to update your local branch)`, remoteBranchName, ahead, behind)
-	} else if ahead > 0 {
-		s := ""
-		if ahead > 1 {
-			s = "s"
-		}
-		return fmt.Sprintf(`Your branch is ahead of '%s' by %v commit%s.
-  (use "dolt push" to publish your local commits)`, remoteBranchName, ahead, s)
-	} else if behind > 0 {
-		s := ""
-		if behind > 1 {
-			s = "s"
-		}
-		return fmt.Sprintf(`Your branch is behind '%s' by %v commit%s, and can be fast-forwarded.
-  (use "dolt pull" to update your local branch)`, remoteBranchName, behind, s)
-	} else {
-		return fmt.Sprintf("Your branch is up to date with '%s'.", remoteBranchName)
+// resolveStatusAgainstRoot resolves the commit spec given to --against and returns its root value, which takes the
+// place of the HEAD root when computing staged changes, along with the hash of the commit.
+func resolveStatusAgainstRoot(ctx context.Context, dEnv *env.DoltEnv, specStr string) (*doltdb.RootValue, hash.Hash, error) {
+	cs, err := doltdb.NewCommitSpec(specStr)
+	if err != nil {
+		return nil, hash.Hash{}, err
 	}
+
+	headRef, err := dEnv.RepoStateReader().CWBHeadRef()
+	if err != nil {
+		return nil, hash.Hash{}, err
+	}
+
+	cm, err := dEnv.DoltDB.Resolve(ctx, cs, headRef)
+	if err != nil {
+		return nil, hash.Hash{}, err
+	}
+
+	h, err := cm.HashOf()
+	if err != nil {
+		return nil, hash.Hash{}, err
+	}
+
+	root, err := cm.GetRootValue(ctx)
+	if err != nil {
+		return nil, hash.Hash{}, err
+	}
+
+	return root, h, nil
+}
+
+func handleStatusVErr(err error) int {
+	cli.PrintErrln(errhand.VerboseErrorFromError(err).Verbose())
+	return 1
 }
@@ -0,0 +1,200 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/utils/iohelp"
+	"github.com/dolthub/dolt/go/libraries/utils/set"
+)
+
+const fastStatusNote = `note: with --fast, tables are only compared by hash, so a renamed table is listed as deleted and new`
+
+// hashTableChange is a table whose hash differs between two roots, as found by hashTableChanges.
+type hashTableChange struct {
+	name     string
+	diffType diff.TableDiffType
+}
+
+// hashTableChanges returns the tables added, removed and modified between |from| and |to|, sorted by name. Tables are
+// matched by name and compared by hash only, without loading their schemas or rows, so renames aren't detected.
+func hashTableChanges(ctx context.Context, from, to *doltdb.RootValue) ([]hashTableChange, error) {
+	fromHashes, err := from.MapTableHashes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	toHashes, err := to.MapTableHashes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []hashTableChange
+	for name, toHash := range toHashes {
+		if fromHash, ok := fromHashes[name]; !ok {
+			changes = append(changes, hashTableChange{name: name, diffType: diff.AddedTable})
+		} else if fromHash != toHash {
+			changes = append(changes, hashTableChange{name: name, diffType: diff.ModifiedTable})
+		}
+	}
+	for name := range fromHashes {
+		if _, ok := toHashes[name]; !ok {
+			changes = append(changes, hashTableChange{name: name, diffType: diff.RemovedTable})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].name < changes[j].name
+	})
+	return changes, nil
+}
+
+// filterHashTableChanges returns the |changes| in the namespace of |filter| which have one of its types, leaving out
+// read-only system tables and those in |exclude|.
+func filterHashTableChanges(changes []hashTableChange, filter StatusFilter, exclude *set.StrSet) []hashTableChange {
+	var filtered []hashTableChange
+	for _, c := range changes {
+		if !strings.HasPrefix(c.name, filter.Relative) || doltdb.IsReadOnlySystemTable(c.name) || exclude.Contains(c.name) {
+			continue
+		}
+		if filter.Types != nil {
+			statusType := modifiedStatusType
+			if c.diffType == diff.AddedTable {
+				statusType = addedStatusType
+			} else if c.diffType == diff.RemovedTable {
+				statusType = deletedStatusType
+			}
+			if !filter.Types.Contains(statusType) {
+				continue
+			}
+		}
+		filtered = append(filtered, c)
+	}
+	return filtered
+}
+
+// printFastStatus prints the status of the current branch for dolt status --fast, which finds the changed tables by
+// comparing table hashes rather than computing table deltas.
+func printFastStatus(ctx context.Context, dEnv *env.DoltEnv, roots doltdb.Roots, tracking TrackingOptions, filter StatusFilter) error {
+	headRef, err := dEnv.RepoStateReader().CWBHeadRef()
+	if err != nil {
+		return err
+	}
+	cli.Printf(branchHeader, headRef.GetPath())
+	if !tracking.Skip {
+		start := time.Now()
+		err = printRemoteRefTrackingInfo(ctx, dEnv, tracking)
+		if err != nil {
+			return err
+		}
+		logStatusTiming(ctx, "remote_tracking", start)
+	}
+
+	ws, err := dEnv.WorkingSet(ctx)
+	if err != nil {
+		return err
+	}
+	showUnmerged := !filter.StagedOnly && (filter.Types == nil || filter.Types.Contains(conflictedStatusType))
+	start := time.Now()
+	as, n, err := printUnmergedTables(ctx, cli.CliOut, ws, showUnmerged, filter.Relative)
+	if err != nil {
+		return err
+	}
+	logStatusTiming(ctx, "merge_artifacts", start)
+	unmerged := set.NewStrSet(as.DataConflictTables)
+	unmerged.Add(as.SchemaConflictsTables...)
+	unmerged.Add(as.ConstraintViolationsTables...)
+
+	if !filter.UnstagedOnly {
+		staged, err := hashTableChanges(ctx, roots.Head, roots.Staged)
+		if err != nil {
+			return err
+		}
+		staged = filterHashTableChanges(staged, filter, set.NewStrSet(nil))
+		n = printHashTableChanges(stagedHeader, stagedHeaderHelp, staged, color.GreenString, filter.Relative, n)
+	}
+
+	if !filter.StagedOnly {
+		notStaged, err := hashTableChanges(ctx, roots.Staged, roots.Working)
+		if err != nil {
+			return err
+		}
+		notStaged = filterHashTableChanges(notStaged, filter, unmerged)
+
+		var changed, untracked []hashTableChange
+		var untrackedNames []string
+		for _, c := range notStaged {
+			if c.diffType == diff.AddedTable {
+				untracked = append(untracked, c)
+				untrackedNames = append(untrackedNames, c.name)
+			} else {
+				changed = append(changed, c)
+			}
+		}
+		n = printHashTableChanges(workingHeader, workingHeaderHelp, changed, color.RedString, filter.Relative, n)
+
+		filtered, err := doltdb.FilterIgnoredTables(ctx, untrackedNames, roots)
+		if err != nil && doltdb.AsDoltIgnoreInConflict(err) == nil {
+			return err
+		}
+		dontIgnore := set.NewStrSet(filtered.DontIgnore)
+		var shown []hashTableChange
+		for _, c := range untracked {
+			if dontIgnore.Contains(c.name) {
+				shown = append(shown, c)
+			}
+		}
+		n = printHashTableChanges(untrackedHeader, untrackedHeaderHelp, shown, color.RedString, filter.Relative, n)
+	}
+
+	if !ws.MergeActive() && n == 0 && filter.isEmpty() {
+		cli.Println("nothing to commit, working tree clean")
+		return nil
+	}
+	if n > 0 {
+		cli.Println()
+		cli.Println(fastStatusNote)
+	}
+	return nil
+}
+
+// printHashTableChanges prints a section of dolt status --fast, with the |header| and |help| given, listing |changes|
+// colored with |colorFn|. Returns |linesPrinted| plus the number of tables listed.
+func printHashTableChanges(header, help string, changes []hashTableChange, colorFn func(string, ...interface{}) string, relative string, linesPrinted int) int {
+	if len(changes) == 0 {
+		return linesPrinted
+	}
+	if linesPrinted > 0 {
+		cli.Println()
+	}
+	iohelp.WriteLine(cli.CliOut, header)
+	iohelp.WriteLine(cli.CliOut, help)
+
+	lines := make([]string, len(changes))
+	for i, c := range changes {
+		lines[i] = fmt.Sprintf(statusFmt, tblDiffTypeToLabel[c.diffType], relativeTableName(c.name, relative))
+	}
+	iohelp.WriteLine(cli.CliOut, colorFn(strings.Join(lines, "\n")))
+	return linesPrinted + len(lines)
+}
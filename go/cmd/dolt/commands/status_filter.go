@@ -0,0 +1,142 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/utils/set"
+)
+
+const (
+	addedStatusType      = "added"
+	modifiedStatusType   = "modified"
+	deletedStatusType    = "deleted"
+	conflictedStatusType = "conflicted"
+)
+
+var statusTypes = []string{addedStatusType, modifiedStatusType, deletedStatusType, conflictedStatusType}
+
+// StatusFilter restricts the changes reported by PrintStatus. The zero value reports every change.
+type StatusFilter struct {
+	// Types are the change types to report, or nil to report all of them
+	Types *set.StrSet
+	// StagedOnly reports only changes staged for commit
+	StagedOnly bool
+	// UnstagedOnly reports only changes in the working set that aren't staged, including conflicts and untracked tables
+	UnstagedOnly bool
+	// Relative, if not empty, is a namespace: a prefix of table names. Only tables in it are reported, and their names
+	// are printed without the prefix.
+	Relative string
+}
+
+func (f StatusFilter) isEmpty() bool {
+	return f.Types == nil && !f.StagedOnly && !f.UnstagedOnly && f.Relative == ""
+}
+
+// relativeTableName returns |tableName| relative to the namespace |relative|.
+func relativeTableName(tableName, relative string) string {
+	return strings.TrimPrefix(tableName, relative)
+}
+
+// filterStatusByNamespace returns the deltas of the tables in the namespace |relative|. A renamed table is included if
+// either of its names is in it.
+func filterStatusByNamespace(relative string, deltas []diff.TableDelta) []diff.TableDelta {
+	if relative == "" {
+		return deltas
+	}
+	var filtered []diff.TableDelta
+	for _, td := range deltas {
+		if strings.HasPrefix(td.CurName(), relative) || strings.HasPrefix(td.FromName, relative) {
+			filtered = append(filtered, td)
+		}
+	}
+	return filtered
+}
+
+// withoutAutoIncrementOnlyChanges returns |deltas| without those of tables whose auto increment value is all that
+// changed.
+func withoutAutoIncrementOnlyChanges(ctx context.Context, deltas []diff.TableDelta) ([]diff.TableDelta, error) {
+	filtered := make([]diff.TableDelta, 0, len(deltas))
+	for _, td := range deltas {
+		onlyAutoInc, err := td.HasOnlyAutoIncrementChanged(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !onlyAutoInc {
+			filtered = append(filtered, td)
+		}
+	}
+	return filtered, nil
+}
+
+// parseStatusTypeFilter parses the comma-separated list of change types given to --type into a set.
+func parseStatusTypeFilter(typeStr string) (*set.StrSet, error) {
+	valid := set.NewStrSet(statusTypes)
+	filter := set.NewEmptyStrSet()
+	for _, t := range strings.Split(typeStr, ",") {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if !valid.Contains(t) {
+			return nil, fmt.Errorf("invalid value '%s' for --%s; valid types are %s", t, statusTypeParam, strings.Join(statusTypes, ", "))
+		}
+		filter.Add(t)
+	}
+	return filter, nil
+}
+
+// tableDeltaMatchesStatusTypes returns whether the table delta given is one of the change types in |filter|. Renames are
+// reported by status as a drop of the old name and an add of the new one, so they match both added and deleted.
+func tableDeltaMatchesStatusTypes(td diff.TableDelta, filter *set.StrSet) bool {
+	if td.IsAdd() {
+		return filter.Contains(addedStatusType)
+	} else if td.IsDrop() {
+		return filter.Contains(deletedStatusType)
+	} else if td.IsRename() {
+		return filter.Contains(addedStatusType) || filter.Contains(deletedStatusType)
+	}
+	return filter.Contains(modifiedStatusType)
+}
+
+// filterStatusByType limits the staged and unstaged table deltas, as well as the merge artifact status, to the change
+// types in |filter|. Tables with conflicts or constraint violations are only reported when conflicted is requested.
+func filterStatusByType(filter *set.StrSet, staged, notStaged []diff.TableDelta, as merge.ArtifactStatus) ([]diff.TableDelta, []diff.TableDelta, merge.ArtifactStatus) {
+	conflicted := set.NewStrSet(as.SchemaConflictsTables)
+	conflicted.Add(as.DataConflictTables...)
+	conflicted.Add(as.ConstraintViolationsTables...)
+
+	filterDeltas := func(deltas []diff.TableDelta) []diff.TableDelta {
+		filtered := make([]diff.TableDelta, 0, len(deltas))
+		for _, td := range deltas {
+			if conflicted.Contains(td.CurName()) {
+				if filter.Contains(conflictedStatusType) {
+					filtered = append(filtered, td)
+				}
+			} else if tableDeltaMatchesStatusTypes(td, filter) {
+				filtered = append(filtered, td)
+			}
+		}
+		return filtered
+	}
+
+	if !filter.Contains(conflictedStatusType) {
+		as = merge.ArtifactStatus{}
+	}
+
+	return filterDeltas(staged), filterDeltas(notStaged), as
+}
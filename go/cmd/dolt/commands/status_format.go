@@ -0,0 +1,226 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/utils/set"
+)
+
+const renamedStatusType = "renamed"
+
+// StatusResult is the status of the current branch, as computed by ComputeStatus. It's the data given to a
+// dolt status --format-once template.
+type StatusResult struct {
+	// Branch is the current branch.
+	Branch string
+	// Upstream is the remote tracking branch of the current branch, or empty if it has none.
+	Upstream string
+	// Ahead and Behind are the number of commits the current branch has that its upstream doesn't, and vice versa.
+	Ahead, Behind int
+	// MergeActive is whether a merge is in progress.
+	MergeActive bool
+	// Tables are the tables with conflicts or constraint violations, followed by those with staged changes and those
+	// with changes not staged for commit. A table can be listed both as staged and as not staged.
+	Tables []StatusTable
+	// Staged, Unstaged and Untracked are the number of tables in Tables with changes staged for commit, with changes
+	// not staged for commit, and which are new and not staged for commit.
+	Staged, Unstaged, Untracked int
+	// Changes is the total of Staged, Unstaged and Untracked. Tables with conflicts or constraint violations aren't
+	// counted, since they have to be resolved before they can be committed.
+	Changes int
+	// Conflicted is the number of tables in Tables with conflicts or constraint violations.
+	Conflicted int
+}
+
+// Clean returns whether there's nothing to commit, which is when dolt status prints "nothing to commit".
+func (r StatusResult) Clean() bool {
+	return !r.MergeActive && r.Changes == 0 && r.Conflicted == 0
+}
+
+// addTable appends |st| to the tables of |r|, and counts it.
+func (r *StatusResult) addTable(st StatusTable) {
+	r.Tables = append(r.Tables, st)
+	switch {
+	case st.Status == conflictedStatusType:
+		r.Conflicted++
+		return
+	case st.Staged:
+		r.Staged++
+	case st.Status == addedStatusType:
+		r.Untracked++
+	default:
+		r.Unstaged++
+	}
+	r.Changes++
+}
+
+// StatusTable is a table listed in a StatusResult. It's the data given to a dolt status --format template, which is
+// rendered once per table.
+type StatusTable struct {
+	// Name is the name of the table, without the --relative namespace.
+	Name string
+	// OldName is the name the table had before it was renamed, if Status is renamed.
+	OldName string
+	// Status is one of added, modified, deleted, renamed or conflicted. An added table which isn't staged is untracked.
+	Status string
+	// Staged is whether the change is staged for commit.
+	Staged bool
+}
+
+// ComputeStatus returns the status of the current branch, given its roots, working set and table deltas, limited to
+// the tables selected by |filter|. Untracked tables matched by dolt_ignore are left out, and the current branch is
+// only compared with its upstream unless |tracking| skips it.
+func ComputeStatus(ctx context.Context, dEnv *env.DoltEnv, roots doltdb.Roots, ws *doltdb.WorkingSet, staged, notStaged []diff.TableDelta, tracking TrackingOptions, filter StatusFilter) (StatusResult, error) {
+	headRef, err := dEnv.RepoStateReader().CWBHeadRef()
+	if err != nil {
+		return StatusResult{}, err
+	}
+	result := StatusResult{Branch: headRef.GetPath(), MergeActive: ws.MergeActive()}
+
+	if !tracking.Skip {
+		headCommit, _, trackingRef, remoteCommit, ok, err := resolveHeadAndUpstreamCommits(ctx, dEnv)
+		if err != nil {
+			return StatusResult{}, err
+		}
+		if ok {
+			result.Upstream = trackingRef.GetPath()
+			result.Ahead, result.Behind, err = countAheadBehind(ctx, dEnv.DoltDB, headCommit, remoteCommit, tracking)
+			if err != nil {
+				return StatusResult{}, err
+			}
+		}
+	}
+
+	as, err := merge.GetMergeArtifactStatus(ctx, ws)
+	if err != nil {
+		return StatusResult{}, err
+	}
+	staged = filterStatusByNamespace(filter.Relative, staged)
+	notStaged = filterStatusByNamespace(filter.Relative, notStaged)
+	if filter.Types != nil {
+		staged, notStaged, as = filterStatusByType(filter.Types, staged, notStaged, as)
+	}
+	if filter.StagedOnly {
+		notStaged, as = nil, merge.ArtifactStatus{}
+	} else if filter.UnstagedOnly {
+		staged = nil
+	}
+
+	unmerged := set.NewStrSet(as.DataConflictTables)
+	unmerged.Add(as.SchemaConflictsTables...)
+	unmerged.Add(as.ConstraintViolationsTables...)
+	for _, tblName := range unmerged.AsSortedSlice() {
+		if !strings.HasPrefix(tblName, filter.Relative) {
+			continue
+		}
+		result.addTable(StatusTable{Name: relativeTableName(tblName, filter.Relative), Status: conflictedStatusType})
+	}
+
+	for _, td := range staged {
+		if !doltdb.IsReadOnlySystemTable(td.CurName()) {
+			result.addTable(statusTableForDelta(td, true, filter.Relative))
+		}
+	}
+
+	var untracked []diff.TableDelta
+	for _, td := range notStaged {
+		if unmerged.Contains(td.CurName()) {
+			continue
+		}
+		if td.IsAdd() {
+			untracked = append(untracked, td)
+			continue
+		}
+		result.addTable(statusTableForDelta(td, false, filter.Relative))
+	}
+
+	untrackedNames := make([]string, len(untracked))
+	for i, td := range untracked {
+		untrackedNames[i] = td.CurName()
+	}
+	filtered, err := doltdb.FilterIgnoredTables(ctx, untrackedNames, roots)
+	if err != nil && doltdb.AsDoltIgnoreInConflict(err) == nil {
+		return StatusResult{}, err
+	}
+	dontIgnore := set.NewStrSet(filtered.DontIgnore)
+	for _, td := range untracked {
+		if dontIgnore.Contains(td.CurName()) {
+			result.addTable(statusTableForDelta(td, false, filter.Relative))
+		}
+	}
+
+	return result, nil
+}
+
+// statusTableForDelta returns the StatusTable describing the change |td|.
+func statusTableForDelta(td diff.TableDelta, staged bool, relative string) StatusTable {
+	st := StatusTable{Name: relativeTableName(td.CurName(), relative), Staged: staged}
+	switch {
+	case td.IsAdd():
+		st.Status = addedStatusType
+	case td.IsDrop():
+		st.Status = deletedStatusType
+	case td.IsRename():
+		st.Status = renamedStatusType
+		st.OldName = relativeTableName(td.FromName, relative)
+	default:
+		st.Status = modifiedStatusType
+	}
+	return st
+}
+
+// parseStatusFormat parses the template given with --format.
+func parseStatusFormat(format string) (*template.Template, error) {
+	tmpl, err := template.New("status").Parse(format)
+	if err != nil {
+		return nil, fmt.Errorf("error: invalid --%s template: %w", statusFormatParam, err)
+	}
+	return tmpl, nil
+}
+
+// printStatusFormat renders |tmpl| to |wr| once for each table in |result|, or once for the whole result if |once| is
+// true, ending each rendering with a newline.
+func printStatusFormat(wr io.Writer, tmpl *template.Template, result StatusResult, once bool) error {
+	render := func(data interface{}) error {
+		sb := &strings.Builder{}
+		err := tmpl.Execute(sb, data)
+		if err != nil {
+			return fmt.Errorf("error: could not render the --%s template: %w", statusFormatParam, err)
+		}
+		_, err = fmt.Fprintln(wr, sb.String())
+		return err
+	}
+
+	if once {
+		return render(result)
+	}
+	for _, st := range result.Tables {
+		err := render(st)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
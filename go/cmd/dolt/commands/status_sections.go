@@ -0,0 +1,593 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
+	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/utils/set"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// statusGraphCommits is the number of commits shown by status --graph
+const statusGraphCommits = 5
+
+// indexWorkFmt is the annotation of a modified table's status line with the indexes its change builds, for
+// dolt status --show-index-work.
+const indexWorkFmt = " (builds indexes: %s)"
+
+// indexWorkAnnotations returns an annotation for the status line of each modified table in |deltas| whose change
+// builds secondary indexes, keyed by table name.
+func indexWorkAnnotations(deltas []diff.TableDelta) map[string]string {
+	annotations := make(map[string]string)
+	for _, td := range deltas {
+		if td.IsAdd() || td.IsDrop() {
+			continue
+		}
+		if names := td.RebuiltIndexes(); len(names) > 0 {
+			annotations[td.CurName()] = fmt.Sprintf(indexWorkFmt, strings.Join(names, ", "))
+		}
+	}
+	return annotations
+}
+
+// mergeSourceAnnotation is the annotation of a staged table's status line when the table was taken from the commit
+// being merged, rather than changed locally.
+const mergeSourceAnnotation = " (from merge)"
+
+// addMergeSourceAnnotations adds mergeSourceAnnotation to |annotations| for each table in |staged| which the merge of
+// |mergeState| brought in: tables whose staged version is the one in the commit being merged, and tables the merge
+// dropped, which were in the working set before the merge but aren't in that commit.
+func addMergeSourceAnnotations(ctx context.Context, mergeState *doltdb.MergeState, staged []diff.TableDelta, annotations map[string]string) error {
+	mergeRoot, err := mergeState.Commit().GetRootValue(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, td := range staged {
+		fromMerge := false
+		if td.IsDrop() {
+			inMerge, err := mergeRoot.HasTable(ctx, td.FromName)
+			if err != nil {
+				return err
+			}
+			preMerge, err := mergeState.PreMergeWorkingRoot().HasTable(ctx, td.FromName)
+			if err != nil {
+				return err
+			}
+			fromMerge = !inMerge && preMerge
+		} else {
+			mergeHash, ok, err := mergeRoot.GetTableHash(ctx, td.ToName)
+			if err != nil {
+				return err
+			}
+			stagedHash, err := td.ToTable.HashOf()
+			if err != nil {
+				return err
+			}
+			fromMerge = ok && mergeHash == stagedHash
+		}
+		if fromMerge {
+			annotations[td.CurName()] += mergeSourceAnnotation
+		}
+	}
+	return nil
+}
+
+const (
+	schemaChangesStagedHeader   = "Schema changes to be committed:"
+	schemaChangesUnstagedHeader = "Schema changes not staged for commit:"
+	storageGrowthHeader         = "Storage growth since HEAD:"
+	schemaDriftHeader           = "Schema drift from %s:"
+)
+
+// printStatusSchemaChanges prints a summary of the column changes in each modified table in |staged| and |notStaged|,
+// with table names relative to the namespace |relative|.
+func printStatusSchemaChanges(staged, notStaged []diff.TableDelta, relative string) {
+	printSchemaChangesSection(schemaChangesStagedHeader, staged, color.GreenString, relative)
+	printSchemaChangesSection(schemaChangesUnstagedHeader, notStaged, color.RedString, relative)
+}
+
+func printSchemaChangesSection(header string, deltas []diff.TableDelta, colorFn func(string, ...interface{}) string, relative string) {
+	var lines []string
+	for _, td := range deltas {
+		if td.IsAdd() || td.IsDrop() || doltdb.IsReadOnlySystemTable(td.CurName()) {
+			continue
+		}
+		if changes := schemaDeltaSummary(td.FromSch, td.ToSch); len(changes) > 0 {
+			lines = append(lines, fmt.Sprintf("\t%s: %s", relativeTableName(td.CurName(), relative), strings.Join(changes, ", ")))
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+
+	cli.Println()
+	cli.Println(header)
+	cli.Println(colorFn(strings.Join(lines, "\n")))
+}
+
+// printStatusStorageGrowth prints, for each table changed between HEAD and the working set of |roots|, or its staged
+// tables with --staged, an estimate of the storage the change adds and no longer uses, with table names relative to the
+// namespace of |filter|.
+func printStatusStorageGrowth(ctx context.Context, roots doltdb.Roots, filter StatusFilter) error {
+	to := roots.Working
+	if filter.StagedOnly {
+		to = roots.Staged
+	}
+	deltas, err := diff.GetTableDeltas(ctx, roots.Head, to)
+	if err != nil {
+		return err
+	}
+	if filter.Types != nil {
+		deltas, _, _ = filterStatusByType(filter.Types, deltas, nil, merge.ArtifactStatus{})
+	}
+	deltas = filterStatusByNamespace(filter.Relative, deltas)
+
+	var lines []string
+	for _, td := range deltas {
+		if doltdb.IsReadOnlySystemTable(td.CurName()) {
+			continue
+		}
+		added, removed, err := td.GetStorageGrowth(ctx)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, fmt.Sprintf("\t%s: +%s, -%s", relativeTableName(td.CurName(), filter.Relative), humanize.Bytes(added), humanize.Bytes(removed)))
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	cli.Println()
+	cli.Println(storageGrowthHeader)
+	cli.Println(color.YellowString(strings.Join(lines, "\n")))
+	return nil
+}
+
+// printStatusSchemaDrift prints each table in the namespace |relative| whose schema in |working| differs from its
+// schema on the branch or commit |refStr|, with a summary of the column changes, for dolt status --schema-against.
+func printStatusSchemaDrift(ctx context.Context, dEnv *env.DoltEnv, working *doltdb.RootValue, refStr, relative string) error {
+	refRoot, _, err := resolveStatusAgainstRoot(ctx, dEnv, refStr)
+	if err != nil {
+		return err
+	}
+	deltas, err := diff.GetTableDeltas(ctx, refRoot, working)
+	if err != nil {
+		return err
+	}
+	deltas = filterStatusByNamespace(relative, deltas)
+	sort.Slice(deltas, func(i, j int) bool {
+		return deltas[i].CurName() < deltas[j].CurName()
+	})
+
+	var lines []string
+	for _, td := range deltas {
+		if doltdb.IsReadOnlySystemTable(td.CurName()) {
+			continue
+		}
+		drifted, err := td.HasSchemaChanged(ctx)
+		if err != nil {
+			return err
+		}
+		if !drifted {
+			continue
+		}
+		name := relativeTableName(td.CurName(), relative)
+		switch {
+		case td.IsAdd():
+			lines = append(lines, fmt.Sprintf("\t%s: not on %s", name, refStr))
+		case td.IsDrop():
+			lines = append(lines, fmt.Sprintf("\t%s: only on %s", name, refStr))
+		default:
+			changes := schemaDeltaSummary(td.FromSch, td.ToSch)
+			if len(changes) == 0 {
+				changes = []string{"indexes or constraints differ"}
+			}
+			lines = append(lines, fmt.Sprintf("\t%s: %s", name, strings.Join(changes, ", ")))
+		}
+	}
+
+	cli.Println()
+	if len(lines) == 0 {
+		cli.Printf("no schema drift from %s\n", refStr)
+		return nil
+	}
+	cli.Printf(schemaDriftHeader+"\n", refStr)
+	cli.Println(color.YellowString(strings.Join(lines, "\n")))
+	return nil
+}
+
+// schemaDeltaSummary returns a compact description of each column change between |from| and |to|: "+name type" for an
+// added column, "-name" for a dropped one, "old -> new" for a renamed one, and "~name old -> new" for one whose type
+// changed. Columns are matched by name, and then by tag to find renames.
+func schemaDeltaSummary(from, to schema.Schema) []string {
+	fromCols, toCols := from.GetAllCols(), to.GetAllCols()
+	renamed := make(map[string]bool)
+
+	var changes []string
+	for _, col := range toCols.GetColumns() {
+		typ := col.TypeInfo.ToSqlType().String()
+		if fromCol, ok := fromCols.GetByName(col.Name); ok {
+			if fromTyp := fromCol.TypeInfo.ToSqlType().String(); fromTyp != typ {
+				changes = append(changes, fmt.Sprintf("~%s %s -> %s", col.Name, fromTyp, typ))
+			}
+		} else if fromCol, ok := fromCols.GetByTag(col.Tag); ok && !toCols.Contains(fromCol.Name) {
+			renamed[fromCol.Name] = true
+			changes = append(changes, fmt.Sprintf("%s -> %s", fromCol.Name, col.Name))
+			if fromTyp := fromCol.TypeInfo.ToSqlType().String(); fromTyp != typ {
+				changes = append(changes, fmt.Sprintf("~%s %s -> %s", col.Name, fromTyp, typ))
+			}
+		} else {
+			changes = append(changes, fmt.Sprintf("+%s %s", col.Name, typ))
+		}
+	}
+
+	for _, col := range fromCols.GetColumns() {
+		if !toCols.Contains(col.Name) && !renamed[col.Name] {
+			changes = append(changes, "-"+col.Name)
+		}
+	}
+
+	return changes
+}
+
+// statusGraphCommit is a single commit shown by status --graph.
+type statusGraphCommit struct {
+	hash        hash.Hash
+	message     string
+	decorations []string
+	// local and upstream are whether the commit is reachable from the current branch and from its upstream
+	local    bool
+	upstream bool
+}
+
+// printStatusGraph prints a graph of the |n| most recent commits reachable from the current branch and its upstream, if
+// it has one, in topological order.
+func printStatusGraph(ctx context.Context, dEnv *env.DoltEnv, n int) error {
+	headRef, err := dEnv.RepoStateReader().CWBHeadRef()
+	if err != nil {
+		return err
+	}
+	headCommit, err := dEnv.HeadCommit(ctx)
+	if err != nil {
+		return err
+	}
+	headHash, err := headCommit.HashOf()
+	if err != nil {
+		return err
+	}
+
+	startHashes := []hash.Hash{headHash}
+	localSet := hash.NewHashSet(headHash)
+	upstreamSet := hash.NewHashSet()
+	decorations := map[hash.Hash][]string{headHash: {"HEAD -> " + headRef.GetPath()}}
+
+	branches, err := dEnv.RepoStateReader().GetBranches()
+	if err != nil {
+		return err
+	}
+	if upstream, ok := branches[headRef.GetPath()]; ok {
+		trackingRef, upstreamCommit, ok, err := resolveUpstreamCommit(ctx, dEnv, upstream)
+		if err != nil {
+			return err
+		}
+		if ok {
+			upstreamHash, err := upstreamCommit.HashOf()
+			if err != nil {
+				return err
+			}
+			startHashes = append(startHashes, upstreamHash)
+			upstreamSet.Insert(upstreamHash)
+			decorations[upstreamHash] = append(decorations[upstreamHash], trackingRef.GetPath())
+		}
+	}
+
+	itr, err := commitwalk.GetTopologicalOrderIterator(ctx, dEnv.DoltDB, startHashes, nil)
+	if err != nil {
+		return err
+	}
+
+	commits := make([]statusGraphCommit, 0, n)
+	for len(commits) < n {
+		h, cm, err := itr.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		meta, err := cm.GetCommitMeta(ctx)
+		if err != nil {
+			return err
+		}
+		parents, err := cm.ParentHashes(ctx)
+		if err != nil {
+			return err
+		}
+
+		// commits are visited before any of their parents, so reachability can be propagated as we go
+		gc := statusGraphCommit{
+			hash:        h,
+			message:     strings.SplitN(meta.Description, "\n", 2)[0],
+			decorations: decorations[h],
+			local:       localSet.Has(h),
+			upstream:    upstreamSet.Has(h),
+		}
+		for _, p := range parents {
+			if gc.local {
+				localSet.Insert(p)
+			}
+			if gc.upstream {
+				upstreamSet.Insert(p)
+			}
+		}
+		commits = append(commits, gc)
+	}
+
+	cli.Println()
+	cli.Println(strings.Join(renderStatusGraph(commits), "\n"))
+	return nil
+}
+
+// renderStatusGraph returns the lines of the graph of |commits|. When the current branch and its upstream have
+// diverged, commits only on the current branch are drawn in the left column and those only on the upstream in the
+// right column, with the columns joining at the first commit they share.
+func renderStatusGraph(commits []statusGraphCommit) []string {
+	localOnly, upstreamOnly := false, false
+	for _, c := range commits {
+		localOnly = localOnly || (c.local && !c.upstream)
+		upstreamOnly = upstreamOnly || (c.upstream && !c.local)
+	}
+	diverged := localOnly && upstreamOnly
+
+	lines := make([]string, 0, len(commits)+1)
+	joined := false
+	for _, c := range commits {
+		prefix := "* "
+		if diverged && !joined {
+			switch {
+			case c.local && c.upstream:
+				lines = append(lines, "|/")
+				joined = true
+			case c.local:
+				prefix = "* | "
+			default:
+				prefix = "| * "
+			}
+		}
+
+		line := prefix + color.YellowString(c.hash.String()[:8])
+		if len(c.decorations) > 0 {
+			line += " " + color.CyanString("("+strings.Join(c.decorations, ", ")+")")
+		}
+		lines = append(lines, line+" "+c.message)
+	}
+	return lines
+}
+
+// printStatusHashes prints the hashes of the working set, its working and staged roots, and the HEAD commit. These are
+// useful when diagnosing a working set that has diverged between servers, e.g. a primary and its replica. Hashes are
+// abbreviated to |abbrev| characters, or printed in full if |abbrev| is 0.
+func printStatusHashes(ctx context.Context, dEnv *env.DoltEnv, ws *doltdb.WorkingSet, abbrev int) error {
+	wsHash, err := ws.HashOf()
+	if err != nil {
+		return err
+	}
+
+	workingHash, err := ws.WorkingRoot().HashOf()
+	if err != nil {
+		return err
+	}
+
+	stagedHash, err := ws.StagedRoot().HashOf()
+	if err != nil {
+		return err
+	}
+
+	headCommit, err := dEnv.HeadCommit(ctx)
+	if err != nil {
+		return err
+	}
+
+	headHash, err := headCommit.HashOf()
+	if err != nil {
+		return err
+	}
+
+	cli.Printf("Working set:  %s\n", cli.AbbreviateHash(wsHash.String(), abbrev))
+	cli.Printf("Working root: %s\n", cli.AbbreviateHash(workingHash.String(), abbrev))
+	cli.Printf("Staged root:  %s\n", cli.AbbreviateHash(stagedHash.String(), abbrev))
+	cli.Printf("HEAD commit:  %s\n", cli.AbbreviateHash(headHash.String(), abbrev))
+	return nil
+}
+
+// statusTableNames returns the names of the tables printed by --name-only. These are the staged tables if
+// |filter| is limited to staged changes, and otherwise the tables with changes not staged for commit followed by the
+// untracked tables which aren't ignored. Tables with conflicts or constraint violations are left out, since they
+// can't be added until they're resolved.
+func statusTableNames(ctx context.Context, roots doltdb.Roots, ws *doltdb.WorkingSet, staged, notStaged []diff.TableDelta, filter StatusFilter) ([]string, error) {
+	as, err := merge.GetMergeArtifactStatus(ctx, ws)
+	if err != nil {
+		return nil, err
+	}
+	if filter.Types != nil {
+		staged, notStaged, _ = filterStatusByType(filter.Types, staged, notStaged, as)
+	}
+	staged = filterStatusByNamespace(filter.Relative, staged)
+	notStaged = filterStatusByNamespace(filter.Relative, notStaged)
+
+	if filter.StagedOnly {
+		names := make([]string, len(staged))
+		for i, td := range staged {
+			names[i] = relativeTableName(td.CurName(), filter.Relative)
+		}
+		return names, nil
+	}
+
+	unmerged := set.NewStrSet(as.DataConflictTables)
+	unmerged.Add(as.SchemaConflictsTables...)
+	unmerged.Add(as.ConstraintViolationsTables...)
+	changed, untracked := notStagedTableNames(notStaged, unmerged)
+
+	filtered, err := doltdb.FilterIgnoredTables(ctx, untracked, roots)
+	if err != nil && doltdb.AsDoltIgnoreInConflict(err) == nil {
+		return nil, err
+	}
+	names := append(changed, filtered.DontIgnore...)
+	for i := range names {
+		names[i] = relativeTableName(names[i], filter.Relative)
+	}
+	return names, nil
+}
+
+// notStagedTableNames splits the tables with changes not staged for commit into the modified or deleted tables and the
+// untracked tables, leaving out those in |unmerged|. As in the rest of the status, a renamed table is both the
+// deletion of its old name and an untracked table with its new name.
+func notStagedTableNames(notStaged []diff.TableDelta, unmerged *set.StrSet) (changed, untracked []string) {
+	for _, td := range notStaged {
+		if unmerged.Contains(td.CurName()) {
+			continue
+		}
+		if td.IsAdd() {
+			untracked = append(untracked, td.CurName())
+		} else if td.IsRename() {
+			changed = append(changed, td.FromName)
+			untracked = append(untracked, td.ToName)
+		} else {
+			changed = append(changed, td.CurName())
+		}
+	}
+	return changed, untracked
+}
+
+// statusWorktree is a branch with a working set, as listed by --worktrees.
+type statusWorktree struct {
+	branch   string
+	head     hash.Hash
+	current  bool
+	staged   int
+	unstaged int
+}
+
+// printStatusWorktrees lists every branch which has a working set, i.e. which has been checked out by the CLI or a
+// SQL session, with its HEAD commit and whether its working set is clean or dirty.
+func printStatusWorktrees(ctx context.Context, dEnv *env.DoltEnv) error {
+	currentRef, err := dEnv.RepoStateReader().CWBHeadRef()
+	if err != nil {
+		return err
+	}
+
+	branches, err := dEnv.DoltDB.GetBranches(ctx)
+	if err != nil {
+		return err
+	}
+
+	var worktrees []statusWorktree
+	for _, branchRef := range branches {
+		wt, ok, err := loadStatusWorktree(ctx, dEnv.DoltDB, branchRef)
+		if err != nil {
+			return err
+		}
+		if ok {
+			wt.current = ref.Equals(branchRef, currentRef)
+			worktrees = append(worktrees, wt)
+		}
+	}
+
+	for _, line := range statusWorktreeLines(worktrees) {
+		cli.Println(line)
+	}
+	return nil
+}
+
+// loadStatusWorktree computes the changes in the working set of |branchRef|, and returns false if it has none.
+func loadStatusWorktree(ctx context.Context, ddb *doltdb.DoltDB, branchRef ref.DoltRef) (statusWorktree, bool, error) {
+	wsRef, err := ref.WorkingSetRefForHead(branchRef)
+	if err != nil {
+		return statusWorktree{}, false, err
+	}
+	ws, err := ddb.ResolveWorkingSet(ctx, wsRef)
+	if err == doltdb.ErrWorkingSetNotFound {
+		return statusWorktree{}, false, nil
+	} else if err != nil {
+		return statusWorktree{}, false, err
+	}
+
+	headCommit, err := ddb.ResolveCommitRef(ctx, branchRef)
+	if err != nil {
+		return statusWorktree{}, false, err
+	}
+	headHash, err := headCommit.HashOf()
+	if err != nil {
+		return statusWorktree{}, false, err
+	}
+	headRoot, err := headCommit.GetRootValue(ctx)
+	if err != nil {
+		return statusWorktree{}, false, err
+	}
+
+	roots := doltdb.Roots{Head: headRoot, Staged: ws.StagedRoot(), Working: ws.WorkingRoot()}
+	staged, unstaged, err := diff.GetStagedUnstagedTableDeltas(ctx, roots)
+	if err != nil {
+		return statusWorktree{}, false, err
+	}
+
+	return statusWorktree{
+		branch:   branchRef.GetPath(),
+		head:     headHash,
+		staged:   len(staged),
+		unstaged: len(unstaged),
+	}, true, nil
+}
+
+// statusWorktreeLines formats the worktrees given as aligned lines, similar to `git worktree list`.
+func statusWorktreeLines(worktrees []statusWorktree) []string {
+	width := 0
+	for _, wt := range worktrees {
+		if len(wt.branch) > width {
+			width = len(wt.branch)
+		}
+	}
+
+	lines := make([]string, len(worktrees))
+	for i, wt := range worktrees {
+		marker := " "
+		if wt.current {
+			marker = "*"
+		}
+		state := "clean"
+		if wt.staged > 0 || wt.unstaged > 0 {
+			state = fmt.Sprintf("dirty (%d staged, %d unstaged)", wt.staged, wt.unstaged)
+		}
+		lines[i] = fmt.Sprintf("%s %-*s  %s  %s", marker, width, wt.branch, wt.head.String()[:8], state)
+	}
+	return lines
+}
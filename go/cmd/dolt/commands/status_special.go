@@ -0,0 +1,213 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fatih/color"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/utils/iohelp"
+)
+
+// StatusLineFunc returns the line printed by status for a changed special table. |staged| is true if |td| is a change
+// staged for commit, and false if it is a change in the working set.
+type StatusLineFunc func(td diff.TableDelta, staged bool) string
+
+var specialStatusTables = make(map[string]StatusLineFunc)
+
+// RegisterSpecialStatusTable marks the table named as special, so that status reports changes to it in a dedicated
+// section using the line returned by |lineFn| rather than listing it with ordinary tables. Applications use this for
+// tables with their own workflow, such as schema migration tracking tables. Should be called during initialization.
+func RegisterSpecialStatusTable(tableName string, lineFn StatusLineFunc) {
+	specialStatusTables[tableName] = lineFn
+}
+
+// StatusClassification is how status presents a changed table, as decided by a StatusClassifier.
+type StatusClassification struct {
+	// Category is the header of the section the table is listed in, instead of with ordinary tables, e.g.
+	// "Generated tables:".
+	Category string
+	// Annotation, if not empty, is appended to the table's line, e.g. "(regenerated by the build)".
+	Annotation string
+}
+
+// StatusClassifier decides how status presents the changed table |td|, and returns false to leave it with ordinary
+// tables. |staged| is true if |td| is a change staged for commit, and false if it is a change in the working set.
+type StatusClassifier func(td diff.TableDelta, staged bool) (StatusClassification, bool)
+
+var statusClassifiers []StatusClassifier
+
+// RegisterStatusClassifier adds a classifier which status consults for each changed table, other than special tables
+// registered with RegisterSpecialStatusTable. The first classifier registered which classifies a table decides how it's
+// presented. Applications use this for groups of tables with a common purpose, such as generated or audit tables.
+// Should be called during initialization.
+func RegisterStatusClassifier(classifier StatusClassifier) {
+	statusClassifiers = append(statusClassifiers, classifier)
+}
+
+// splitSpecialStatusTables separates the deltas for tables registered with RegisterSpecialStatusTable from the rest.
+func splitSpecialStatusTables(deltas []diff.TableDelta) (ordinary, special []diff.TableDelta) {
+	if len(specialStatusTables) == 0 {
+		return deltas, nil
+	}
+
+	ordinary = make([]diff.TableDelta, 0, len(deltas))
+	for _, td := range deltas {
+		if isSpecialStatusTable(td) {
+			special = append(special, td)
+		} else {
+			ordinary = append(ordinary, td)
+		}
+	}
+	return ordinary, special
+}
+
+func isSpecialStatusTable(td diff.TableDelta) bool {
+	if _, ok := specialStatusTables[td.CurName()]; ok {
+		return true
+	}
+	_, ok := specialStatusTables[td.FromName]
+	return ok
+}
+
+func specialStatusLineFunc(td diff.TableDelta) StatusLineFunc {
+	if lineFn, ok := specialStatusTables[td.CurName()]; ok {
+		return lineFn
+	}
+	return specialStatusTables[td.FromName]
+}
+
+// printSpecialStatusTables prints the section for changed special tables and returns the updated number of lines printed.
+func printSpecialStatusTables(wr io.Writer, staged, notStaged []diff.TableDelta, linesPrinted int) int {
+	if len(staged)+len(notStaged) == 0 {
+		return linesPrinted
+	}
+
+	if linesPrinted > 0 {
+		cli.Println()
+	}
+	iohelp.WriteLine(wr, specialTablesHeader)
+
+	lines := make([]string, 0, len(staged)+len(notStaged))
+	for _, td := range staged {
+		lines = append(lines, "\t"+specialStatusLineFunc(td)(td, true))
+	}
+	for _, td := range notStaged {
+		lines = append(lines, "\t"+specialStatusLineFunc(td)(td, false))
+	}
+	iohelp.WriteLine(wr, strings.Join(lines, "\n"))
+
+	return linesPrinted + len(lines)
+}
+
+// classifiedStatusTable is a changed table classified by one of the registered StatusClassifiers.
+type classifiedStatusTable struct {
+	td     diff.TableDelta
+	staged bool
+	StatusClassification
+}
+
+// classifyStatusTables splits |deltas| into the tables which none of the registered classifiers classify, and those
+// which one does.
+func classifyStatusTables(deltas []diff.TableDelta, staged bool) (ordinary []diff.TableDelta, classified []classifiedStatusTable) {
+	if len(statusClassifiers) == 0 {
+		return deltas, nil
+	}
+
+	ordinary = make([]diff.TableDelta, 0, len(deltas))
+	for _, td := range deltas {
+		if c, ok := classifyStatusTable(td, staged); ok {
+			classified = append(classified, classifiedStatusTable{td: td, staged: staged, StatusClassification: c})
+		} else {
+			ordinary = append(ordinary, td)
+		}
+	}
+	return ordinary, classified
+}
+
+func classifyStatusTable(td diff.TableDelta, staged bool) (StatusClassification, bool) {
+	for _, classifier := range statusClassifiers {
+		if c, ok := classifier(td, staged); ok && c.Category != "" {
+			return c, true
+		}
+	}
+	return StatusClassification{}, false
+}
+
+// printClassifiedStatusTables prints a section for each category of the classified tables given, in the order they
+// first appear, and returns the updated number of lines printed. Staged changes are green, and unstaged changes red,
+// as in the rest of the status.
+func printClassifiedStatusTables(wr io.Writer, classified []classifiedStatusTable, linesPrinted int, relative string) (int, error) {
+	var categories []string
+	byCategory := make(map[string][]string)
+	for _, ct := range classified {
+		line, err := classifiedStatusLine(ct, relative)
+		if err != nil {
+			return 0, err
+		}
+		if _, ok := byCategory[ct.Category]; !ok {
+			categories = append(categories, ct.Category)
+		}
+		byCategory[ct.Category] = append(byCategory[ct.Category], line)
+	}
+
+	for _, category := range categories {
+		if linesPrinted > 0 {
+			cli.Println()
+		}
+		iohelp.WriteLine(wr, category)
+		lines := byCategory[category]
+		iohelp.WriteLine(wr, strings.Join(lines, "\n"))
+		linesPrinted += len(lines)
+	}
+	return linesPrinted, nil
+}
+
+// classifiedStatusLine returns the line printed for a classified table, labeled with its kind of change like an ordinary
+// table, with its names relative to the namespace |relative|.
+func classifiedStatusLine(ct classifiedStatusTable, relative string) (string, error) {
+	var line string
+	switch {
+	case ct.td.IsAdd():
+		line = fmt.Sprintf(statusFmt, tblDiffTypeToLabel[diff.AddedTable], relativeTableName(ct.td.CurName(), relative))
+	case ct.td.IsDrop():
+		line = fmt.Sprintf(statusFmt, tblDiffTypeToLabel[diff.RemovedTable], relativeTableName(ct.td.CurName(), relative))
+	case ct.td.IsRename():
+		renamedAndModified, err := ct.td.IsRenameAndModify()
+		if err != nil {
+			return "", err
+		}
+		diffType := diff.RenamedTable
+		if renamedAndModified {
+			diffType = diff.RenamedModifiedTable
+		}
+		line = fmt.Sprintf(statusRenameFmt, tblDiffTypeToLabel[diffType], relativeTableName(ct.td.FromName, relative), relativeTableName(ct.td.ToName, relative))
+	default:
+		line = fmt.Sprintf(statusFmt, tblDiffTypeToLabel[diff.ModifiedTable], relativeTableName(ct.td.CurName(), relative))
+	}
+	if ct.Annotation != "" {
+		line += " " + ct.Annotation
+	}
+
+	if ct.staged {
+		return color.GreenString(line), nil
+	}
+	return color.RedString(line), nil
+}
@@ -0,0 +1,313 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/utils/set"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/types"
+)
+
+func TestSpecialStatusTables(t *testing.T) {
+	RegisterSpecialStatusTable("migrations", func(td diff.TableDelta, staged bool) string {
+		if staged {
+			return "staged migrations"
+		}
+		return "pending migrations"
+	})
+	defer delete(specialStatusTables, "migrations")
+
+	deltas := []diff.TableDelta{
+		{FromName: "t1", ToName: "t1"},
+		{FromName: "migrations", ToName: "migrations"},
+		{FromName: "old_migrations", ToName: "t2"},
+	}
+
+	ordinary, special := splitSpecialStatusTables(deltas)
+	require.Len(t, ordinary, 2)
+	require.Len(t, special, 1)
+	assert.Equal(t, "migrations", special[0].CurName())
+
+	buf := &bytes.Buffer{}
+	n := printSpecialStatusTables(buf, nil, special, 0)
+	assert.Equal(t, 1, n)
+	assert.Contains(t, buf.String(), specialTablesHeader)
+	assert.Contains(t, buf.String(), "\tpending migrations")
+}
+
+func TestStatusClassifiers(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	RegisterStatusClassifier(func(td diff.TableDelta, staged bool) (StatusClassification, bool) {
+		if strings.HasPrefix(td.CurName(), "gen_") {
+			return StatusClassification{Category: "Generated tables:", Annotation: "(regenerated by the build)"}, true
+		}
+		return StatusClassification{}, false
+	})
+	defer func() { statusClassifiers = nil }()
+
+	tbl := &doltdb.Table{}
+	deltas := []diff.TableDelta{
+		{FromName: "t1", ToName: "t1", FromTable: tbl, ToTable: tbl},
+		{ToName: "gen_t1", ToTable: tbl},
+	}
+
+	ordinary, classified := classifyStatusTables(deltas, true)
+	require.Len(t, ordinary, 1)
+	require.Len(t, classified, 1)
+	assert.Equal(t, "t1", ordinary[0].CurName())
+
+	buf := &bytes.Buffer{}
+	n, err := printClassifiedStatusTables(buf, classified, 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, "Generated tables:\n\tnew table:        gen_t1 (regenerated by the build)\n", buf.String())
+}
+
+func TestGetRemoteTrackingMsg(t *testing.T) {
+	msg := getRemoteTrackingMsg("origin/main", 0, 2)
+	assert.Contains(t, msg, "behind 'origin/main' by 2 commits, and can be fast-forwarded")
+
+	msg = getRemoteTrackingMsg("origin/main", 1, 2)
+	assert.Contains(t, msg, "have diverged")
+	assert.Contains(t, msg, "can't fast-forward your branch, and will create a merge commit")
+	assert.NotContains(t, msg, "can be fast-forwarded")
+
+	msg = getRemoteTrackingMsg("origin/main", 1, 0)
+	assert.Contains(t, msg, "ahead of 'origin/main' by 1 commit.")
+
+	msg = getRemoteTrackingMsg("origin/main", 0, 0)
+	assert.Equal(t, "Your branch is up to date with 'origin/main'.", msg)
+}
+
+func TestGetRemoteTrackingMsgDivergedNeverFastForwards(t *testing.T) {
+	for ahead := 1; ahead <= 3; ahead++ {
+		for behind := 1; behind <= 3; behind++ {
+			msg := getRemoteTrackingMsg("origin/main", ahead, behind)
+			assert.Contains(t, msg, "have diverged")
+			assert.Contains(t, msg, "can't fast-forward")
+			assert.NotContains(t, msg, "can be fast-forwarded")
+		}
+	}
+}
+
+func TestCheckStatusExclusiveArgs(t *testing.T) {
+	ap := StatusCmd{}.ArgParser()
+	tests := []struct {
+		args []string
+		err  string
+	}{
+		{[]string{"--staged", "--relative", "t_"}, ""},
+		{[]string{"--staged", "--unstaged"}, "error: cannot use both --staged and --unstaged"},
+		{[]string{"--no-tracking", "--ahead-only"}, "error: cannot use both --ahead-only and --no-tracking"},
+		{[]string{"--name-only", "--storage"}, "error: cannot use both --name-only and --storage"},
+		{[]string{"--conflicts-only", "--watch"}, "error: cannot use both --watch and --conflicts-only"},
+	}
+	for _, test := range tests {
+		apr, err := ap.Parse(test.args)
+		require.NoError(t, err)
+		err = checkStatusExclusiveArgs(apr)
+		if test.err == "" {
+			assert.NoError(t, err, strings.Join(test.args, " "))
+		} else {
+			assert.EqualError(t, err, test.err)
+		}
+	}
+}
+
+func TestRenderStatusGraph(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	h1 := hash.Of([]byte("local"))
+	h2 := hash.Of([]byte("upstream"))
+	h3 := hash.Of([]byte("base"))
+
+	lines := renderStatusGraph([]statusGraphCommit{
+		{hash: h1, message: "local", decorations: []string{"HEAD -> main"}, local: true},
+		{hash: h2, message: "upstream", decorations: []string{"origin/main"}, upstream: true},
+		{hash: h3, message: "base", local: true, upstream: true},
+	})
+	assert.Equal(t, []string{
+		"* | " + h1.String()[:8] + " (HEAD -> main) local",
+		"| * " + h2.String()[:8] + " (origin/main) upstream",
+		"|/",
+		"* " + h3.String()[:8] + " base",
+	}, lines)
+
+	lines = renderStatusGraph([]statusGraphCommit{
+		{hash: h1, message: "local", decorations: []string{"HEAD -> main"}, local: true},
+		{hash: h3, message: "base", decorations: []string{"origin/main"}, local: true, upstream: true},
+	})
+	assert.Equal(t, []string{
+		"* " + h1.String()[:8] + " (HEAD -> main) local",
+		"* " + h3.String()[:8] + " (origin/main) base",
+	}, lines)
+}
+
+func TestTrackingWindow(t *testing.T) {
+	now := time.Date(2023, 6, 7, 12, 0, 0, 0, time.UTC)
+	since, err := parseTrackingTime("24h", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-24*time.Hour), since)
+
+	until, err := parseTrackingTime("2023-06-07T06:00:00", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2023, 6, 7, 6, 0, 0, 0, time.UTC), until)
+
+	_, err = parseTrackingTime("yesterday", now)
+	assert.Error(t, err)
+
+	opts := TrackingOptions{Since: since, Until: until}
+	assert.True(t, opts.hasWindow())
+	assert.Equal(t, "between 2023-06-06 12:00:00 and 2023-06-07 06:00:00", opts.windowDesc())
+
+	assert.True(t, opts.inWindow(now.Add(-12*time.Hour)))
+	assert.False(t, opts.inWindow(now.Add(-48*time.Hour)))
+	assert.False(t, opts.inWindow(now))
+
+	assert.False(t, TrackingOptions{}.hasWindow())
+}
+
+func TestSchemaDeltaSummary(t *testing.T) {
+	from := schema.MustSchemaFromCols(schema.NewColCollection(
+		schema.NewColumn("pk", 0, types.IntKind, true),
+		schema.NewColumn("a", 1, types.IntKind, false),
+		schema.NewColumn("b", 2, types.IntKind, false),
+		schema.NewColumn("c", 3, types.IntKind, false),
+	))
+	to := schema.MustSchemaFromCols(schema.NewColCollection(
+		schema.NewColumn("pk", 0, types.IntKind, true),
+		schema.NewColumn("a", 1, types.StringKind, false),
+		schema.NewColumn("b2", 2, types.IntKind, false),
+		schema.NewColumn("d", 4, types.IntKind, false),
+	))
+
+	intType := from.GetAllCols().GetByIndex(1).TypeInfo.ToSqlType().String()
+	strType := to.GetAllCols().GetByIndex(1).TypeInfo.ToSqlType().String()
+	assert.Equal(t, []string{
+		"~a " + intType + " -> " + strType,
+		"b -> b2",
+		"+d " + intType,
+		"-c",
+	}, schemaDeltaSummary(from, to))
+
+	assert.Empty(t, schemaDeltaSummary(from, from))
+}
+
+func TestUnmergedTableLines(t *testing.T) {
+	assert.Equal(t, []string{
+		"\tschema conflict:  t",
+		"\tboth modified:    t",
+	}, unmergedTableLines(merge.TableArtifactStatus{Name: "t", SchemaConflict: true, DataConflicts: true, ConstraintViolations: true}))
+	assert.Equal(t, []string{
+		"\tmodified          t",
+	}, unmergedTableLines(merge.TableArtifactStatus{Name: "t", ConstraintViolations: true}))
+	assert.Empty(t, unmergedTableLines(merge.TableArtifactStatus{Name: "t"}))
+}
+
+func TestStatusWorktreeLines(t *testing.T) {
+	h1 := hash.Of([]byte("main"))
+	h2 := hash.Of([]byte("feature"))
+
+	assert.Equal(t, []string{
+		"  feature  " + h2.String()[:8] + "  dirty (1 staged, 2 unstaged)",
+		"* main     " + h1.String()[:8] + "  clean",
+	}, statusWorktreeLines([]statusWorktree{
+		{branch: "feature", head: h2, staged: 1, unstaged: 2},
+		{branch: "main", head: h1, current: true},
+	}))
+}
+
+func TestNotStagedTableNames(t *testing.T) {
+	tbl := &doltdb.Table{}
+	changed, untracked := notStagedTableNames([]diff.TableDelta{
+		{FromName: "modified", ToName: "modified", FromTable: tbl, ToTable: tbl},
+		{FromName: "dropped", FromTable: tbl},
+		{ToName: "added", ToTable: tbl},
+		{FromName: "old", ToName: "new", FromTable: tbl, ToTable: tbl},
+		{FromName: "conflicted", ToName: "conflicted", FromTable: tbl, ToTable: tbl},
+	}, set.NewStrSet([]string{"conflicted"}))
+	assert.Equal(t, []string{"modified", "dropped", "old"}, changed)
+	assert.Equal(t, []string{"added", "new"}, untracked)
+}
+
+func TestFilterStatusByNamespace(t *testing.T) {
+	deltas := []diff.TableDelta{
+		{FromName: "acme_orders", ToName: "acme_orders"},
+		{FromName: "globex_orders", ToName: "globex_orders"},
+		{FromName: "acme_old", ToName: "orders_archive"},
+	}
+
+	filtered := filterStatusByNamespace("acme_", deltas)
+	require.Len(t, filtered, 2)
+	assert.Equal(t, "orders", relativeTableName(filtered[0].CurName(), "acme_"))
+	assert.Equal(t, "orders_archive", relativeTableName(filtered[1].CurName(), "acme_"))
+
+	assert.Equal(t, deltas, filterStatusByNamespace("", deltas))
+}
+
+func TestPrintStatusFormat(t *testing.T) {
+	tbl := &doltdb.Table{}
+	result := StatusResult{Branch: "main", Upstream: "origin/main", Ahead: 1}
+	assert.True(t, result.Clean())
+	result.addTable(statusTableForDelta(diff.TableDelta{FromName: "t1", ToName: "t1", FromTable: tbl, ToTable: tbl}, true, ""))
+	result.addTable(statusTableForDelta(diff.TableDelta{FromName: "acme_old", ToName: "acme_new", FromTable: tbl, ToTable: tbl}, false, "acme_"))
+	result.addTable(statusTableForDelta(diff.TableDelta{ToName: "t2", ToTable: tbl}, false, ""))
+	assert.Equal(t, 1, result.Staged)
+	assert.Equal(t, 1, result.Unstaged)
+	assert.Equal(t, 1, result.Untracked)
+	assert.Equal(t, 3, result.Changes)
+	assert.False(t, result.Clean())
+
+	tmpl, err := parseStatusFormat("{{.Status}} {{.Name}}{{if .OldName}} <- {{.OldName}}{{end}}{{if .Staged}} (staged){{end}}")
+	require.NoError(t, err)
+	buf := &bytes.Buffer{}
+	require.NoError(t, printStatusFormat(buf, tmpl, result, false))
+	assert.Equal(t, "modified t1 (staged)\nrenamed new <- old\nadded t2\n", buf.String())
+
+	tmpl, err = parseStatusFormat("{{.Branch}}...{{.Upstream}} +{{.Ahead}} -{{.Behind}} {{len .Tables}} clean={{.Clean}}")
+	require.NoError(t, err)
+	buf.Reset()
+	require.NoError(t, printStatusFormat(buf, tmpl, result, true))
+	assert.Equal(t, "main...origin/main +1 -0 3 clean=false\n", buf.String())
+
+	conflicted := StatusResult{}
+	conflicted.addTable(StatusTable{Name: "t3", Status: conflictedStatusType})
+	assert.Equal(t, 0, conflicted.Changes)
+	assert.False(t, conflicted.Clean())
+
+	_, err = parseStatusFormat("{{.Name")
+	assert.Error(t, err)
+	tmpl, err = parseStatusFormat("{{.Missing}}")
+	require.NoError(t, err)
+	assert.Error(t, printStatusFormat(buf, tmpl, result, false))
+}
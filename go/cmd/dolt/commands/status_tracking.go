@@ -0,0 +1,477 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/fatih/color"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// TrackingOptions control how PrintStatus reports the current branch's relation to its upstream.
+type TrackingOptions struct {
+	// ShowUpstreamTime prints the age of the upstream's latest commit when the branch is behind it
+	ShowUpstreamTime bool
+	// Since and Until, when non-zero, limit the commits counted as ahead or behind to those made within them. Commits
+	// outside the window are skipped rather than ending the walk, as merged history isn't ordered by commit time.
+	Since, Until time.Time
+	// Skip leaves out the relation to the upstream entirely, without resolving it
+	Skip bool
+	// LogUnpushed lists the commits which haven't been pushed when the branch is ahead of its upstream
+	LogUnpushed bool
+}
+
+func (o TrackingOptions) hasWindow() bool {
+	return !o.Since.IsZero() || !o.Until.IsZero()
+}
+
+// windowDesc describes the time window commits are counted in, for use in status messages.
+func (o TrackingOptions) windowDesc() string {
+	const layout = "2006-01-02 15:04:05"
+	switch {
+	case !o.Since.IsZero() && !o.Until.IsZero():
+		return fmt.Sprintf("between %s and %s", o.Since.Format(layout), o.Until.Format(layout))
+	case !o.Since.IsZero():
+		return "since " + o.Since.Format(layout)
+	default:
+		return "until " + o.Until.Format(layout)
+	}
+}
+
+// inWindow returns whether |t| is within the time window.
+func (o TrackingOptions) inWindow(t time.Time) bool {
+	if !o.Since.IsZero() && t.Before(o.Since) {
+		return false
+	}
+	return o.Until.IsZero() || !t.After(o.Until)
+}
+
+// printRemoteRefTrackingInfo prints remote tracking information if there is a remote branch set upstream from current
+// branch. If remote.pushDefault names a different remote than the upstream's, the branch's relation to its tracking
+// branch on that remote is printed too, with each labelled by whether it's the fetch or the push remote.
+func printRemoteRefTrackingInfo(ctx context.Context, dEnv *env.DoltEnv, tracking TrackingOptions) error {
+	ddb := dEnv.DoltDB
+	headCommit, upstream, remoteTrackingRef, remoteCommit, ok, err := resolveHeadAndUpstreamCommits(ctx, dEnv)
+	if err != nil || !ok {
+		return err
+	}
+	ahead, behind, err := countAheadBehind(ctx, ddb, headCommit, remoteCommit, tracking)
+	if err != nil {
+		return err
+	}
+
+	// In a triangular workflow the branch is pushed to a different remote than it's fetched from
+	pushRemote := dEnv.Config.GetStringOrDefault(env.PushDefaultRemote, "")
+	if pushRemote != "" && pushRemote != upstream.Remote {
+		pushUpstream := env.BranchConfig{Merge: upstream.Merge, Remote: pushRemote}
+		pushTrackingRef, pushCommit, ok, err := resolveUpstreamCommit(ctx, dEnv, pushUpstream)
+		if errors.Is(err, doltdb.ErrBranchNotFound) {
+			// the branch hasn't been pushed to, or fetched from, the push remote yet
+			ok, err = false, nil
+		}
+		if err != nil {
+			return err
+		}
+		if ok {
+			pushAhead, pushBehind, err := countAheadBehind(ctx, ddb, headCommit, pushCommit, tracking)
+			if err != nil {
+				return err
+			}
+			cli.Printf(fetchRemoteTrackingHeader+"\n", upstream.Remote)
+			cli.Println(getRemoteTrackingMsg(remoteTrackingRef.GetPath(), ahead, behind))
+			err = printUpstreamRewrittenWarning(ctx, dEnv, headCommit, remoteCommit, remoteTrackingRef, ahead, behind)
+			if err != nil {
+				return err
+			}
+			cli.Printf(pushRemoteTrackingHeader+"\n", pushRemote)
+			cli.Println(getRemoteTrackingMsg(pushTrackingRef.GetPath(), pushAhead, pushBehind))
+			err = printTrackingNotes(ctx, remoteCommit, behind, tracking)
+			if err != nil || !tracking.LogUnpushed || pushAhead == 0 {
+				return err
+			}
+			return printUnpushedCommits(ctx, ddb, headCommit, pushCommit, tracking)
+		}
+	}
+
+	cli.Println(getRemoteTrackingMsg(remoteTrackingRef.GetPath(), ahead, behind))
+	err = printUpstreamRewrittenWarning(ctx, dEnv, headCommit, remoteCommit, remoteTrackingRef, ahead, behind)
+	if err != nil {
+		return err
+	}
+	err = printTrackingNotes(ctx, remoteCommit, behind, tracking)
+	if err != nil || !tracking.LogUnpushed || ahead == 0 {
+		return err
+	}
+	return printUnpushedCommits(ctx, ddb, headCommit, remoteCommit, tracking)
+}
+
+const upstreamRewrittenWarningFmt = `warning: '%[1]s' was rewritten, e.g. by a force push, since your branch was last updated from
+it. Your branch has commits from it that the upstream no longer has.
+  (use "dolt reset --hard %[1]s" to match the rewritten upstream, after cherry-picking any commits you want to keep)`
+
+// printUpstreamRewrittenWarning prints a warning if the current branch has diverged from its upstream because the
+// upstream's history was rewritten, rather than because commits were made on both.
+func printUpstreamRewrittenWarning(ctx context.Context, dEnv *env.DoltEnv, headCommit, remoteCommit *doltdb.Commit, trackingRef ref.DoltRef, ahead, behind int) error {
+	if ahead == 0 || behind == 0 {
+		return nil
+	}
+	rewritten, err := upstreamRewritten(ctx, dEnv, headCommit, remoteCommit, trackingRef)
+	if err != nil || !rewritten {
+		return err
+	}
+	cli.Println(color.YellowString(upstreamRewrittenWarningFmt, trackingRef.GetPath()))
+	return nil
+}
+
+// upstreamRewritten returns whether the reflog shows that the diverged upstream |remoteCommit| was rewritten: that a
+// previous tip of |trackingRef|, recorded by a fetch or pull, is in the history of the branch but no longer in the
+// history of the upstream. Going back from the latest update, the search stops at the first previous tip the upstream
+// still has. Without such a record, the divergence is taken to be ordinary.
+func upstreamRewritten(ctx context.Context, dEnv *env.DoltEnv, headCommit, remoteCommit *doltdb.Commit, trackingRef ref.DoltRef) (bool, error) {
+	entries, err := env.LoadReflog(dEnv.FS)
+	if err != nil {
+		return false, err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Ref != trackingRef.String() {
+			continue
+		}
+		prevHash, ok := hash.MaybeParse(entries[i].FromHash)
+		if !ok {
+			continue
+		}
+		prevCommit, err := dEnv.DoltDB.ReadCommit(ctx, prevHash)
+		if err != nil {
+			// the previous tip may no longer exist locally
+			continue
+		}
+		kept, err := isAncestorCommit(ctx, prevCommit, remoteCommit)
+		if err != nil {
+			return false, err
+		}
+		if kept {
+			return false, nil
+		}
+		onBranch, err := isAncestorCommit(ctx, prevCommit, headCommit)
+		if err != nil {
+			return false, err
+		}
+		if onBranch {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// isAncestorCommit returns whether |ancestor| is |descendant| or one of its ancestors.
+func isAncestorCommit(ctx context.Context, ancestor, descendant *doltdb.Commit) (bool, error) {
+	ancCommit, err := doltdb.GetCommitAncestor(ctx, ancestor, descendant)
+	if errors.Is(err, doltdb.ErrNoCommonAncestor) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	ancHash, err := ancCommit.HashOf()
+	if err != nil {
+		return false, err
+	}
+	h, err := ancestor.HashOf()
+	if err != nil {
+		return false, err
+	}
+	return ancHash == h, nil
+}
+
+const unpushedCommitsHeader = "Unpushed commits:"
+
+// printUnpushedCommits lists the commits on |headCommit| back to its common ancestor with |upstreamCommit|, which are
+// those counted as ahead of the upstream, with their short hashes and subjects.
+func printUnpushedCommits(ctx context.Context, ddb *doltdb.DoltDB, headCommit, upstreamCommit *doltdb.Commit, tracking TrackingOptions) error {
+	headHash, err := headCommit.HashOf()
+	if err != nil {
+		return err
+	}
+	ancCommit, err := doltdb.GetCommitAncestor(ctx, headCommit, upstreamCommit)
+	if err != nil {
+		return err
+	}
+	ancHash, err := ancCommit.HashOf()
+	if err != nil {
+		return err
+	}
+
+	itr, err := commitwalk.GetTopologicalOrderIterator(ctx, ddb, []hash.Hash{headHash}, nil)
+	if err != nil {
+		return err
+	}
+
+	cli.Println(unpushedCommitsHeader)
+	for {
+		h, cm, err := itr.Next(ctx)
+		if err == io.EOF || h == ancHash {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		meta, err := cm.GetCommitMeta(ctx)
+		if err != nil {
+			return err
+		}
+		if tracking.hasWindow() {
+			if !tracking.inWindow(meta.Time()) {
+				continue
+			}
+		}
+		cli.Printf("\t%s %s\n", color.YellowString(h.String()[:8]), strings.SplitN(meta.Description, "\n", 2)[0])
+	}
+}
+
+// resolveHeadAndUpstreamCommits returns the head commit of the current branch, its upstream, and the ref and commit of
+// the upstream's remote tracking branch. ok is false if the branch has no upstream, or its remote doesn't exist.
+func resolveHeadAndUpstreamCommits(ctx context.Context, dEnv *env.DoltEnv) (headCommit *doltdb.Commit, upstream env.BranchConfig, trackingRef ref.DoltRef, remoteCommit *doltdb.Commit, ok bool, err error) {
+	rsr := dEnv.RepoStateReader()
+	headRef, err := rsr.CWBHeadRef()
+	if err != nil {
+		return nil, upstream, nil, nil, false, err
+	}
+	branches, err := rsr.GetBranches()
+	if err != nil {
+		return nil, upstream, nil, nil, false, err
+	}
+	upstream, hasUpstream := branches[headRef.GetPath()]
+	if !hasUpstream {
+		return nil, upstream, nil, nil, false, nil
+	}
+
+	// Get local head branch
+	headCommitSpec, err := doltdb.NewCommitSpec(headRef.GetPath())
+	if err != nil {
+		return nil, upstream, nil, nil, false, err
+	}
+	headCommit, err = dEnv.DoltDB.Resolve(ctx, headCommitSpec, headRef)
+	if err != nil {
+		return nil, upstream, nil, nil, false, err
+	}
+
+	// Get remote tracking branch
+	trackingRef, remoteCommit, ok, err = resolveUpstreamCommit(ctx, dEnv, upstream)
+	if err != nil || !ok {
+		return nil, upstream, nil, nil, false, err
+	}
+	return headCommit, upstream, trackingRef, remoteCommit, true, nil
+}
+
+// printAheadBehindCount prints just the number of commits the current branch is ahead of or behind its upstream, as
+// selected by --ahead-only or --behind-only, for use by scripts and hooks.
+func printAheadBehindCount(ctx context.Context, dEnv *env.DoltEnv, apr *argparser.ArgParseResults, tracking TrackingOptions) int {
+	if apr.Contains(statusAheadOnlyFlag) && apr.Contains(statusBehindOnlyFlag) {
+		return handleStatusVErr(fmt.Errorf("error: cannot use both --%s and --%s", statusAheadOnlyFlag, statusBehindOnlyFlag))
+	}
+
+	headCommit, _, _, remoteCommit, ok, err := resolveHeadAndUpstreamCommits(ctx, dEnv)
+	if err != nil {
+		return handleStatusVErr(err)
+	}
+	if !ok {
+		return handleStatusVErr(errors.New("error: the current branch has no upstream branch"))
+	}
+
+	ahead, behind, err := countAheadBehind(ctx, dEnv.DoltDB, headCommit, remoteCommit, tracking)
+	if err != nil {
+		return handleStatusVErr(err)
+	}
+
+	if apr.Contains(statusAheadOnlyFlag) {
+		cli.Println(ahead)
+	} else {
+		cli.Println(behind)
+	}
+	return 0
+}
+
+const (
+	fetchRemoteTrackingHeader = "Relative to the fetch remote '%s':"
+	pushRemoteTrackingHeader  = "Relative to the push remote '%s':"
+)
+
+// countAheadBehind returns the number of commits in |tracking|'s window that |headCommit| has which |remoteCommit|
+// doesn't, and the number that |remoteCommit| has which |headCommit| doesn't.
+func countAheadBehind(ctx context.Context, ddb *doltdb.DoltDB, headCommit, remoteCommit *doltdb.Commit, tracking TrackingOptions) (ahead, behind int, err error) {
+	headHash, err := headCommit.HashOf()
+	if err != nil {
+		return 0, 0, err
+	}
+	remoteHash, err := remoteCommit.HashOf()
+	if err != nil {
+		return 0, 0, err
+	}
+	if headHash == remoteHash {
+		return 0, 0, nil
+	}
+
+	// get common ancestor
+	ancCommit, err := doltdb.GetCommitAncestor(ctx, headCommit, remoteCommit)
+	if err != nil {
+		return 0, 0, err
+	}
+	ancHash, err := ancCommit.HashOf()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	behind, err = countCommitsInRange(ctx, ddb, remoteHash, ancHash, tracking)
+	if err != nil {
+		return 0, 0, err
+	}
+	ahead, err = countCommitsInRange(ctx, ddb, headHash, ancHash, tracking)
+	if err != nil {
+		return 0, 0, err
+	}
+	return ahead, behind, nil
+}
+
+// printTrackingNotes prints the notes requested by |tracking| beneath the tracking info for the upstream commit
+// |remoteCommit|, which the current branch is |behind| by.
+func printTrackingNotes(ctx context.Context, remoteCommit *doltdb.Commit, behind int, tracking TrackingOptions) error {
+	if tracking.hasWindow() {
+		cli.Printf("  (only counting commits made %s)\n", tracking.windowDesc())
+	}
+
+	if tracking.ShowUpstreamTime && behind > 0 {
+		meta, err := remoteCommit.GetCommitMeta(ctx)
+		if err != nil {
+			return err
+		}
+		cli.Printf("  (upstream updated %s)\n", humanize.Time(time.UnixMilli(int64(meta.Timestamp))))
+	}
+
+	return nil
+}
+
+// resolveUpstreamCommit returns the remote tracking ref for |upstream| and the commit it points to. If the remote of
+// |upstream| no longer exists, ok is false.
+func resolveUpstreamCommit(ctx context.Context, dEnv *env.DoltEnv, upstream env.BranchConfig) (trackingRef ref.DoltRef, cm *doltdb.Commit, ok bool, err error) {
+	remotes, err := dEnv.RepoStateReader().GetRemotes()
+	if err != nil {
+		return nil, nil, false, err
+	}
+	remote, ok := remotes[upstream.Remote]
+	if !ok {
+		return nil, nil, false, nil
+	}
+	trackingRef, err = env.GetTrackingRef(upstream.Merge.Ref, remote)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	cs, err := doltdb.NewCommitSpec(trackingRef.GetPath())
+	if err != nil {
+		return nil, nil, false, err
+	}
+	cm, err = dEnv.DoltDB.Resolve(ctx, cs, trackingRef)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	return trackingRef, cm, true, nil
+}
+
+// countCommitsInRange returns the number of commits between the given starting point to trace back to the given target point.
+// The starting commit must be a descendant of the target commit. Target commit must be a common ancestor commit. If
+// |tracking| has a time window, only commits made within it are counted. Commit dates aren't ordered along the history,
+// so commits outside the window are skipped rather than ending the walk, which still visits every commit between the
+// two, however narrow the window is.
+func countCommitsInRange(ctx context.Context, ddb *doltdb.DoltDB, startCommitHash, targetCommitHash hash.Hash, tracking TrackingOptions) (int, error) {
+	itr, iErr := commitwalk.GetTopologicalOrderIterator(ctx, ddb, []hash.Hash{startCommitHash}, nil)
+	if iErr != nil {
+		return 0, iErr
+	}
+	count := 0
+	for {
+		hash, cm, err := itr.Next(ctx)
+		if err == io.EOF {
+			return 0, errors.New("no match found to ancestor commit")
+		} else if err != nil {
+			return 0, err
+		}
+
+		if hash == targetCommitHash {
+			break
+		}
+
+		if tracking.hasWindow() {
+			meta, err := cm.GetCommitMeta(ctx)
+			if err != nil {
+				return 0, err
+			}
+			if !tracking.inWindow(meta.Time()) {
+				continue
+			}
+		}
+		count += 1
+	}
+
+	return count, nil
+}
+
+// parseTrackingTime parses the value of --since or --until, which is either a date in any format accepted by
+// cli.ParseDate, or a duration before |now|.
+func parseTrackingTime(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	return cli.ParseDate(s)
+}
+
+// getRemoteTrackingMsg returns remote tracking information with given remote branch name, number of commits ahead and/or behind.
+func getRemoteTrackingMsg(remoteBranchName string, ahead int, behind int) string {
+	if behind > 0 {
+		// only a branch without commits of its own can be fast-forwarded to its upstream
+		if ahead == 0 {
+			s := ""
+			if behind > 1 {
+				s = "s"
+			}
+			return fmt.Sprintf(`Your branch is behind '%s' by %v commit%s, and can be fast-forwarded.
+  (use "dolt pull" to update your local branch)`, remoteBranchName, behind, s)
+		}
+		return fmt.Sprintf(`Your branch and '%s' have diverged,
+and have %v and %v different commits each, respectively.
+Pulling can't fast-forward your branch, and will create a merge commit.
+  (use "dolt pull" to merge the remote branch into yours)`, remoteBranchName, ahead, behind)
+	} else if ahead > 0 {
+		s := ""
+		if ahead > 1 {
+			s = "s"
+		}
+		return fmt.Sprintf(`Your branch is ahead of '%s' by %v commit%s.
+  (use "dolt push" to publish your local commits)`, remoteBranchName, ahead, s)
+	} else {
+		return fmt.Sprintf("Your branch is up to date with '%s'.", remoteBranchName)
+	}
+}
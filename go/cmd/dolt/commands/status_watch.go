@@ -0,0 +1,126 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commands
+
+import (
+	"context"
+	"time"
+
+	"github.com/fatih/color"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+)
+
+// statusWatchInterval is how often dolt status --watch checks whether the working set has changed.
+const statusWatchInterval = time.Second
+
+// clearScreen moves the cursor to the top left of the terminal and clears it.
+const clearScreen = "\033[H\033[2J"
+
+// watchStatus clears the terminal and calls |render| whenever the current branch, its HEAD commit or its working set
+// changes, checking every statusWatchInterval, until |ctx| is canceled by an interrupt.
+func watchStatus(ctx context.Context, dEnv *env.DoltEnv, render func() error) int {
+	var lastKey string
+	for {
+		key, err := statusWatchKey(ctx, dEnv)
+		if ctx.Err() != nil {
+			return 0
+		}
+		if err != nil {
+			return handleStatusVErr(err)
+		}
+
+		if key != lastKey {
+			cli.Print(clearScreen)
+			err = render()
+			if ctx.Err() != nil {
+				return 0
+			}
+			if err != nil {
+				return handleStatusVErr(err)
+			}
+			lastKey = key
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0
+		case <-time.After(statusWatchInterval):
+		}
+	}
+}
+
+// statusWatchKey reloads the repo state and the database from disk, since they may have been changed by another
+// process, and returns the statusStateKey of the result.
+func statusWatchKey(ctx context.Context, dEnv *env.DoltEnv) (string, error) {
+	err := dEnv.DoltDB.Rebase(ctx)
+	if err != nil {
+		return "", err
+	}
+	rs, err := env.LoadRepoState(dEnv.FS)
+	if err != nil {
+		return "", err
+	}
+	dEnv.RepoState = rs
+	return statusStateKey(ctx, dEnv)
+}
+
+const staleStatusWarning = `warning: the branch was changed by another session while its status was computed, so the status may be out of date.
+  (use "dolt status" again to see its current status)`
+
+// printStaleStatusWarning reloads the database from disk and prints a warning if the current branch's HEAD commit or
+// working set is no longer the one with the state key |stateKey|, which the status was computed from. This happens
+// when another session, such as a SQL server, commits to or writes to the branch at the same time.
+func printStaleStatusWarning(ctx context.Context, dEnv *env.DoltEnv, stateKey string) error {
+	err := dEnv.DoltDB.Rebase(ctx)
+	if err != nil {
+		return err
+	}
+	latestKey, err := statusStateKey(ctx, dEnv)
+	if err != nil {
+		return err
+	}
+	if latestKey != stateKey {
+		cli.PrintErrln(color.YellowString(staleStatusWarning))
+	}
+	return nil
+}
+
+// statusStateKey returns a key which changes whenever the current branch, its HEAD commit or its working set does.
+func statusStateKey(ctx context.Context, dEnv *env.DoltEnv) (string, error) {
+	headRef, err := dEnv.RepoStateReader().CWBHeadRef()
+	if err != nil {
+		return "", err
+	}
+	headCommit, err := dEnv.HeadCommit(ctx)
+	if err != nil {
+		return "", err
+	}
+	headHash, err := headCommit.HashOf()
+	if err != nil {
+		return "", err
+	}
+	ws, err := dEnv.WorkingSet(ctx)
+	if err != nil {
+		return "", err
+	}
+	wsHash, err := ws.HashOf()
+	if err != nil {
+		return "", err
+	}
+
+	return headRef.String() + " " + headHash.String() + " " + wsHash.String(), nil
+}
@@ -176,6 +176,26 @@ func CheckAccess(ctx context.Context, flags Permissions) error {
 	if branchAwareSession == nil {
 		return nil
 	}
+	branch, err := branchAwareSession.GetBranch()
+	if err != nil {
+		return err
+	}
+	return checkAccessForBranch(branchAwareSession, branch, flags)
+}
+
+// CheckAccessForBranch returns whether the given context has the correct permissions on the branch given, which need
+// not be its selected branch. As with CheckAccess, contexts without a user associated, such as those of most CLI
+// commands, are always allowed access.
+func CheckAccessForBranch(ctx context.Context, branch string, flags Permissions) error {
+	branchAwareSession := GetBranchAwareSession(ctx)
+	// A nil session means we're not in the SQL context, so we allow all operations
+	if branchAwareSession == nil {
+		return nil
+	}
+	return checkAccessForBranch(branchAwareSession, branch, flags)
+}
+
+func checkAccessForBranch(branchAwareSession Context, branch string, flags Permissions) error {
 	controller := branchAwareSession.GetController()
 	// Any context that has a non-nil session should always have a non-nil controller, so this is an error
 	if controller == nil {
@@ -187,10 +207,6 @@ func CheckAccess(ctx context.Context, flags Permissions) error {
 	user := branchAwareSession.GetUser()
 	host := branchAwareSession.GetHost()
 	database := branchAwareSession.GetCurrentDatabase()
-	branch, err := branchAwareSession.GetBranch()
-	if err != nil {
-		return err
-	}
 	// Get the permissions for the branch, user, and host combination
 	_, perms := controller.Access.Match(database, branch, user, host)
 	// If either the flags match or the user is an admin for this branch, then we allow access
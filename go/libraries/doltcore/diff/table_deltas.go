@@ -39,6 +39,7 @@ const (
 	ModifiedTable
 	RenamedTable
 	RemovedTable
+	RenamedModifiedTable
 )
 
 // TableDelta represents the change of a single table between two roots.
@@ -70,7 +71,9 @@ type TableDeltaSummary struct {
 	ToTableName   string
 }
 
-// GetStagedUnstagedTableDeltas represents staged and unstaged changes as TableDelta slices.
+// GetStagedUnstagedTableDeltas represents staged and unstaged changes as TableDelta slices. The two are computed
+// independently, so a table can appear in both: e.g. a new table which is staged and then dropped in the working set is
+// an add in |staged| and a drop in |unstaged|.
 func GetStagedUnstagedTableDeltas(ctx context.Context, roots doltdb.Roots) (staged, unstaged []TableDelta, err error) {
 	staged, err = GetTableDeltas(ctx, roots.Head, roots.Staged)
 	if err != nil {
@@ -88,6 +91,13 @@ func GetStagedUnstagedTableDeltas(ctx context.Context, roots doltdb.Roots) (stag
 // GetTableDeltas returns a slice of TableDelta objects for each table that changed between fromRoot and toRoot.
 // It matches tables across roots by finding Schemas with Column tags in common.
 func GetTableDeltas(ctx context.Context, fromRoot, toRoot *doltdb.RootValue) (deltas []TableDelta, err error) {
+	// Identical roots have no deltas, which saves loading every table of a clean working set
+	if same, err := rootsHaveSameHash(fromRoot, toRoot); err != nil {
+		return nil, err
+	} else if same {
+		return nil, nil
+	}
+
 	fromVRW := fromRoot.VRW()
 	fromNS := fromRoot.NodeStore()
 	toVRW := toRoot.VRW()
@@ -170,6 +180,19 @@ func GetTableDeltas(ctx context.Context, fromRoot, toRoot *doltdb.RootValue) (de
 	return deltas, nil
 }
 
+// rootsHaveSameHash returns whether |fromRoot| and |toRoot| have the same hash, and so the same contents.
+func rootsHaveSameHash(fromRoot, toRoot *doltdb.RootValue) (bool, error) {
+	fromHash, err := fromRoot.HashOf()
+	if err != nil {
+		return false, err
+	}
+	toHash, err := toRoot.HashOf()
+	if err != nil {
+		return false, err
+	}
+	return !fromHash.IsEmpty() && fromHash == toHash, nil
+}
+
 func getFkParentSchs(ctx context.Context, root *doltdb.RootValue, fks ...doltdb.ForeignKey) (map[string]schema.Schema, error) {
 	schs := make(map[string]schema.Schema)
 	for _, toFk := range fks {
@@ -293,7 +316,9 @@ func (td TableDelta) IsDrop() bool {
 	return td.FromTable != nil && td.ToTable == nil
 }
 
-// IsRename return true if the table was renamed between the fromRoot and toRoot.
+// IsRename return true if the table was renamed between the fromRoot and toRoot. Names are compared case-sensitively,
+// so a rename that only changes the case of a table's name, e.g. Orders to orders, is reported as a rename even though
+// table names are otherwise resolved case-insensitively.
 func (td TableDelta) IsRename() bool {
 	if td.IsAdd() || td.IsDrop() {
 		return false
@@ -301,6 +326,15 @@ func (td TableDelta) IsRename() bool {
 	return td.FromName != td.ToName
 }
 
+// IsRenameAndModify returns true if the table was renamed between the fromRoot and toRoot, and its schema or data was
+// also changed.
+func (td TableDelta) IsRenameAndModify() (bool, error) {
+	if !td.IsRename() {
+		return false, nil
+	}
+	return td.HasHashChanged()
+}
+
 // HasHashChanged returns true if the hash of the table content has changed between
 // the fromRoot and toRoot.
 func (td TableDelta) HasHashChanged() (bool, error) {
@@ -376,10 +410,142 @@ func (td TableDelta) HasDataChanged(ctx context.Context) (bool, error) {
 	return !fromRowDataHash.Equal(toRowDataHash), nil
 }
 
+// HasOnlyAutoIncrementChanged returns true if the auto increment value of the table is all that changed between the
+// fromRoot and toRoot, as happens when rows are inserted and then deleted again.
+func (td TableDelta) HasOnlyAutoIncrementChanged(ctx context.Context) (bool, error) {
+	if td.IsAdd() || td.IsDrop() || td.IsRename() || td.HasFKChanges() {
+		return false, nil
+	}
+
+	fromVal, err := td.FromTable.GetAutoIncrementValue(ctx)
+	if err != nil {
+		return false, err
+	}
+	toVal, err := td.ToTable.GetAutoIncrementValue(ctx)
+	if err != nil {
+		return false, err
+	}
+	if fromVal == toVal {
+		return false, nil
+	}
+
+	// the table is otherwise unchanged if restoring its auto increment value restores its hash
+	restored, err := td.ToTable.SetAutoIncrementValue(ctx, fromVal)
+	if err != nil {
+		return false, err
+	}
+	restoredHash, err := restored.HashOf()
+	if err != nil {
+		return false, err
+	}
+	fromHash, err := td.FromTable.HashOf()
+	if err != nil {
+		return false, err
+	}
+	return restoredHash.Equal(fromHash), nil
+}
+
+// HasOnlyCollationChanged returns true if the collation, and so the character set, of the table or of some of its
+// columns is all that changed between the fromRoot and toRoot. The rows must be unchanged, so a table whose rows were
+// rewritten for the new collation, as happens when it changes for a primary key column, isn't included.
+func (td TableDelta) HasOnlyCollationChanged(ctx context.Context) (bool, error) {
+	if td.IsAdd() || td.IsDrop() || td.IsRename() || td.HasFKChanges() {
+		return false, nil
+	}
+	if !schemasDifferOnlyInCollation(td.FromSch, td.ToSch) {
+		return false, nil
+	}
+	dataChanged, err := td.HasDataChanged(ctx)
+	if err != nil {
+		return false, err
+	}
+	return !dataChanged, nil
+}
+
+// schemasDifferOnlyInCollation returns true if |from| and |to| differ, but only in the collation of the table or in
+// the collations of their columns' types.
+func schemasDifferOnlyInCollation(from, to schema.Schema) bool {
+	if from == nil || to == nil || schema.SchemasAreEqual(from, to) {
+		return false
+	}
+
+	fromCols, toCols := from.GetAllCols(), to.GetAllCols()
+	if fromCols.Size() != toCols.Size() || !tagsEqual(from.GetPKCols().Tags, to.GetPKCols().Tags) {
+		return false
+	}
+	for i := 0; i < fromCols.Size(); i++ {
+		fromCol, toCol := fromCols.GetByIndex(i), toCols.GetByIndex(i)
+		if !fromCol.TypeInfo.Equals(toCol.TypeInfo) {
+			if !withDefaultCollation(fromCol.TypeInfo.ToSqlType()).Equals(withDefaultCollation(toCol.TypeInfo.ToSqlType())) {
+				return false
+			}
+			toCol.TypeInfo = fromCol.TypeInfo
+		}
+		if !fromCol.Equals(toCol) {
+			return false
+		}
+	}
+
+	if (from.Checks() == nil) != (to.Checks() == nil) {
+		return false
+	}
+	if from.Checks() != nil && !from.Checks().Equals(to.Checks()) {
+		return false
+	}
+	return from.Indexes().Equals(to.Indexes())
+}
+
+// withDefaultCollation returns |typ| with the default collation if it has a collation, so that types which differ only
+// in their collation compare as equal.
+func withDefaultCollation(typ sql.Type) sql.Type {
+	collatedType, ok := typ.(sql.TypeWithCollation)
+	if !ok {
+		return typ
+	}
+	defaultType, err := collatedType.WithNewCollation(sql.Collation_Default)
+	if err != nil {
+		return typ
+	}
+	return defaultType
+}
+
+func tagsEqual(tags1, tags2 []uint64) bool {
+	if len(tags1) != len(tags2) {
+		return false
+	}
+	for i := range tags1 {
+		if tags1[i] != tags2[i] {
+			return false
+		}
+	}
+	return true
+}
+
 func (td TableDelta) HasPrimaryKeySetChanged() bool {
 	return !schema.ArePrimaryKeySetsDiffable(td.Format(), td.FromSch, td.ToSch)
 }
 
+// RebuiltIndexes returns the names of the secondary indexes of the table at the toRoot, sorted, whose index data had to
+// be built from the table's rows rather than carried over from the fromRoot: indexes which are new, or whose columns,
+// properties or primary key changed. It's based on the schemas alone, so it doesn't load any index data.
+func (td TableDelta) RebuiltIndexes() []string {
+	if td.ToSch == nil {
+		return nil
+	}
+
+	var names []string
+	for _, idx := range td.ToSch.Indexes().AllIndexes() {
+		if td.FromSch != nil {
+			if fromIdx := td.FromSch.Indexes().GetByName(idx.Name()); fromIdx != nil && fromIdx.DeepEquals(idx) {
+				continue
+			}
+		}
+		names = append(names, idx.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
 func (td TableDelta) HasChanges() (bool, error) {
 	hashChanged, err := td.HasHashChanged()
 	if err != nil {
@@ -560,6 +726,65 @@ func (td TableDelta) GetRowData(ctx context.Context) (from, to durable.Index, er
 	return from, to, nil
 }
 
+// GetStorageGrowth returns an estimate of the on-disk size of the chunks of the table's row data and secondary indexes
+// at the toRoot which aren't at the fromRoot, and of those at the fromRoot which aren't at the toRoot. Out-of-band
+// values, such as large TEXT and BLOB values, aren't included. It's only supported for the __DOLT__ format.
+func (td TableDelta) GetStorageGrowth(ctx context.Context) (added, removed uint64, err error) {
+	if !types.IsFormat_DOLT(td.Format()) {
+		return 0, 0, fmt.Errorf("storage growth is only supported for the %s format", types.Format_DOLT.VersionString())
+	}
+
+	fromNodes, err := tableStorageNodes(ctx, td.FromTable, td.FromSch)
+	if err != nil {
+		return 0, 0, err
+	}
+	toNodes, err := tableStorageNodes(ctx, td.ToTable, td.ToSch)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// the row data is keyed by "", and secondary indexes by their names
+	names := set.NewStrSet(nil)
+	for name := range fromNodes {
+		names.Add(name)
+	}
+	for name := range toNodes {
+		names.Add(name)
+	}
+	for _, name := range names.AsSortedSlice() {
+		a, r, err := tree.SizeDiff(ctx, td.ToNodeStore, fromNodes[name], toNodes[name])
+		if err != nil {
+			return 0, 0, err
+		}
+		added, removed = added+a, removed+r
+	}
+	return added, removed, nil
+}
+
+// tableStorageNodes returns the root nodes of the row data and secondary indexes of |tbl|, keyed by index name, with
+// the row data keyed by "". It returns no nodes if |tbl| is nil.
+func tableStorageNodes(ctx context.Context, tbl *doltdb.Table, sch schema.Schema) (map[string]tree.Node, error) {
+	nodes := make(map[string]tree.Node)
+	if tbl == nil {
+		return nodes, nil
+	}
+
+	rows, err := tbl.GetRowData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	nodes[""] = durable.ProllyMapFromIndex(rows).Node()
+
+	for _, idx := range sch.Indexes().AllIndexes() {
+		idxRows, err := tbl.GetIndexRowData(ctx, idx.Name())
+		if err != nil {
+			return nil, err
+		}
+		nodes[idx.Name()] = durable.ProllyMapFromIndex(idxRows).Node()
+	}
+	return nodes, nil
+}
+
 // SqlSchemaDiff returns a slice of DDL statements that will transform the schema in the from delta to the schema in
 // the to delta.
 func SqlSchemaDiff(ctx context.Context, td TableDelta, toSchemas map[string]schema.Schema) ([]string, error) {
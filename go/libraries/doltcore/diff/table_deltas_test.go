@@ -17,9 +17,13 @@ package diff
 import (
 	"testing"
 
+	"github.com/dolthub/go-mysql-server/sql"
+	gmstypes "github.com/dolthub/go-mysql-server/sql/types"
+	"github.com/dolthub/vitess/go/sqltypes"
 	"github.com/stretchr/testify/require"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/schema"
+	"github.com/dolthub/dolt/go/libraries/doltcore/schema/typeinfo"
 	"github.com/dolthub/dolt/go/store/types"
 )
 
@@ -38,6 +42,9 @@ var sch4 = schema.MustSchemaFromCols(schema.NewColCollection(
 var sch5 = schema.MustSchemaFromCols(schema.NewColCollection(
 	schema.NewColumn("pk5", 4, types.StringKind, false),
 ))
+var sch6 = schema.MustSchemaFromCols(schema.NewColCollection(
+	schema.NewColumn("pk6", 5, types.StringKind, false),
+))
 
 func TestMatchTableDeltas(t *testing.T) {
 	var fromDeltas = []TableDelta{
@@ -45,16 +52,19 @@ func TestMatchTableDeltas(t *testing.T) {
 		{FromName: "dropped", FromSch: sch},
 		{FromName: "dropped2", FromSch: sch3},
 		{FromName: "renamed_before", FromSch: sch5},
+		{FromName: "Case_Renamed", FromSch: sch6},
 	}
 	var toDeltas = []TableDelta{
 		{ToName: "should_match_on_name", ToSch: sch},
 		{ToName: "added", ToSch: sch2},
 		{ToName: "added2", ToSch: sch4},
 		{ToName: "renamed_after", ToSch: sch5},
+		{ToName: "case_renamed", ToSch: sch6},
 	}
 	expected := []TableDelta{
 		{FromName: "should_match_on_name", ToName: "should_match_on_name", FromSch: sch, ToSch: sch},
 		{FromName: "renamed_before", ToName: "renamed_after", FromSch: sch5, ToSch: sch5},
+		{FromName: "Case_Renamed", ToName: "case_renamed", FromSch: sch6, ToSch: sch6},
 		{FromName: "dropped", FromSch: sch},
 		{FromName: "dropped2", FromSch: sch3},
 		{ToName: "added", ToSch: sch2},
@@ -66,3 +76,52 @@ func TestMatchTableDeltas(t *testing.T) {
 		require.ElementsMatch(t, expected, received)
 	}
 }
+
+func TestRebuiltIndexes(t *testing.T) {
+	newSch := func(indexes map[string][]string) schema.Schema {
+		s := schema.MustSchemaFromCols(schema.NewColCollection(
+			schema.NewColumn("pk", 0, types.IntKind, true),
+			schema.NewColumn("a", 1, types.IntKind, false),
+			schema.NewColumn("b", 2, types.IntKind, false),
+		))
+		for name, cols := range indexes {
+			_, err := s.Indexes().AddIndexByColNames(name, cols, nil, schema.IndexProperties{IsUserDefined: true})
+			require.NoError(t, err)
+		}
+		return s
+	}
+
+	from := newSch(map[string][]string{"kept": {"a"}, "redefined": {"a"}, "dropped": {"b"}})
+	to := newSch(map[string][]string{"kept": {"a"}, "redefined": {"a", "b"}, "new": {"b"}})
+
+	td := TableDelta{FromName: "t", ToName: "t", FromSch: from, ToSch: to}
+	require.Equal(t, []string{"new", "redefined"}, td.RebuiltIndexes())
+
+	td = TableDelta{FromName: "t", ToName: "t", FromSch: from, ToSch: from}
+	require.Empty(t, td.RebuiltIndexes())
+
+	td = TableDelta{ToName: "t", ToSch: to}
+	require.Equal(t, []string{"kept", "new", "redefined"}, td.RebuiltIndexes())
+
+	td = TableDelta{FromName: "t", FromSch: from}
+	require.Empty(t, td.RebuiltIndexes())
+}
+
+func TestSchemasDifferOnlyInCollation(t *testing.T) {
+	newSch := func(length int64, collation sql.CollationID, tableCollation schema.Collation) schema.Schema {
+		ti, err := typeinfo.FromSqlType(gmstypes.MustCreateString(sqltypes.VarChar, length, collation))
+		require.NoError(t, err)
+		col, err := schema.NewColumnWithTypeInfo("c", 1, ti, false, "", false, "")
+		require.NoError(t, err)
+		s := schema.MustSchemaFromCols(schema.NewColCollection(schema.NewColumn("pk", 0, types.IntKind, true), col))
+		s.SetCollation(tableCollation)
+		return s
+	}
+
+	from := newSch(20, sql.Collation_utf8mb4_0900_bin, schema.Collation_utf8mb4_0900_bin)
+	require.False(t, schemasDifferOnlyInCollation(from, from))
+	require.True(t, schemasDifferOnlyInCollation(from, newSch(20, sql.Collation_latin1_swedish_ci, schema.Collation_utf8mb4_0900_bin)))
+	require.True(t, schemasDifferOnlyInCollation(from, newSch(20, sql.Collation_utf8mb4_0900_bin, schema.Collation_latin1_swedish_ci)))
+	require.False(t, schemasDifferOnlyInCollation(from, newSch(30, sql.Collation_latin1_swedish_ci, schema.Collation_utf8mb4_0900_bin)))
+	require.False(t, schemasDifferOnlyInCollation(from, sch))
+}
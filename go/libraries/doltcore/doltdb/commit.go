@@ -234,6 +234,9 @@ type PendingCommit struct {
 	Roots         Roots
 	Val           types.Value
 	CommitOptions datas.CommitOptions
+	// NextStaged, if not nil, is the staged root to leave in the working set once the commit is written, for a commit
+	// of something other than everything staged. Otherwise the committed root, Roots.Staged, stays staged.
+	NextStaged *RootValue
 }
 
 // NewPendingCommit returns a new PendingCommit object to be written with doltdb.CommitWithWorkingSet.
@@ -1315,6 +1315,38 @@ func (ddb *DoltDB) CommitWithWorkingSet(
 	return NewCommit(ctx, ddb.vrw, ddb.ns, dc)
 }
 
+// PreviewCommitHash returns the hash the pending commit given would have if it were committed to |headRef| now, without
+// writing the commit or updating the ref. Parents are filled in the same way as CommitWithWorkingSet.
+func (ddb *DoltDB) PreviewCommitHash(ctx context.Context, headRef ref.DoltRef, commit *PendingCommit) (hash.Hash, error) {
+	headDs, err := ddb.db.GetDataset(ctx, headRef.String())
+	if err != nil {
+		return hash.Hash{}, err
+	}
+
+	opts := commit.CommitOptions
+	if len(opts.Parents) > 0 && !opts.ExactParents {
+		if headHash, ok := headDs.MaybeHeadAddr(); ok {
+			hasHead := false
+			for _, h := range opts.Parents {
+				if h == headHash {
+					hasHead = true
+					break
+				}
+			}
+			if !hasHead {
+				opts.Parents = append([]hash.Hash{headHash}, opts.Parents...)
+			}
+		}
+	}
+
+	dc, err := ddb.db.BuildNewCommit(ctx, headDs, commit.Roots.Staged.nomsValue(), opts)
+	if err != nil {
+		return hash.Hash{}, err
+	}
+
+	return dc.Addr(), nil
+}
+
 // DeleteWorkingSet deletes the working set given
 func (ddb *DoltDB) DeleteWorkingSet(ctx context.Context, workingSetRef ref.WorkingSetRef) error {
 	ds, err := ddb.db.GetDataset(ctx, workingSetRef.String())
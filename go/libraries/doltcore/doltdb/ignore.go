@@ -36,6 +36,16 @@ type IgnoredTables struct {
 	Ignore     []string
 	DontIgnore []string
 	Conflicts  []DoltIgnoreConflictError
+	// Rules are the rules which decided whether each table in Ignore and DontIgnore is ignored. Tables which didn't
+	// match any pattern have no rule.
+	Rules map[string]IgnoreRule
+}
+
+// IgnoreRule is a row of dolt_ignore: a table name pattern, and whether tables matching it are ignored or, for a
+// negation rule, not ignored.
+type IgnoreRule struct {
+	Pattern string
+	Ignore  bool
 }
 
 // IgnoreResult is an enum containing the result of matching a table name against the list of ignored table patterns
@@ -197,7 +207,36 @@ func resolveConflictingPatterns(trueMatches, falseMatches []string, tableName st
 	return IgnorePatternConflict, DoltIgnoreConflictError{Table: tableName, TruePatterns: conflictingTrueMatches, FalsePatterns: conflictingFalseMatches}
 }
 
+// mostSpecificPattern returns the pattern in |patterns| which no other pattern in it is more specific than.
+func mostSpecificPattern(patterns []string) (string, error) {
+	for _, candidate := range patterns {
+		candidateRegExp, err := getMoreSpecificPatterns(candidate)
+		if err != nil {
+			return "", err
+		}
+		mostSpecific := true
+		for _, other := range patterns {
+			if normalizePattern(other) != normalizePattern(candidate) && candidateRegExp.MatchString(other) {
+				mostSpecific = false
+				break
+			}
+		}
+		if mostSpecific {
+			return candidate, nil
+		}
+	}
+	return patterns[0], nil
+}
+
 func (ip *IgnorePatterns) IsTableNameIgnored(tableName string) (IgnoreResult, error) {
+	result, _, err := ip.IgnoreRuleForTableName(tableName)
+	return result, err
+}
+
+// IgnoreRuleForTableName is like IsTableNameIgnored, but also returns the rule which decided the result: the most
+// specific of the patterns matching the table name which agrees with it. The rule is nil if no pattern matches the
+// table name, or if the patterns matching it conflict.
+func (ip *IgnorePatterns) IgnoreRuleForTableName(tableName string) (IgnoreResult, *IgnoreRule, error) {
 	trueMatches := []string{}
 	falseMatches := []string{}
 	for _, patternIgnore := range *ip {
@@ -205,7 +244,7 @@ func (ip *IgnorePatterns) IsTableNameIgnored(tableName string) (IgnoreResult, er
 		ignore := patternIgnore.ignore
 		patternRegExp, err := compilePattern(pattern)
 		if err != nil {
-			return ErrorOccurred, err
+			return ErrorOccurred, nil, err
 		}
 		if patternRegExp.MatchString(tableName) {
 			if ignore {
@@ -215,13 +254,30 @@ func (ip *IgnorePatterns) IsTableNameIgnored(tableName string) (IgnoreResult, er
 			}
 		}
 	}
-	if len(trueMatches) == 0 {
-		return DontIgnore, nil
+	if len(trueMatches) == 0 && len(falseMatches) == 0 {
+		return DontIgnore, nil, nil
 	}
-	if len(falseMatches) == 0 {
-		return Ignore, nil
+
+	result := DontIgnore
+	if len(trueMatches) > 0 && len(falseMatches) == 0 {
+		result = Ignore
+	} else if len(trueMatches) > 0 {
+		// The table name matched both positive and negative patterns.
+		// More specific patterns override less specific patterns.
+		var err error
+		result, err = resolveConflictingPatterns(trueMatches, falseMatches, tableName)
+		if err != nil {
+			return result, nil, err
+		}
+	}
+
+	matches := falseMatches
+	if result == Ignore {
+		matches = trueMatches
+	}
+	pattern, err := mostSpecificPattern(matches)
+	if err != nil {
+		return ErrorOccurred, nil, err
 	}
-	// The table name matched both positive and negative patterns.
-	// More specific patterns override less specific patterns.
-	return resolveConflictingPatterns(trueMatches, falseMatches, tableName)
+	return result, &IgnoreRule{Pattern: pattern, Ignore: result == Ignore}, nil
 }
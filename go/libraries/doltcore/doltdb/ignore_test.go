@@ -0,0 +1,52 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package doltdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIgnoreRuleForTableName(t *testing.T) {
+	patterns := IgnorePatterns{
+		{pattern: "generated_*", ignore: true},
+		{pattern: "generated_keep_*", ignore: false},
+		{pattern: "generated_keep_tmp", ignore: true},
+		{pattern: "scratch", ignore: false},
+	}
+
+	tests := []struct {
+		table    string
+		expected IgnoreResult
+		rule     *IgnoreRule
+	}{
+		{"users", DontIgnore, nil},
+		{"generated_x", Ignore, &IgnoreRule{Pattern: "generated_*", Ignore: true}},
+		{"generated_keep_x", DontIgnore, &IgnoreRule{Pattern: "generated_keep_*", Ignore: false}},
+		{"generated_keep_tmp", Ignore, &IgnoreRule{Pattern: "generated_keep_tmp", Ignore: true}},
+		{"scratch", DontIgnore, &IgnoreRule{Pattern: "scratch", Ignore: false}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.table, func(t *testing.T) {
+			result, rule, err := patterns.IgnoreRuleForTableName(test.table)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, result)
+			assert.Equal(t, test.rule, rule)
+		})
+	}
+}
@@ -1062,12 +1062,21 @@ func FilterIgnoredTables(ctx context.Context, tables []string, roots Roots) (ign
 		return ignoredTables, err
 	}
 	for _, tableName := range tables {
-		ignored, err := ignorePatterns.IsTableNameIgnored(tableName)
+		ignored, rule, err := ignorePatterns.IgnoreRuleForTableName(tableName)
 		if conflict := AsDoltIgnoreInConflict(err); conflict != nil {
 			ignoredTables.Conflicts = append(ignoredTables.Conflicts, *conflict)
+			continue
 		} else if err != nil {
 			return ignoredTables, err
-		} else if ignored == DontIgnore {
+		}
+
+		if rule != nil {
+			if ignoredTables.Rules == nil {
+				ignoredTables.Rules = make(map[string]IgnoreRule)
+			}
+			ignoredTables.Rules[tableName] = *rule
+		}
+		if ignored == DontIgnore {
 			ignoredTables.DontIgnore = append(ignoredTables.DontIgnore, tableName)
 		} else if ignored == Ignore {
 			ignoredTables.Ignore = append(ignoredTables.Ignore, tableName)
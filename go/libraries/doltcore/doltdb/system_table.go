@@ -306,6 +306,9 @@ const (
 	// TagsTableName is the tags table name
 	TagsTableName = "dolt_tags"
 
+	// ReflogTableName is the reflog system table name
+	ReflogTableName = "dolt_reflog"
+
 	IgnoreTableName = "dolt_ignore"
 )
 
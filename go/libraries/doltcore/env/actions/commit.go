@@ -16,8 +16,11 @@ package actions
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	"golang.org/x/sync/errgroup"
+
 	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/store/datas"
@@ -32,6 +35,11 @@ type CommitStagedProps struct {
 	Force      bool
 	Name       string
 	Email      string
+	// FixedTimestamp uses Date as the commit's internal timestamp as well as its user timestamp, instead of the current
+	// time, so that the commit hash depends only on the commit's contents, parents and these props.
+	FixedTimestamp bool
+	// Metadata is the user-defined key/value metadata of the commit, if any.
+	Metadata map[string]string
 }
 
 // GetCommitStaged returns a new pending commit with the roots and commit properties given.
@@ -106,6 +114,146 @@ func GetCommitStaged(
 	if err != nil {
 		return nil, err
 	}
+	if props.FixedTimestamp {
+		meta.Timestamp = uint64(props.Date.UnixMilli())
+	}
+	meta.Metadata = props.Metadata
 
 	return db.NewPendingCommit(ctx, roots, mergeParents, meta)
 }
+
+// CommitRowStats summarizes the number of rows added, modified and deleted by a commit.
+type CommitRowStats struct {
+	Added    uint64
+	Modified uint64
+	Deleted  uint64
+}
+
+// GetCommitRowStats returns the number of rows changed between |fromRoot| and |toRoot|. When |exact| is false, no rows
+// are read or sampled: the net change in the row count of each changed table is reported as rows added or deleted. This
+// is cheap, but doesn't count modified rows, or inserts and deletes that cancel each other out. When |exact| is true,
+// the row data of every changed table is diffed.
+func GetCommitRowStats(ctx context.Context, fromRoot, toRoot *doltdb.RootValue, exact bool) (CommitRowStats, error) {
+	deltas, err := diff.GetTableDeltas(ctx, fromRoot, toRoot)
+	if err != nil {
+		return CommitRowStats{}, err
+	}
+
+	var stats CommitRowStats
+	for _, td := range deltas {
+		if exact {
+			err = addExactRowStats(ctx, td, &stats)
+		} else {
+			err = addEstimatedRowStats(ctx, td, &stats)
+		}
+		if err != nil {
+			return CommitRowStats{}, err
+		}
+	}
+
+	return stats, nil
+}
+
+// GetCommitRowStatsForCommit returns the number of rows changed by |commit| relative to its first parent. A commit with
+// no parents is reported as changing no rows.
+func GetCommitRowStatsForCommit(ctx context.Context, commit *doltdb.Commit, exact bool) (CommitRowStats, error) {
+	toRoot, err := commit.GetRootValue(ctx)
+	if err != nil {
+		return CommitRowStats{}, err
+	}
+
+	fromRoot := toRoot
+	if commit.NumParents() > 0 {
+		parent, err := commit.GetParent(ctx, 0)
+		if err != nil {
+			return CommitRowStats{}, err
+		}
+		fromRoot, err = parent.GetRootValue(ctx)
+		if err != nil {
+			return CommitRowStats{}, err
+		}
+	}
+
+	return GetCommitRowStats(ctx, fromRoot, toRoot, exact)
+}
+
+func addEstimatedRowStats(ctx context.Context, td diff.TableDelta, stats *CommitRowStats) error {
+	from, to, err := td.GetRowData(ctx)
+	if err != nil {
+		return err
+	}
+	fromCnt, err := from.Count()
+	if err != nil {
+		return err
+	}
+	toCnt, err := to.Count()
+	if err != nil {
+		return err
+	}
+
+	if toCnt > fromCnt {
+		stats.Added += toCnt - fromCnt
+	} else {
+		stats.Deleted += fromCnt - toCnt
+	}
+	return nil
+}
+
+func addExactRowStats(ctx context.Context, td diff.TableDelta, stats *CommitRowStats) error {
+	ch := make(chan diff.DiffStatProgress)
+
+	grp, ctx2 := errgroup.WithContext(ctx)
+	grp.Go(func() error {
+		defer close(ch)
+		return diff.StatForTableDelta(ctx2, ch, td)
+	})
+
+	var acc diff.DiffStatProgress
+	grp.Go(func() error {
+		for {
+			select {
+			case p, ok := <-ch:
+				if !ok {
+					return nil
+				}
+				acc.Adds += p.Adds
+				acc.Removes += p.Removes
+				acc.Changes += p.Changes
+			case <-ctx2.Done():
+				return ctx2.Err()
+			}
+		}
+	})
+
+	err := grp.Wait()
+	if errors.Is(err, diff.ErrPrimaryKeySetChanged) {
+		// Rows can't be matched up across a primary key change, so every row is considered replaced
+		return addReplacedRowStats(ctx, td, stats)
+	} else if err != nil {
+		return err
+	}
+
+	stats.Added += acc.Adds
+	stats.Modified += acc.Changes
+	stats.Deleted += acc.Removes
+	return nil
+}
+
+func addReplacedRowStats(ctx context.Context, td diff.TableDelta, stats *CommitRowStats) error {
+	from, to, err := td.GetRowData(ctx)
+	if err != nil {
+		return err
+	}
+	fromCnt, err := from.Count()
+	if err != nil {
+		return err
+	}
+	toCnt, err := to.Count()
+	if err != nil {
+		return err
+	}
+
+	stats.Added += toCnt
+	stats.Deleted += fromCnt
+	return nil
+}
@@ -28,6 +28,7 @@ const (
 	tblErrTypeInConflict  tblErrorType = "are in conflict"
 	tblErrTypeSchConflict tblErrorType = "have schema conflicts"
 	tblErrTypeConstViols  tblErrorType = "have constraint violations"
+	tblErrTypeUnstaged    tblErrorType = "have unstaged changes"
 )
 
 type TblError struct {
@@ -51,6 +52,10 @@ func NewTblHasConstraintViolations(tbls []string) TblError {
 	return TblError{tbls, tblErrTypeConstViols}
 }
 
+func NewTblUnstagedError(tbls []string) TblError {
+	return TblError{tbls, tblErrTypeUnstaged}
+}
+
 func (te TblError) Error() string {
 	return "error: the table(s) " + strings.Join(te.tables, ", ") + " " + string(te.tblErrType)
 }
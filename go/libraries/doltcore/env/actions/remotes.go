@@ -385,17 +385,74 @@ func FetchRemoteBranch(
 	return srcDBCommit, nil
 }
 
+// TrackingRefUpdate is a move of a remote-tracking ref by a fetch or pull, from the commit it pointed to before, which
+// is empty if the ref didn't exist, to the commit fetched. Recording these in the reflog keeps the previous tips of the
+// ref, which the commit graph doesn't when the remote branch was rewritten.
+type TrackingRefUpdate struct {
+	Ref  ref.DoltRef
+	From hash.Hash
+	To   hash.Hash
+}
+
+// ReflogEntry returns the reflog entry recording the update, made by the operation |op| by |name| and |email| at |t|.
+func (u TrackingRefUpdate) ReflogEntry(op, name, email string, t time.Time) env.ReflogEntry {
+	entry := env.ReflogEntry{
+		Ref:       u.Ref.String(),
+		Operation: op,
+		ToHash:    u.To.String(),
+		Name:      name,
+		Email:     email,
+		Timestamp: t,
+	}
+	if !u.From.IsEmpty() {
+		entry.FromHash = u.From.String()
+	}
+	return entry
+}
+
+// FastForwardTrackingRef fast-forwards the remote-tracking ref |trackingRef| to |commit|, as done by a pull, and returns
+// the update made. The update has the same From and To if the ref was already at |commit|.
+func FastForwardTrackingRef(ctx context.Context, ddb *doltdb.DoltDB, trackingRef ref.DoltRef, commit *doltdb.Commit) (TrackingRefUpdate, error) {
+	from, err := refCommitHash(ctx, ddb, trackingRef)
+	if err != nil {
+		return TrackingRefUpdate{}, err
+	}
+	to, err := commit.HashOf()
+	if err != nil {
+		return TrackingRefUpdate{}, err
+	}
+	err = ddb.FastForward(ctx, trackingRef, commit)
+	if err != nil {
+		return TrackingRefUpdate{}, err
+	}
+	return TrackingRefUpdate{Ref: trackingRef, From: from, To: to}, nil
+}
+
+// refCommitHash returns the hash of the commit |r| points to, or an empty hash if |r| doesn't exist.
+func refCommitHash(ctx context.Context, ddb *doltdb.DoltDB, r ref.DoltRef) (hash.Hash, error) {
+	has, err := ddb.HasRef(ctx, r)
+	if err != nil || !has {
+		return hash.Hash{}, err
+	}
+	cm, err := ddb.ResolveCommitRef(ctx, r)
+	if err != nil {
+		return hash.Hash{}, err
+	}
+	return cm.HashOf()
+}
+
 // FetchRefSpecs is the common SQL and CLI entrypoint for fetching branches, tags, and heads from a remote.
 // This function takes dbData which is a env.DbData object for handling repoState read and write, and srcDB is
-// a remote *doltdb.DoltDB object that is used to fetch remote branches from.
-func FetchRefSpecs(ctx context.Context, dbData env.DbData, srcDB *doltdb.DoltDB, refSpecs []ref.RemoteRefSpec, remote env.Remote, mode ref.UpdateMode, progStarter ProgStarter, progStopper ProgStopper) error {
+// a remote *doltdb.DoltDB object that is used to fetch remote branches from. It returns the updates made to
+// remote-tracking refs, leaving out those already at the commit fetched, so that callers can record them in the reflog.
+func FetchRefSpecs(ctx context.Context, dbData env.DbData, srcDB *doltdb.DoltDB, refSpecs []ref.RemoteRefSpec, remote env.Remote, mode ref.UpdateMode, progStarter ProgStarter, progStopper ProgStopper) ([]TrackingRefUpdate, error) {
 	var branchRefs []doltdb.RefWithHash
 	err := srcDB.VisitRefsOfType(ctx, ref.HeadRefTypes, func(r ref.DoltRef, addr hash.Hash) error {
 		branchRefs = append(branchRefs, doltdb.RefWithHash{Ref: r, Hash: addr})
 		return nil
 	})
 	if err != nil {
-		return fmt.Errorf("%w: %s", env.ErrFailedToReadDb, err.Error())
+		return nil, fmt.Errorf("%w: %s", env.ErrFailedToReadDb, err.Error())
 	}
 
 	// We build up two structures:
@@ -419,14 +476,14 @@ func FetchRefSpecs(ctx context.Context, dbData env.DbData, srcDB *doltdb.DoltDB,
 			}
 		}
 		if !rsSeen {
-			return fmt.Errorf("%w: '%s'", ref.ErrInvalidRefSpec, rs.GetRemRefToLocal())
+			return nil, fmt.Errorf("%w: '%s'", ref.ErrInvalidRefSpec, rs.GetRemRefToLocal())
 		}
 	}
 
 	// Now we fetch all the new HEADs we need.
 	tmpDir, err := dbData.Rsw.TempTableFilesDir()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	err = func() error {
@@ -448,30 +505,35 @@ func FetchRefSpecs(ctx context.Context, dbData env.DbData, srcDB *doltdb.DoltDB,
 		return err
 	}()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	var updates []TrackingRefUpdate
 	for _, newHead := range newHeads {
 		commit, err := dbData.Ddb.ReadCommit(ctx, newHead.Hash)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		remoteTrackRef := newHead.Ref
+		prevHash, err := refCommitHash(ctx, dbData.Ddb, remoteTrackRef)
+		if err != nil {
+			return nil, err
+		}
 
 		switch mode {
 		case ref.ForceUpdate:
 			// TODO: can't be used safely in a SQL context
 			err := dbData.Ddb.SetHeadToCommit(ctx, remoteTrackRef, commit)
 			if err != nil {
-				return err
+				return nil, err
 			}
 		case ref.FastForwardOnly:
 			ok, err := dbData.Ddb.CanFastForward(ctx, remoteTrackRef, commit)
 			if err != nil && !errors.Is(err, doltdb.ErrUpToDate) {
-				return fmt.Errorf("%w: %s", ErrCantFF, err.Error())
+				return nil, fmt.Errorf("%w: %s", ErrCantFF, err.Error())
 			}
 			if !ok {
-				return ErrCantFF
+				return nil, ErrCantFF
 			}
 
 			switch err {
@@ -480,20 +542,23 @@ func FetchRefSpecs(ctx context.Context, dbData env.DbData, srcDB *doltdb.DoltDB,
 				// TODO: can't be used safely in a SQL context
 				err = dbData.Ddb.FastForward(ctx, remoteTrackRef, commit)
 				if err != nil && !errors.Is(err, doltdb.ErrUpToDate) {
-					return fmt.Errorf("%w: %s", ErrCantFF, err.Error())
+					return nil, fmt.Errorf("%w: %s", ErrCantFF, err.Error())
 				}
 			default:
-				return fmt.Errorf("%w: %s", ErrCantFF, err.Error())
+				return nil, fmt.Errorf("%w: %s", ErrCantFF, err.Error())
 			}
 		}
+		if prevHash != newHead.Hash {
+			updates = append(updates, TrackingRefUpdate{Ref: remoteTrackRef, From: prevHash, To: newHead.Hash})
+		}
 	}
 
 	err = FetchFollowTags(ctx, tmpDir, srcDB, dbData.Ddb, progStarter, progStopper)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return updates, nil
 }
 
 // SyncRoots is going to copy the root hash of the database from srcDb to destDb.
@@ -16,6 +16,7 @@ package actions
 
 import (
 	"context"
+	"sort"
 
 	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
@@ -62,6 +63,42 @@ func StageModifiedAndDeletedTables(ctx context.Context, roots doltdb.Roots) (dol
 	return stageTables(ctx, roots, tbls)
 }
 
+// CheckNoUnstagedChanges returns a TblError listing the tables whose working version in |roots| differs from their
+// staged version, if there are any, so that a commit of the staged root would leave changes behind. New tables ignored
+// by dolt_ignore aren't included.
+func CheckNoUnstagedChanges(ctx context.Context, roots doltdb.Roots) error {
+	unstaged, err := diff.GetTableDeltas(ctx, roots.Staged, roots.Working)
+	if err != nil {
+		return err
+	}
+
+	var tbls, added []string
+	for _, td := range unstaged {
+		if td.IsAdd() {
+			added = append(added, td.ToName)
+		} else {
+			tbls = append(tbls, td.CurName())
+		}
+	}
+	if len(added) > 0 {
+		filteredTables, err := doltdb.FilterIgnoredTables(ctx, added, roots)
+		if err != nil && doltdb.AsDoltIgnoreInConflict(err) == nil {
+			return err
+		}
+		tbls = append(tbls, filteredTables.DontIgnore...)
+		// tables matching conflicting patterns aren't known to be ignored, so they're counted too
+		for _, conflict := range filteredTables.Conflicts {
+			tbls = append(tbls, conflict.Table)
+		}
+	}
+	if len(tbls) == 0 {
+		return nil
+	}
+
+	sort.Strings(tbls)
+	return NewTblUnstagedError(tbls)
+}
+
 func stageTables(
 	ctx context.Context,
 	roots doltdb.Roots,
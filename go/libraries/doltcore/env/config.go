@@ -52,6 +52,7 @@ const (
 	MetricsInsecure = "metrics.insecure"
 
 	PushAutoSetupRemote = "push.autosetupremote"
+	PushDefaultRemote   = "remote.pushdefault"
 )
 
 var LocalConfigWhitelist = set.NewStrSet([]string{UserNameKey, UserEmailKey})
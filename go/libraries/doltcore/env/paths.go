@@ -32,6 +32,7 @@ const (
 	globalConfig = "config_global.json"
 
 	repoStateFile = "repo_state.json"
+	reflogFile    = "reflog"
 )
 
 // HomeDirProvider is a function that returns the users home directory.  This is where global dolt state is stored for
@@ -88,6 +89,10 @@ func getRepoStateFile() string {
 	return filepath.Join(dbfactory.DoltDir, repoStateFile)
 }
 
+func getReflogFile() string {
+	return filepath.Join(dbfactory.DoltDir, reflogFile)
+}
+
 func getHomeDir(hdp HomeDirProvider) (string, error) {
 	homeDir, err := hdp()
 	if err != nil {
@@ -0,0 +1,150 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+// maxReflogSize is the size in bytes the reflog file can grow to before its oldest entries are dropped. The reflog
+// only explains recent updates to refs, so it isn't worth letting it grow without bound.
+var maxReflogSize int64 = 1 << 20
+
+// ReflogEntry records a single update to a ref. Unlike the commit graph, the reflog keeps the sequence of updates to a
+// ref, including ones that move it to a commit that isn't a descendant of the previous one, such as amends and resets.
+//
+// The reflog is a file in the .dolt directory, appended to after a ref is updated. It isn't part of the database: it
+// isn't covered by transactions, and isn't pushed, cloned or replicated. An update is missing from the reflog if the
+// append fails, or the process crashes before it's made, and callers don't fail an update which has already been made
+// because its entry couldn't be written.
+type ReflogEntry struct {
+	Ref       string    `json:"ref"`
+	Operation string    `json:"operation"`
+	FromHash  string    `json:"from_hash"`
+	ToHash    string    `json:"to_hash"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// AppendReflogEntry appends |entry| to the reflog file on the filesystem given, creating the file if necessary. Each
+// entry is written as a single line of JSON, preceded by a newline too so that a line left partly written by a crash
+// doesn't run into it. Once the file is larger than maxReflogSize, the oldest entries are dropped, keeping the newest
+// ones which fit in half of it.
+func AppendReflogEntry(fs filesys.Filesys, entry ReflogEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	wr, err := fs.OpenForWriteAppend(getReflogFile(), os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	_, err = wr.Write([]byte("\n" + string(data) + "\n"))
+	if err != nil {
+		wr.Close()
+		return err
+	}
+
+	err = wr.Close()
+	if err != nil {
+		return err
+	}
+
+	return trimReflog(fs)
+}
+
+// trimReflog drops the oldest entries of the reflog file if it's larger than maxReflogSize, keeping the newest entries
+// which fit in half of it. The entries kept are written to a temporary file which then replaces the reflog, so the
+// reflog is never left partly written.
+func trimReflog(fs filesys.Filesys) error {
+	path := getReflogFile()
+	var size int64
+	err := fs.Iter(dbfactory.DoltDir, false, func(p string, sz int64, isDir bool) (stop bool) {
+		if !isDir && filepath.Base(p) == reflogFile {
+			size = sz
+			return true
+		}
+		return false
+	})
+	if err != nil || size <= maxReflogSize {
+		return err
+	}
+
+	data, err := fs.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	lines := bytes.SplitAfter(data, []byte{'\n'})
+	start, kept := len(lines), 0
+	for start > 0 && int64(kept+len(lines[start-1])) <= maxReflogSize/2 {
+		start--
+		kept += len(lines[start])
+	}
+
+	tmpPath := path + ".tmp"
+	err = fs.WriteFile(tmpPath, bytes.Join(lines[start:], nil))
+	if err != nil {
+		return err
+	}
+	return fs.MoveFile(tmpPath, path)
+}
+
+// LoadReflog returns all the entries in the reflog file on the filesystem given, oldest first. A repository without a
+// reflog file has an empty reflog. Lines which aren't valid entries, such as one left partly written by a crash, are
+// skipped.
+func LoadReflog(fs filesys.ReadableFS) ([]ReflogEntry, error) {
+	path := getReflogFile()
+	if exists, _ := fs.Exists(path); !exists {
+		return nil, nil
+	}
+
+	rd, err := fs.OpenForRead(path)
+	if err != nil {
+		return nil, err
+	}
+	defer rd.Close()
+
+	var entries []ReflogEntry
+	scanner := bufio.NewScanner(rd)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var entry ReflogEntry
+		if json.Unmarshal(line, &entry) != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
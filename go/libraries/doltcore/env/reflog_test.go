@@ -0,0 +1,103 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/dbfactory"
+	"github.com/dolthub/dolt/go/libraries/utils/filesys"
+)
+
+func TestReflog(t *testing.T) {
+	fs, err := filesys.LocalFilesysWithWorkingDir(filepath.ToSlash(t.TempDir()))
+	require.NoError(t, err)
+	require.NoError(t, fs.MkDirs(dbfactory.DoltDir))
+
+	entries, err := LoadReflog(fs)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	ts := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	first := ReflogEntry{Ref: "refs/heads/main", Operation: "commit", FromHash: "a", ToHash: "b", Name: "Bill", Email: "bill@fake.horse", Timestamp: ts}
+	second := ReflogEntry{Ref: "refs/heads/main", Operation: "commit (amend)", FromHash: "b", ToHash: "c", Name: "Bill", Email: "bill@fake.horse", Timestamp: ts.Add(time.Minute)}
+	require.NoError(t, AppendReflogEntry(fs, first))
+	require.NoError(t, AppendReflogEntry(fs, second))
+
+	entries, err = LoadReflog(fs)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, first, entries[0])
+	assert.Equal(t, second, entries[1])
+}
+
+func TestReflogDropsOldestEntriesWhenTooLarge(t *testing.T) {
+	fs, err := filesys.LocalFilesysWithWorkingDir(filepath.ToSlash(t.TempDir()))
+	require.NoError(t, err)
+	require.NoError(t, fs.MkDirs(dbfactory.DoltDir))
+
+	defer func(size int64) { maxReflogSize = size }(maxReflogSize)
+	maxReflogSize = 1024
+
+	ts := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 50; i++ {
+		entry := ReflogEntry{Ref: "refs/heads/main", Operation: "commit", FromHash: strconv.Itoa(i - 1), ToHash: strconv.Itoa(i), Timestamp: ts}
+		require.NoError(t, AppendReflogEntry(fs, entry))
+	}
+
+	data, err := fs.ReadFile(getReflogFile())
+	require.NoError(t, err)
+	assert.LessOrEqual(t, int64(len(data)), maxReflogSize)
+
+	entries, err := LoadReflog(fs)
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	assert.Less(t, len(entries), 50)
+	assert.Equal(t, "49", entries[len(entries)-1].ToHash)
+	for i := 1; i < len(entries); i++ {
+		assert.Equal(t, entries[i-1].ToHash, entries[i].FromHash)
+	}
+}
+
+func TestReflogSkipsEntryCutShortByCrash(t *testing.T) {
+	fs, err := filesys.LocalFilesysWithWorkingDir(filepath.ToSlash(t.TempDir()))
+	require.NoError(t, err)
+	require.NoError(t, fs.MkDirs(dbfactory.DoltDir))
+
+	ts := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	first := ReflogEntry{Ref: "refs/heads/main", Operation: "commit", FromHash: "a", ToHash: "b", Timestamp: ts}
+	second := ReflogEntry{Ref: "refs/heads/main", Operation: "commit", FromHash: "c", ToHash: "d", Timestamp: ts}
+	require.NoError(t, AppendReflogEntry(fs, first))
+
+	// a crash part way through appending an entry leaves a partial line without a newline
+	wr, err := fs.OpenForWriteAppend(getReflogFile(), os.ModePerm)
+	require.NoError(t, err)
+	_, err = wr.Write([]byte(`{"ref":"refs/heads/main","operation":"comm`))
+	require.NoError(t, err)
+	require.NoError(t, wr.Close())
+
+	require.NoError(t, AppendReflogEntry(fs, second))
+
+	entries, err := LoadReflog(fs)
+	require.NoError(t, err)
+	assert.Equal(t, []ReflogEntry{first, second}, entries)
+}
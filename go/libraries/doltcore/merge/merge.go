@@ -395,16 +395,89 @@ func GetMergeArtifactStatus(ctx context.Context, working *doltdb.WorkingSet) (as
 		as.SchemaConflictsTables = working.MergeState().TablesWithSchemaConflicts()
 	}
 
-	as.DataConflictTables, err = working.WorkingRoot().TablesWithDataConflicts(ctx)
-	if err != nil {
-		return as, err
+	err = IterMergeArtifactStatus(ctx, working, func(tas TableArtifactStatus) (bool, error) {
+		if tas.DataConflicts {
+			as.DataConflictTables = append(as.DataConflictTables, tas.Name)
+		}
+		if tas.ConstraintViolations {
+			as.ConstraintViolationsTables = append(as.ConstraintViolationsTables, tas.Name)
+		}
+		return false, nil
+	})
+	return
+}
+
+// TableArtifactStatus is the merge artifacts of a single table.
+type TableArtifactStatus struct {
+	Name                 string
+	SchemaConflict       bool
+	DataConflicts        bool
+	ConstraintViolations bool
+}
+
+// IterMergeArtifactStatus calls |cb| with the merge artifacts of each table in |working| which has any, in order of
+// table name, as soon as they're found. Unlike GetMergeArtifactStatus, this lets callers report the first tables with
+// conflicts or constraint violations without waiting for every table to be checked. Iteration stops if |cb| returns
+// true or an error.
+func IterMergeArtifactStatus(ctx context.Context, working *doltdb.WorkingSet, cb func(tas TableArtifactStatus) (stop bool, err error)) error {
+	schemaConflicts := make(map[string]bool)
+	if working.MergeActive() {
+		for _, name := range working.MergeState().TablesWithSchemaConflicts() {
+			schemaConflicts[name] = true
+		}
 	}
 
-	as.ConstraintViolationsTables, err = working.WorkingRoot().TablesWithConstraintViolations(ctx)
+	root := working.WorkingRoot()
+	names, err := root.GetTableNames(ctx)
 	if err != nil {
-		return as, err
+		return err
 	}
-	return
+
+	for _, name := range names {
+		tbl, _, err := root.GetTable(ctx, name)
+		if err != nil {
+			return err
+		}
+		hasConflicts, err := tbl.HasConflicts(ctx)
+		if err != nil {
+			return err
+		}
+		numViolations, err := tbl.NumConstraintViolations(ctx)
+		if err != nil {
+			return err
+		}
+
+		tas := TableArtifactStatus{
+			Name:                 name,
+			SchemaConflict:       schemaConflicts[name],
+			DataConflicts:        hasConflicts,
+			ConstraintViolations: numViolations > 0,
+		}
+		delete(schemaConflicts, name)
+		if !tas.SchemaConflict && !tas.DataConflicts && !tas.ConstraintViolations {
+			continue
+		}
+
+		stop, err := cb(tas)
+		if err != nil || stop {
+			return err
+		}
+	}
+
+	// a table with a schema conflict may not be in the working root at all
+	if len(schemaConflicts) > 0 {
+		for _, name := range working.MergeState().TablesWithSchemaConflicts() {
+			if !schemaConflicts[name] {
+				continue
+			}
+			stop, err := cb(TableArtifactStatus{Name: name, SchemaConflict: true})
+			if err != nil || stop {
+				return err
+			}
+		}
+	}
+
+	return nil
 }
 
 // MergeWouldStompChanges returns list of table names that are stomped and the diffs map between head and working set.
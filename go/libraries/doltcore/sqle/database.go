@@ -386,6 +386,8 @@ func (db Database) getTableInsensitive(ctx *sql.Context, head *doltdb.Commit, ds
 		dt, found = dtables.NewMergeStatusTable(db.name), true
 	case doltdb.TagsTableName:
 		dt, found = dtables.NewTagsTable(ctx, db.ddb), true
+	case doltdb.ReflogTableName:
+		dt, found = dtables.NewReflogTable(db.name), true
 	case dtables.AccessTableName:
 		basCtx := branch_control.GetBranchAwareSession(ctx)
 		if basCtx != nil {
@@ -0,0 +1,85 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfunctions
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dprocedures"
+)
+
+const CommitAuthorFuncName = "dolt_commit_author"
+
+// CommitAuthorFunc returns a JSON document with the name and email dolt_commit would record as the author of a commit
+// made by the current session without --author: the value of @@dolt_author if it's set, and otherwise the current SQL
+// user. It lets a client check how its commits will be attributed before making them.
+type CommitAuthorFunc struct {
+}
+
+// NewCommitAuthorFunc creates a new CommitAuthorFunc expression.
+func NewCommitAuthorFunc() sql.Expression {
+	return &CommitAuthorFunc{}
+}
+
+// Eval implements the Expression interface.
+func (ca *CommitAuthorFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	name, email, err := dprocedures.CommitAuthorFromSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, _, err := types.JSON.Convert(map[string]interface{}{
+		"name":  name,
+		"email": email,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// String implements the Stringer interface.
+func (ca *CommitAuthorFunc) String() string {
+	return "DOLT_COMMIT_AUTHOR()"
+}
+
+// IsNullable implements the Expression interface.
+func (ca *CommitAuthorFunc) IsNullable() bool {
+	return false
+}
+
+// Resolved implements the Expression interface.
+func (*CommitAuthorFunc) Resolved() bool {
+	return true
+}
+
+func (ca *CommitAuthorFunc) Type() sql.Type {
+	return types.JSON
+}
+
+// Children implements the Expression interface.
+func (*CommitAuthorFunc) Children() []sql.Expression {
+	return nil
+}
+
+// WithChildren implements the Expression interface.
+func (ca *CommitAuthorFunc) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 0 {
+		return nil, sql.ErrInvalidChildrenNumber.New(ca, len(children), 0)
+	}
+	return NewCommitAuthorFunc(), nil
+}
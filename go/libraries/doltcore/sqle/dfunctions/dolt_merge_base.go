@@ -15,6 +15,7 @@
 package dfunctions
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/dolthub/go-mysql-server/sql"
@@ -65,7 +66,10 @@ func (d MergeBase) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
 	}
 
 	mergeBase, err := merge.MergeBase(ctx, left, right)
-	if err != nil {
+	if errors.Is(err, doltdb.ErrNoCommonAncestor) {
+		// unrelated histories, e.g. a branch fetched from another repository, have no merge base
+		return nil, nil
+	} else if err != nil {
 		return nil, err
 	}
 
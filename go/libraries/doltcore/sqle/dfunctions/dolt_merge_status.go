@@ -0,0 +1,106 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dfunctions
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/merge"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+const MergeStatusFuncName = "dolt_merge_status"
+
+// MergeStatusFunc returns a JSON document describing the merge artifacts in the current working set: whether a merge
+// is active, and which tables have schema conflicts, data conflicts and constraint violations. This is the same
+// information `dolt status` reports for unmerged tables.
+type MergeStatusFunc struct {
+}
+
+// NewMergeStatusFunc creates a new MergeStatusFunc expression.
+func NewMergeStatusFunc() sql.Expression {
+	return &MergeStatusFunc{}
+}
+
+// Eval implements the Expression interface.
+func (ms *MergeStatusFunc) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	dbName := ctx.GetCurrentDatabase()
+	dSess := dsess.DSessFromSess(ctx.Session)
+
+	ws, err := dSess.WorkingSet(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	as, err := merge.GetMergeArtifactStatus(ctx, ws)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, _, err := types.JSON.Convert(map[string]interface{}{
+		"is_merging":                  ws.MergeActive(),
+		"has_conflicts":               as.HasConflicts(),
+		"has_constraint_violations":   as.HasConstraintViolations(),
+		"schema_conflict_tables":      nonNilTableNames(as.SchemaConflictsTables),
+		"data_conflict_tables":        nonNilTableNames(as.DataConflictTables),
+		"constraint_violation_tables": nonNilTableNames(as.ConstraintViolationsTables),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// nonNilTableNames returns |names|, or an empty slice if it's nil, so that no tables is rendered as [] rather than null.
+func nonNilTableNames(names []string) []string {
+	if names == nil {
+		return []string{}
+	}
+	return names
+}
+
+// String implements the Stringer interface.
+func (ms *MergeStatusFunc) String() string {
+	return "DOLT_MERGE_STATUS()"
+}
+
+// IsNullable implements the Expression interface.
+func (ms *MergeStatusFunc) IsNullable() bool {
+	return false
+}
+
+// Resolved implements the Expression interface.
+func (*MergeStatusFunc) Resolved() bool {
+	return true
+}
+
+func (ms *MergeStatusFunc) Type() sql.Type {
+	return types.JSON
+}
+
+// Children implements the Expression interface.
+func (*MergeStatusFunc) Children() []sql.Expression {
+	return nil
+}
+
+// WithChildren implements the Expression interface.
+func (ms *MergeStatusFunc) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != 0 {
+		return nil, sql.ErrInvalidChildrenNumber.New(ms, len(children), 0)
+	}
+	return NewMergeStatusFunc(), nil
+}
@@ -22,6 +22,8 @@ var DoltFunctions = []sql.Function{
 	sql.Function0{Name: StorageFormatFuncName, Fn: NewStorageFormat},
 	sql.Function0{Name: ActiveBranchFuncName, Fn: NewActiveBranchFunc},
 	sql.Function2{Name: DoltMergeBaseFuncName, Fn: NewMergeBase},
+	sql.Function0{Name: MergeStatusFuncName, Fn: NewMergeStatusFunc},
+	sql.Function0{Name: CommitAuthorFuncName, Fn: NewCommitAuthorFunc},
 }
 
 // DolthubApiFunctions are the DoltFunctions that get exposed to Dolthub Api.
@@ -31,4 +33,5 @@ var DolthubApiFunctions = []sql.Function{
 	sql.Function0{Name: StorageFormatFuncName, Fn: NewStorageFormat},
 	sql.Function0{Name: ActiveBranchFuncName, Fn: NewActiveBranchFunc},
 	sql.Function2{Name: DoltMergeBaseFuncName, Fn: NewMergeBase},
+	sql.Function0{Name: MergeStatusFuncName, Fn: NewMergeStatusFunc},
 }
@@ -0,0 +1,101 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/sirupsen/logrus"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// autoGCScheduler counts the commits made to each database since its last automatic garbage collection, across every
+// session of the server, and runs a garbage collection in the background once the configured threshold is crossed.
+type autoGCScheduler struct {
+	mu      sync.Mutex
+	commits map[string]int64
+	running map[string]bool
+}
+
+var autoGC = &autoGCScheduler{
+	commits: make(map[string]int64),
+	running: make(map[string]bool),
+}
+
+// commitCreated records a commit to the database named, and returns whether a garbage collection of it should be
+// started now. Only one collection of a database runs at a time; commits made while one is running count towards the
+// next.
+func (s *autoGCScheduler) commitCreated(dbName string, threshold int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.commits[dbName]++
+	if s.commits[dbName] < threshold || s.running[dbName] {
+		return false
+	}
+
+	s.commits[dbName] = 0
+	s.running[dbName] = true
+	return true
+}
+
+// run garbage collects |ddb| in the background.
+func (s *autoGCScheduler) run(dbName string, ddb *doltdb.DoltDB) {
+	go func() {
+		defer func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			delete(s.running, dbName)
+		}()
+
+		// A shallow GC only removes table files which are no longer referenced, and leaves alone any written after
+		// it began, so it's safe to run alongside other reads and writes. A full GC needs to kill every other
+		// connection to establish its safepoint, so it's only ever run explicitly with dolt_gc().
+		err := ddb.ShallowGC(context.Background())
+		if err != nil {
+			logrus.Errorf("error running automatic garbage collection of database %s: %v", dbName, err)
+		}
+	}()
+}
+
+// maybeScheduleGC starts a background garbage collection of the database named if the number of commits made to it
+// since the last one has crossed @@<db>_auto_gc_commit_threshold. It never blocks on the collection itself.
+func maybeScheduleGC(ctx *sql.Context, dbName string) {
+	if !DoltGCFeatureFlag {
+		return
+	}
+
+	threshold := dsess.GetAutoGCCommitThreshold(dbName)
+	if threshold <= 0 {
+		return
+	}
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	ddb, ok := dSess.GetDoltDB(ctx, dbName)
+	if !ok {
+		return
+	}
+
+	// Revision databases share their storage with the base database, so their commits all count towards its threshold
+	baseName := strings.SplitN(dbName, dsess.DbRevisionDelimiter, 2)[0]
+	if autoGC.commitCreated(baseName, threshold) {
+		autoGC.run(baseName, ddb)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// commitLogHashColumn is the column of a commit log table which holds the hash of the commit logged. It's the only
+// column a commit log table must have.
+const commitLogHashColumn = "commit_hash"
+
+// loadCommitLogTable returns the table configured with @@<db>_commit_log_table for the database named, or nil if
+// none is configured. It's an error for the table not to exist, not to be writable, or to have no commit_hash column.
+func loadCommitLogTable(ctx *sql.Context, dbName string) (sql.InsertableTable, error) {
+	tableName := dsess.GetCommitLogTable(dbName)
+	if tableName == "" {
+		return nil, nil
+	}
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	db, ok, err := dSess.Provider().SessionDatabase(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, sql.ErrDatabaseNotFound.New(dbName)
+	}
+
+	tbl, ok, err := db.GetTableInsensitive(ctx, tableName)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("error: commit log table %s, set by @@%s, not found", tableName, dsess.CommitLogTableKey(dbName))
+	}
+	insertable, ok := tbl.(sql.InsertableTable)
+	if !ok {
+		return nil, fmt.Errorf("error: commit log table %s, set by @@%s, can't be written to", tableName, dsess.CommitLogTableKey(dbName))
+	}
+	if tbl.Schema().IndexOfColName(commitLogHashColumn) < 0 {
+		return nil, fmt.Errorf("error: commit log table %s, set by @@%s, has no %s column", tableName, dsess.CommitLogTableKey(dbName), commitLogHashColumn)
+	}
+	return insertable, nil
+}
+
+// appendCommitLogRow inserts a row describing the commit with the hash given, made to |branch|, into the table
+// configured with @@<db>_commit_log_table, if any. The row is written to the session's working set, so it's part of
+// the same transaction as the commit, and is committed by the next commit unless the table is matched by dolt_ignore.
+// The columns commit_hash, parents, branch, author, email, date and tables are filled in where the table has them,
+// and any other columns are NULL. Parents and tables are comma-separated lists.
+func appendCommitLogRow(ctx *sql.Context, dbName, branch, commitHash string) error {
+	tbl, err := loadCommitLogTable(ctx, dbName)
+	if err != nil || tbl == nil {
+		return err
+	}
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	ddb, ok := dSess.GetDoltDB(ctx, dbName)
+	if !ok {
+		return fmt.Errorf("Could not load database %s", dbName)
+	}
+	commit, err := ddb.ReadCommit(ctx, hash.Parse(commitHash))
+	if err != nil {
+		return err
+	}
+	meta, err := commit.GetCommitMeta(ctx)
+	if err != nil {
+		return err
+	}
+	parentHashes, err := commit.ParentHashes(ctx)
+	if err != nil {
+		return err
+	}
+	parents := make([]string, len(parentHashes))
+	for i, h := range parentHashes {
+		parents[i] = h.String()
+	}
+	tables, err := commitTableNames(ctx, dbName, commitHash)
+	if err != nil {
+		return err
+	}
+
+	values := map[string]interface{}{
+		commitLogHashColumn: commitHash,
+		"parents":           strings.Join(parents, ","),
+		"branch":            branch,
+		"author":            meta.Name,
+		"email":             meta.Email,
+		"date":              meta.Time(),
+		"tables":            strings.Join(tables, ","),
+	}
+	sch := tbl.Schema()
+	row := make(sql.Row, len(sch))
+	for i, col := range sch {
+		v, ok := values[strings.ToLower(col.Name)]
+		if !ok {
+			continue
+		}
+		row[i], _, err = col.Type.Convert(v)
+		if err != nil {
+			return fmt.Errorf("error: can't write %s to the commit log table %s: %w", col.Name, tbl.Name(), err)
+		}
+	}
+
+	inserter := tbl.Inserter(ctx)
+	inserter.StatementBegin(ctx)
+	err = inserter.Insert(ctx, row)
+	if err != nil {
+		inserter.DiscardChanges(ctx, err)
+		inserter.Close(ctx)
+		return err
+	}
+	err = inserter.StatementComplete(ctx)
+	if err != nil {
+		inserter.Close(ctx)
+		return err
+	}
+	return inserter.Close(ctx)
+}
+
+// commitTableNames returns the sorted names of the tables changed by the commit with the hash given, relative to its
+// first parent. A renamed table is listed by its new name.
+func commitTableNames(ctx *sql.Context, dbName, commitHash string) ([]string, error) {
+	deltas, err := commitTableDeltas(ctx, dbName, commitHash)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(deltas))
+	for i, td := range deltas {
+		names[i] = td.CurName()
+	}
+	sort.Strings(names)
+	return names, nil
+}
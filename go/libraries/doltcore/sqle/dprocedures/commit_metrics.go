@@ -0,0 +1,104 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"errors"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/datas"
+)
+
+const (
+	commitDbLabel     = "database"
+	commitBranchLabel = "branch"
+	commitReasonLabel = "reason"
+)
+
+var (
+	commitsCreated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "dss_commits_created",
+		Help: "Count of commits created with dolt_commit",
+	}, []string{commitDbLabel, commitBranchLabel})
+	commitsSkipped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "dss_commits_skipped",
+		Help: "Count of calls to dolt_commit that were skipped because there was nothing to commit",
+	}, []string{commitDbLabel, commitBranchLabel})
+	commitFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "dss_commit_failures",
+		Help: "Count of calls to dolt_commit that failed, by reason",
+	}, []string{commitDbLabel, commitBranchLabel, commitReasonLabel})
+)
+
+// CommitMetricsCollectors returns the prometheus collectors for the commit metrics maintained by dolt_commit. They are
+// always updated, but are only exported when registered, e.g. by the sql-server metrics endpoint.
+func CommitMetricsCollectors() []prometheus.Collector {
+	return []prometheus.Collector{commitsCreated, commitsSkipped, commitFailures}
+}
+
+// recordCommitMetrics updates the commit metrics for the outcome of a call to dolt_commit on the current database.
+func recordCommitMetrics(ctx *sql.Context, skipped bool, err error) {
+	dbName := ctx.GetCurrentDatabase()
+	branch := ""
+	if headRef, refErr := dsess.DSessFromSess(ctx.Session).CWBHeadRef(ctx, dbName); refErr == nil {
+		branch = headRef.GetPath()
+	}
+
+	if err != nil {
+		commitFailures.WithLabelValues(dbName, branch, commitFailureReason(err)).Inc()
+	} else if skipped {
+		commitsSkipped.WithLabelValues(dbName, branch).Inc()
+	} else {
+		commitsCreated.WithLabelValues(dbName, branch).Inc()
+	}
+}
+
+// commitFailureReason classifies a commit error into a small, fixed set of reasons suitable for a metric label.
+func commitFailureReason(err error) string {
+	switch {
+	case errors.Is(err, ErrNothingToCommit) || actions.IsNothingStaged(err):
+		return "nothing_to_commit"
+	case actions.IsTblInConflict(err):
+		return "conflicts"
+	case actions.IsTblViolatesConstraints(err):
+		return "constraint_violations"
+	case branch_control.ErrIncorrectPermissions.Is(err):
+		return "permissions"
+	case ErrProtectedBranch.Is(err):
+		return "protected_branch"
+	case ErrCommitRateExceeded.Is(err):
+		return "rate_limited"
+	case ErrCommitTooManyTables.Is(err):
+		return "too_many_tables"
+	case ErrCommitSubjectTooLong.Is(err):
+		return "subject_too_long"
+	case errors.As(err, new(*CommitValidationError)):
+		return "validation_failed"
+	case ErrPreCommitProcedureRejected.Is(err):
+		return "pre_commit_rejected"
+	case errors.Is(err, doltdb.ErrOperationNotSupportedInDetachedHead):
+		return "detached_head"
+	case errors.Is(err, datas.ErrEmptyCommitMessage) || errors.Is(err, ErrMissingCommitMessage):
+		return "empty_message"
+	default:
+		return "other"
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"sync"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	goerrors "gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// ErrCommitRateExceeded is returned by dolt_commit when a branch has had more commits than the configured limit within
+// the configured window, and the limit is enforced by rejecting commits.
+var ErrCommitRateExceeded = goerrors.NewKind("commit rate limit exceeded: branch %s of database %s already has %d commits in the last %s, see @@%s and @@%s")
+
+// commitRateWarningCode is the code of the warning raised when the commit rate limit is exceeded, but only enforced with
+// warnings. Since this is our own custom warning we use 1105, the code for an unknown error.
+const commitRateWarningCode = 1105
+
+// commitRateTracker records the times of recent commits on each branch, across every session of the server.
+type commitRateTracker struct {
+	mu      sync.Mutex
+	commits map[string][]time.Time
+}
+
+var commitRates = &commitRateTracker{commits: make(map[string][]time.Time)}
+
+// count returns the number of commits recorded for |key| within |window| before |now|, discarding any older ones.
+func (t *commitRateTracker) count(key string, now time.Time, window time.Duration) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	times := t.commits[key]
+	i := 0
+	for i < len(times) && now.Sub(times[i]) >= window {
+		i++
+	}
+	t.commits[key] = times[i:]
+
+	return len(t.commits[key])
+}
+
+// record records a commit for |key| at |now|.
+func (t *commitRateTracker) record(key string, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.commits[key] = append(t.commits[key], now)
+}
+
+// checkCommitRate returns an error if a new commit on the branch given would exceed the commit rate limit configured
+// for the database, and the limit is enforced by rejecting commits. When it's enforced with warnings, a warning is
+// added to the session instead. The returned function records the commit, and must be called once it's created.
+func checkCommitRate(ctx *sql.Context, dbName, branch string) (func(), error) {
+	limit, window := dsess.GetCommitRateLimit(dbName)
+	if limit <= 0 {
+		return func() {}, nil
+	}
+
+	key := dbName + "/" + branch
+	now := time.Now()
+	record := func() {
+		commitRates.record(key, now)
+	}
+
+	n := commitRates.count(key, now, window)
+	if int64(n) < limit {
+		return record, nil
+	}
+
+	action, err := ctx.GetSessionVariable(ctx, dsess.CommitRateLimitAction)
+	if err != nil {
+		return nil, err
+	}
+
+	rateErr := ErrCommitRateExceeded.New(branch, dbName, n, window, dsess.CommitRateLimitKey(dbName), dsess.CommitRateWindowKey(dbName))
+	if action == "warn" {
+		ctx.Warn(commitRateWarningCode, rateErr.Error())
+		return record, nil
+	}
+
+	return nil, rateErr
+}
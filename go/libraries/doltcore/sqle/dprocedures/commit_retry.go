@@ -0,0 +1,92 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/datas"
+)
+
+// The wait before each retry of a commit given --retry starts at commitRetryInitialInterval and grows exponentially up
+// to commitRetryMaxInterval.
+const (
+	commitRetryInitialInterval = 10 * time.Millisecond
+	commitRetryMaxInterval     = time.Second
+)
+
+// retryCommitOnConflict runs |commit| and, if it fails because it conflicts with a transaction committed concurrently
+// by another client, retries it up to |retries| more times, backing off exponentially between attempts. |restart|, if
+// not nil, is called before each retry to discard the state left by the failed attempt. Any other error is returned
+// immediately.
+func retryCommitOnConflict(ctx *sql.Context, retries uint64, restart func(ctx *sql.Context) error, commit func() (string, bool, error)) (commitHash string, skipped bool, err error) {
+	if retries == 0 {
+		return commit()
+	}
+
+	params := backoff.NewExponentialBackOff()
+	params.InitialInterval = commitRetryInitialInterval
+	params.MaxInterval = commitRetryMaxInterval
+	params.MaxElapsedTime = 0
+
+	attempted := false
+	err = backoff.Retry(func() error {
+		if attempted && restart != nil {
+			if err := restart(ctx); err != nil {
+				return backoff.Permanent(err)
+			}
+		}
+		attempted = true
+
+		var err error
+		commitHash, skipped, err = commit()
+		if err != nil && !isCommitConflict(err) {
+			return backoff.Permanent(err)
+		}
+		return err
+	}, backoff.WithContext(backoff.WithMaxRetries(params, retries), ctx))
+
+	return commitHash, skipped, err
+}
+
+// isCommitConflict returns whether |err| is the failure of a commit because of a conflicting write by another client,
+// after which the commit may succeed if it's attempted again against the latest state of the database.
+func isCommitConflict(err error) bool {
+	return sql.ErrLockDeadlock.Is(err) || errors.Is(err, datas.ErrOptimisticLockFailed)
+}
+
+// restartCommitTransaction rolls back what's left of the session's transaction after a failed commit attempt and
+// starts a new one, which reads the latest working set of the database.
+func restartCommitTransaction(ctx *sql.Context) error {
+	dSess := dsess.DSessFromSess(ctx.Session)
+	if tx := ctx.GetTransaction(); tx != nil {
+		if err := dSess.Rollback(ctx, tx); err != nil {
+			return err
+		}
+	}
+
+	tx, err := dSess.StartTransaction(ctx, sql.ReadWrite)
+	if err != nil {
+		return err
+	}
+	ctx.SetTransaction(tx)
+
+	return nil
+}
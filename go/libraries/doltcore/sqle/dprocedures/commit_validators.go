@@ -0,0 +1,170 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	goerrors "gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// ErrUnknownCommitValidator is returned by dolt_commit when @@<db>_commit_validators names a validator which isn't
+// registered.
+var ErrUnknownCommitValidator = goerrors.NewKind("unknown commit validator '%s' in @@%s")
+
+// CommitValidationError is returned by dolt_commit when a commit is rejected by one of the validators configured with
+// @@<db>_commit_validators.
+type CommitValidationError struct {
+	// Validator is the name of the validator which rejected the commit.
+	Validator string
+	// Err is the error returned by the validator, describing why the commit was rejected.
+	Err error
+}
+
+func (e *CommitValidationError) Error() string {
+	return fmt.Sprintf("commit rejected by validator %s: %s", e.Validator, e.Err.Error())
+}
+
+func (e *CommitValidationError) Unwrap() error {
+	return e.Err
+}
+
+// CommitToValidate is a commit about to be created by dolt_commit, as seen by a CommitValidator.
+type CommitToValidate struct {
+	Database string
+	Branch   string
+	Message  string
+	Metadata map[string]string
+	Name     string
+	Email    string
+	Amend    bool
+}
+
+// CommitValidator checks a commit about to be created by dolt_commit, and returns an error describing why it should be
+// rejected, or nil to allow it. |arg| is the argument given to the validator in @@<db>_commit_validators, and is empty
+// if none was given.
+type CommitValidator func(commit CommitToValidate, arg string) error
+
+var commitValidators = map[string]CommitValidator{
+	"conventional_commits": validateConventionalCommit,
+	"require_issue_key":    validateIssueKey,
+	"max_subject_length":   validateMaxSubjectLength,
+}
+
+// RegisterCommitValidator adds a validator which can be named in @@<db>_commit_validators, replacing any registered
+// with the same name, such as a built-in one. Applications embedding Dolt use this to enforce their own commit
+// policies. Should be called during initialization.
+func RegisterCommitValidator(name string, validator CommitValidator) {
+	commitValidators[name] = validator
+}
+
+// validateCommit runs the validators configured for the database of |commit| against it, in the order given, and
+// returns a *CommitValidationError for the first which rejects it.
+func validateCommit(commit CommitToValidate) error {
+	for _, entry := range strings.Split(dsess.GetCommitValidators(commit.Database), ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(entry), "=")
+		if name == "" {
+			continue
+		}
+
+		validator, ok := commitValidators[name]
+		if !ok {
+			return ErrUnknownCommitValidator.New(name, dsess.CommitValidatorsKey(commit.Database))
+		}
+		if err := validator(commit, arg); err != nil {
+			return &CommitValidationError{Validator: name, Err: err}
+		}
+	}
+	return nil
+}
+
+// commitSubject returns the first line of a commit message.
+func commitSubject(msg string) string {
+	subject, _, _ := strings.Cut(msg, "\n")
+	return strings.TrimSpace(subject)
+}
+
+var conventionalCommitRegex = regexp.MustCompile(`^([a-z]+)(\([^()]+\))?!?: \S`)
+
+// validateConventionalCommit requires the subject of the message to follow the Conventional Commits format, e.g.
+// "fix(parser): handle empty input". |arg| optionally restricts the commit types allowed, separated by '|', e.g.
+// "feat|fix|chore".
+func validateConventionalCommit(commit CommitToValidate, arg string) error {
+	subject := commitSubject(commit.Message)
+	m := conventionalCommitRegex.FindStringSubmatch(subject)
+	if m == nil {
+		return fmt.Errorf("subject '%s' doesn't match the conventional commit format 'type(scope): description'", subject)
+	}
+
+	if arg == "" {
+		return nil
+	}
+	for _, typ := range strings.Split(arg, "|") {
+		if m[1] == strings.TrimSpace(typ) {
+			return nil
+		}
+	}
+	return fmt.Errorf("commit type '%s' isn't one of %s", m[1], strings.ReplaceAll(arg, "|", ", "))
+}
+
+var issueKeyRegex = regexp.MustCompile(`\b[A-Z][A-Z0-9]+-[0-9]+\b`)
+
+// validateIssueKey requires the message, or the value of a metadata entry given with --meta, to contain an issue key
+// such as "PROJ-123". |arg| optionally restricts the keys accepted to those of the project given, e.g. "PROJ".
+func validateIssueKey(commit CommitToValidate, arg string) error {
+	keyRegex := issueKeyRegex
+	if arg != "" {
+		var err error
+		keyRegex, err = regexp.Compile(`\b` + regexp.QuoteMeta(arg) + `-[0-9]+\b`)
+		if err != nil {
+			return err
+		}
+	}
+
+	if keyRegex.MatchString(commit.Message) {
+		return nil
+	}
+	for _, v := range commit.Metadata {
+		if keyRegex.MatchString(v) {
+			return nil
+		}
+	}
+
+	if arg != "" {
+		return fmt.Errorf("no %s issue key, e.g. %s-123, found in the commit message or metadata", arg, arg)
+	}
+	return errors.New("no issue key, e.g. PROJ-123, found in the commit message or metadata")
+}
+
+// validateMaxSubjectLength requires the subject of the message to be at most |arg| characters long.
+func validateMaxSubjectLength(commit CommitToValidate, arg string) error {
+	maxLen, err := strconv.Atoi(arg)
+	if err != nil || maxLen <= 0 {
+		return fmt.Errorf("invalid maximum subject length '%s'", arg)
+	}
+
+	n := utf8.RuneCountInString(commitSubject(commit.Message))
+	if n > maxLen {
+		return fmt.Errorf("subject is %d characters long, longer than the maximum of %d", n, maxLen)
+	}
+	return nil
+}
@@ -17,19 +17,48 @@ package dprocedures
 import (
 	"errors"
 	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/types"
 	"github.com/dolthub/vitess/go/vt/proto/query"
+	goerrors "gopkg.in/src-d/go-errors.v1"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/libraries/utils/set"
+	"github.com/dolthub/dolt/go/store/datas"
+	"github.com/dolthub/dolt/go/store/hash"
+	"github.com/dolthub/dolt/go/store/nbs"
 )
 
 var hashType = types.MustCreateString(query.Type_TEXT, 32, sql.Collation_ascii_bin)
 
+// ErrNothingToCommit is returned by dolt_commit when there are no staged changes and neither --allow-empty nor
+// --skip-empty was given.
+var ErrNothingToCommit = errors.New("nothing to commit")
+
+// ErrMissingCommitMessage is returned by dolt_commit when no message is given, and there's no previous one to amend.
+var ErrMissingCommitMessage = errors.New("Must provide commit message.")
+
+// ErrCommitSubjectTooLong is returned by dolt_commit when the first line of the message is longer than the maximum
+// given with --max-subject-length or @@dolt_commit_max_subject_length.
+var ErrCommitSubjectTooLong = goerrors.NewKind("commit message subject is %d characters long, longer than the maximum of %d set by %s")
+
+// ErrCommitTooManyTables is returned by dolt_commit when the commit changes more tables than the maximum given with
+// --max-tables or @@<db>_commit_max_tables.
+var ErrCommitTooManyTables = goerrors.NewKind("commit changes %d tables, more than the maximum of %d set by %s")
+
 // doltCommit is the stored procedure version for the CLI command `dolt commit`.
 func doltCommit(ctx *sql.Context, args ...string) (sql.RowIter, error) {
 	commitHash, skipped, err := doDoltCommit(ctx, args)
@@ -39,6 +68,7 @@ func doltCommit(ctx *sql.Context, args ...string) (sql.RowIter, error) {
 	if skipped {
 		return nil, nil
 	}
+
 	return rowToIter(commitHash), nil
 }
 
@@ -57,13 +87,48 @@ func doltCommitHashOut(ctx *sql.Context, outHash *string, args ...string) (sql.R
 	return rowToIter(commitHash), nil
 }
 
+// commitHashSkippedOutSchema is the schema of the result of dolt_commit_hash_skipped_out.
+var commitHashSkippedOutSchema = sql.Schema{
+	&sql.Column{Name: "hash", Type: types.LongText, Nullable: false},
+	&sql.Column{Name: "skipped", Type: types.Boolean, Nullable: false},
+}
+
+// doltCommitHashSkippedOut is like doltCommitHashOut, but also sets the second parameter to whether the commit was
+// skipped, e.g. because --skip-empty was given and there was nothing to commit, in which case the hash is empty.
+func doltCommitHashSkippedOut(ctx *sql.Context, outHash *string, outSkipped *bool, args ...string) (sql.RowIter, error) {
+	commitHash, skipped, err := doDoltCommit(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	*outHash, *outSkipped = commitHash, skipped
+	return rowToIter(commitHash, skipped), nil
+}
+
 // doDoltCommit creates a dolt commit using the specified command line |args| provided. The response is the commit hash
 // of the new commit (or the empty string if the commit was skipped), a boolean that indicates if creating the commit
 // was skipped (e.g. due to --skip-empty), and an error describing any error encountered.
-func doDoltCommit(ctx *sql.Context, args []string) (string, bool, error) {
-	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
-		return "", false, err
-	}
+func doDoltCommit(ctx *sql.Context, args []string) (commitHash string, skipped bool, err error) {
+	return doDoltCommitWithRoots(ctx, args, nil, nil)
+}
+
+// DoDoltCommitWithRoots is dolt_commit with the command line |args| given, but commits the staged root of |roots|
+// rather than loading the session's roots and staging tables, for callers which manage the staged changes themselves.
+// After the commit, the working set of the current branch has the working root of |roots|. The head root of |roots|
+// must be the root of the current branch's HEAD commit. The commit is validated, and its metadata filled in, as it is
+// by dolt_commit, but the options which stage tables, --working-set, --tree and --retry can't be used.
+func DoDoltCommitWithRoots(ctx *sql.Context, args []string, roots doltdb.Roots) (commitHash string, skipped bool, err error) {
+	return doDoltCommitWithRoots(ctx, args, &roots, nil)
+}
+
+// doDoltCommitWithRoots is doDoltCommit, but commits |roots| if it's not nil, as described by DoDoltCommitWithRoots. If
+// |rowStats| isn't nil, it's set to the number of rows changed by the commit. The row stats flags can only be given if
+// it isn't nil, since only dolt_commit_detailed has columns to report them in.
+func doDoltCommitWithRoots(ctx *sql.Context, args []string, roots *doltdb.Roots, rowStats *actions.CommitRowStats) (commitHash string, skipped bool, err error) {
+	defer func() {
+		recordCommitMetrics(ctx, skipped, err)
+	}()
+
 	// Get the information for the sql context.
 	dbName := ctx.GetCurrentDatabase()
 
@@ -75,118 +140,901 @@ func doDoltCommit(ctx *sql.Context, args []string) (string, bool, error) {
 	if err := cli.VerifyCommitArgs(apr); err != nil {
 		return "", false, err
 	}
+	if rowStats == nil {
+		for _, arg := range []string{cli.RowStatsFlag, cli.ExactRowStatsFlag} {
+			if apr.Contains(arg) {
+				return "", false, fmt.Errorf("error: --%s can only be used with DOLT_COMMIT_DETAILED()", arg)
+			}
+		}
+	}
+	if roots != nil {
+		for _, arg := range []string{cli.AllFlag, cli.UpperCaseAllFlag, cli.ViolationsResolvedFlag, cli.AmendMetadataOnlyFlag, cli.WorkingSetParam, cli.TreeParam, cli.RetryParam} {
+			if apr.Contains(arg) {
+				return "", false, fmt.Errorf("error: --%s cannot be used when committing precomputed roots", arg)
+			}
+		}
+	}
+
+	if apr.Contains(cli.NoFlushFlag) {
+		if !dsess.AllowCommitNoFlush() {
+			return "", false, fmt.Errorf("error: --%s requires @@%s to be enabled", cli.NoFlushFlag, dsess.CommitAllowNoFlush)
+		}
+		ctx = ctx.WithContext(nbs.WithoutSync(ctx))
+	}
+
+	// Check the commit log table before committing, since the commit can't be undone if writing to it fails
+	if _, err := loadCommitLogTable(ctx, dbName); err != nil {
+		return "", false, err
+	}
+
+	retries, _ := apr.GetUint(cli.RetryParam)
+	if wsName, ok := apr.GetValue(cli.WorkingSetParam); ok {
+		// Each attempt reloads the working set, so there's nothing to restart
+		commitHash, skipped, err = retryCommitOnConflict(ctx, retries, nil, func() (string, bool, error) {
+			return doDoltCommitOnWorkingSet(ctx, apr, dbName, wsName)
+		})
+		if err != nil || skipped {
+			return commitHash, skipped, err
+		}
+		headRef, err := ref.NewWorkingSetRef(wsName).ToHeadRef()
+		if err != nil {
+			return "", false, err
+		}
+		return commitHash, false, appendCommitLogRow(ctx, dbName, headRef.GetPath(), commitHash)
+	}
+
+	if retries > 0 {
+		// A retry starts a new transaction, which would lose any changes made earlier in the current one
+		dirty, err := dsess.DSessFromSess(ctx.Session).IsDirty(ctx, dbName)
+		if err != nil {
+			return "", false, err
+		}
+		if dirty {
+			return "", false, fmt.Errorf("error: --%s cannot be used in a transaction with uncommitted changes", cli.RetryParam)
+		}
+	}
+
+	commitHash, skipped, err = retryCommitOnConflict(ctx, retries, restartCommitTransaction, func() (string, bool, error) {
+		return doDoltCommitOnSession(ctx, apr, dbName, roots, rowStats)
+	})
+	if err != nil || skipped {
+		return commitHash, skipped, err
+	}
+	if apr.Contains(cli.KeepFlag) {
+		// The commit isn't on the branch, so it isn't logged as committed to it
+		return commitHash, false, nil
+	}
+	headRef, err := dsess.DSessFromSess(ctx.Session).CWBHeadRef(ctx, dbName)
+	if err != nil {
+		return "", false, err
+	}
+	return commitHash, false, appendCommitLogRow(ctx, dbName, headRef.GetPath(), commitHash)
+}
+
+// doDoltCommitOnSession commits the staged changes of the session's current branch in the database named, as
+// dolt_commit does without --working-set. If |precomputed| isn't nil, its staged root is committed instead. If
+// |rowStats| isn't nil, it's set to the number of rows changed by the commit, counted before the commit is written.
+func doDoltCommitOnSession(ctx *sql.Context, apr *argparser.ArgParseResults, dbName string, precomputed *doltdb.Roots, rowStats *actions.CommitRowStats) (string, bool, error) {
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+		return "", false, err
+	}
 
 	dSess := dsess.DSessFromSess(ctx.Session)
-	roots, ok := dSess.GetRoots(ctx, dbName)
-	if !ok {
-		return "", false, fmt.Errorf("Could not load database %s", dbName)
+	headRef, err := dSess.CWBHeadRef(ctx, dbName)
+	if err != nil {
+		return "", false, err
+	}
+	if err := checkProtectedBranch(ctx, dbName, headRef.GetPath()); err != nil {
+		return "", false, err
 	}
 
-	if apr.Contains(cli.UpperCaseAllFlag) {
-		roots, err = actions.StageAllTables(ctx, roots, true)
+	var roots doltdb.Roots
+	if precomputed != nil {
+		roots = *precomputed
+		if err := checkPrecomputedHead(ctx, dSess, dbName, roots); err != nil {
+			return "", false, err
+		}
+	} else {
+		var ok bool
+		roots, ok = dSess.GetRoots(ctx, dbName)
+		if !ok {
+			return "", false, fmt.Errorf("Could not load database %s", dbName)
+		}
+	}
+
+	roots, err = stageCommitRoots(ctx, apr, roots)
+	if err != nil {
+		return "", false, err
+	}
+
+	var violationsStagedRoot *doltdb.RootValue
+	if apr.Contains(cli.ViolationsResolvedFlag) {
+		ws, err := dSess.WorkingSet(ctx, dbName)
+		if err != nil {
+			return "", false, err
+		}
+		if ws.MergeActive() {
+			return "", false, fmt.Errorf("error: cannot use --%s while a merge is in progress", cli.ViolationsResolvedFlag)
+		}
+		roots, violationsStagedRoot, err = violationsResolvedRoots(ctx, roots)
+		if err != nil {
+			return "", false, err
+		}
+	}
+
+	amendMetadataOnly := apr.Contains(cli.AmendMetadataOnlyFlag)
+	amend := apr.Contains(cli.AmendFlag) || amendMetadataOnly
+
+	prevHead, err := dSess.GetHeadCommit(ctx, dbName)
+	if err != nil {
+		return "", false, err
+	}
+	prevHash, err := prevHead.HashOf()
+	if err != nil {
+		return "", false, err
+	}
+
+	var headMeta *datas.CommitMeta
+	if amend {
+		headMeta, err = prevHead.GetCommitMeta(ctx)
+		if err != nil {
+			return "", false, err
+		}
+	}
+
+	if amendMetadataOnly {
+		ws, err := dSess.WorkingSet(ctx, dbName)
+		if err != nil {
+			return "", false, err
+		}
+		if ws.MergeActive() {
+			return "", false, fmt.Errorf("error: cannot use --amend-metadata-only while a merge is in progress")
+		}
+	}
+
+	isMerge, err := sessionCommitIsMerge(ctx, dSess, dbName, amend)
+	if err != nil {
+		return "", false, err
+	}
+	if isMerge && apr.Contains(cli.KeepFlag) {
+		return "", false, fmt.Errorf("error: cannot use --%s while a merge is in progress", cli.KeepFlag)
+	}
+
+	stagedRoot, workingRoot := roots.Staged, roots.Working
+	if amendMetadataOnly {
+		// Commit exactly the tree of the existing HEAD commit, ignoring any staged or working changes
+		roots.Staged, roots.Working = roots.Head, roots.Head
+	}
+	if treeStr, ok := apr.GetValue(cli.TreeParam); ok {
+		roots, err = commitTreeRoots(ctx, dSess, dbName, roots, treeStr)
 		if err != nil {
-			return "", false, fmt.Errorf(err.Error())
+			return "", false, err
 		}
+	}
+
+	fromRoot := roots.Head
+	if amend {
+		// the amended commit replaces HEAD, so its changes are relative to HEAD's parent
+		fromRoot, err = headParentRoot(ctx, dSess, dbName)
+		if err != nil {
+			return "", false, err
+		}
+	}
+
+	props, recordCommit, err := buildCommitProps(ctx, apr, commitTarget{
+		dbName:   dbName,
+		branch:   headRef.GetPath(),
+		roots:    roots,
+		fromRoot: fromRoot,
+		amended:  headMeta,
+		isMerge:  isMerge,
+	})
+	if err != nil {
+		return "", false, err
+	}
+
+	pendingCommit, err := dSess.NewPendingCommit(ctx, dbName, roots, props)
+	if err != nil {
+		return "", false, err
+	}
+
+	// Nothing to commit, and we didn't pass --allowEmpty
+	if pendingCommit == nil && apr.Contains(cli.SkipEmptyFlag) {
+		return "", true, nil
+	} else if pendingCommit == nil {
+		return "", false, ErrNothingToCommit
+	}
+
+	if rowStats != nil {
+		// Counted from the pending commit rather than the new HEAD, which --keep leaves where it was
+		*rowStats, err = actions.GetCommitRowStats(ctx, fromRoot, pendingCommit.Roots.Staged, apr.Contains(cli.ExactRowStatsFlag))
+		if err != nil {
+			return "", false, err
+		}
+	}
+
+	if apr.Contains(cli.KeepFlag) {
+		kept, err := commitKeepingWorkingSet(ctx, dSess, dbName, pendingCommit)
+		if err != nil {
+			return "", false, err
+		}
+		h, err := kept.HashOf()
+		if err != nil {
+			return "", false, err
+		}
+		recordCommit()
+		return h.String(), false, nil
+	}
+
+	if amendMetadataOnly {
+		// The commit's tree is already fixed, so leave the staged and working roots as they were
+		pendingCommit.NextStaged, pendingCommit.Roots.Working = stagedRoot, workingRoot
+	}
+	if violationsStagedRoot != nil {
+		// Only the resolved tables were committed, so keep the other staged changes staged
+		pendingCommit.NextStaged = violationsStagedRoot
+	}
+
+	newCommit, err := dSess.DoltCommit(ctx, dbName, dSess.GetTransaction(), pendingCommit)
+	if err != nil {
+		return "", false, err
+	}
+
+	h, err := newCommit.HashOf()
+	if err != nil {
+		return "", false, err
+	}
+	commitWritten(ctx, apr, dSess, dbName, headRef, prevHash, h, props, recordCommit)
+
+	return h.String(), false, nil
+}
+
+// stageCommitRoots returns |roots| with the tables staged that the -a or -A arguments in |apr| ask dolt_commit to
+// stage, and checks that no changes are left unstaged if --no-partial is given.
+func stageCommitRoots(ctx *sql.Context, apr *argparser.ArgParseResults, roots doltdb.Roots) (doltdb.Roots, error) {
+	var err error
+	if apr.Contains(cli.UpperCaseAllFlag) {
+		roots, err = actions.StageAllTables(ctx, roots, true)
 	} else if apr.Contains(cli.AllFlag) {
 		roots, err = actions.StageModifiedAndDeletedTables(ctx, roots)
-		if err != nil {
-			return "", false, fmt.Errorf(err.Error())
+	}
+	if err != nil {
+		return doltdb.Roots{}, err
+	}
+
+	if apr.Contains(cli.NoPartialFlag) {
+		if err := actions.CheckNoUnstagedChanges(ctx, roots); err != nil {
+			return doltdb.Roots{}, noPartialCommitError(err)
 		}
 	}
+	return roots, nil
+}
+
+// commitTarget describes the commit a dolt_commit is about to make, for buildCommitProps.
+type commitTarget struct {
+	dbName string
+	branch string
+	// roots are the roots being committed, with the changes to commit staged
+	roots doltdb.Roots
+	// fromRoot is the root the commit's changes are relative to
+	fromRoot *doltdb.RootValue
+	// amended is the metadata of the HEAD commit replaced by the commit, or nil if it doesn't amend HEAD
+	amended *datas.CommitMeta
+	isMerge bool
+}
+
+// buildCommitProps works out the author, message, date and metadata of the commit described by |target| from the
+// dolt_commit arguments |apr|, and makes the checks dolt_commit makes of every commit before it's written: the
+// validators, the subject length and table limits, the linear history policy, the commit rate limit and the pre-commit
+// procedure. The function returned records the commit for the commit rate limit, and is called by commitWritten.
+func buildCommitProps(ctx *sql.Context, apr *argparser.ArgParseResults, target commitTarget) (actions.CommitStagedProps, func(), error) {
+	amendMetadataOnly := apr.Contains(cli.AmendMetadataOnlyFlag)
+	resetAuthor := apr.Contains(cli.ResetAuthorFlag)
+	headMeta := target.amended
+
+	metadata, err := cli.GetCommitMetadata(apr)
+	if err != nil {
+		return actions.CommitStagedProps{}, nil, err
+	}
+	if metadata == nil && headMeta != nil {
+		// amending keeps the metadata of the commit amended unless new metadata is given
+		metadata = headMeta.Metadata
+	}
+	metadata, err = addCommitSessionMetadata(ctx, metadata)
+	if err != nil {
+		return actions.CommitStagedProps{}, nil, err
+	}
 
 	var name, email string
 	if authorStr, ok := apr.GetValue(cli.AuthorParam); ok {
 		name, email, err = cli.ParseAuthor(authorStr)
-		if err != nil {
-			return "", false, err
-		}
+	} else if amendMetadataOnly && !resetAuthor {
+		name, email = headMeta.Name, headMeta.Email
 	} else {
-		// In SQL mode, use the current SQL user as the commit author, instead of the `dolt config` configured values.
-		// We won't have an email address for the SQL user though, so instead use the MySQL user@address notation.
-		name = ctx.Client().User
-		email = fmt.Sprintf("%s@%s", ctx.Client().User, ctx.Client().Address)
+		name, email, err = CommitAuthorFromSession(ctx)
+	}
+	if err != nil {
+		return actions.CommitStagedProps{}, nil, err
 	}
 
-	amend := apr.Contains(cli.AmendFlag)
-
-	msg, msgOk := apr.GetValue(cli.MessageArg)
+	msg, msgOk := cli.GetCommitMessage(apr)
 	if !msgOk {
-		if amend {
-			commit, err := dSess.GetHeadCommit(ctx, dbName)
-			if err != nil {
-				return "", false, err
-			}
-			commitMeta, err := commit.GetCommitMeta(ctx)
-			if err != nil {
-				return "", false, err
-			}
-			msg = commitMeta.Description
-		} else {
-			return "", false, fmt.Errorf("Must provide commit message.")
+		if headMeta == nil {
+			return actions.CommitStagedProps{}, nil, ErrMissingCommitMessage
 		}
+		msg = headMeta.Description
+	}
+	if !apr.Contains(cli.KeepLineEndingsFlag) {
+		msg = cli.NormalizeLineEndings(msg)
+	}
+	msg, err = cli.CleanupCommitMessage(msg, apr.GetValueOrDefault(cli.CleanupParam, cli.CleanupVerbatim))
+	if err != nil {
+		return actions.CommitStagedProps{}, nil, err
+	}
+	if apr.Contains(cli.SignoffFlag) {
+		msg = cli.AddSignoff(msg, name, email)
 	}
 
 	t := ctx.QueryTime()
 	if commitTimeStr, ok := apr.GetValue(cli.DateParam); ok {
-		var err error
-		t, err = cli.ParseDate(commitTimeStr)
+		t, err = cli.ParseCommitDate(apr, commitTimeStr)
+		if err != nil {
+			return actions.CommitStagedProps{}, nil, err
+		}
+	} else if nanosTime, ok, err := cli.GetCommitEpochNanos(apr); err != nil {
+		return actions.CommitStagedProps{}, nil, err
+	} else if ok {
+		t = nanosTime
+	} else if amendMetadataOnly && !resetAuthor {
+		t = headMeta.Time()
+	}
 
+	err = checkCommitSubjectLength(ctx, apr, msg)
+	if err != nil {
+		return actions.CommitStagedProps{}, nil, err
+	}
+	err = validateCommit(CommitToValidate{
+		Database: target.dbName,
+		Branch:   target.branch,
+		Message:  msg,
+		Metadata: metadata,
+		Name:     name,
+		Email:    email,
+		Amend:    headMeta != nil,
+	})
+	if err != nil {
+		return actions.CommitStagedProps{}, nil, err
+	}
+	err = checkLinearHistory(apr, target.dbName, target.branch, target.isMerge)
+	if err != nil {
+		return actions.CommitStagedProps{}, nil, err
+	}
+	recordCommit, err := checkCommitRate(ctx, target.dbName, target.branch)
+	if err != nil {
+		return actions.CommitStagedProps{}, nil, err
+	}
+
+	if !amendMetadataOnly {
+		err = checkCommitMaxTables(ctx, apr, target.dbName, target.roots)
 		if err != nil {
-			return "", false, fmt.Errorf(err.Error())
+			return actions.CommitStagedProps{}, nil, err
 		}
 	}
+	err = runPreCommitProcedure(ctx, target.dbName, target.branch, target.roots)
+	if err != nil {
+		return actions.CommitStagedProps{}, nil, err
+	}
+
+	metadata, err = addSchemaOnlyMetadata(ctx, target.fromRoot, target.roots.Staged, metadata)
+	if err != nil {
+		return actions.CommitStagedProps{}, nil, err
+	}
 
-	pendingCommit, err := dSess.NewPendingCommit(ctx, dbName, roots, actions.CommitStagedProps{
+	return actions.CommitStagedProps{
 		Message:    msg,
 		Date:       t,
 		AllowEmpty: apr.Contains(cli.AllowEmptyFlag),
 		SkipEmpty:  apr.Contains(cli.SkipEmptyFlag),
-		Amend:      amend,
+		Amend:      headMeta != nil,
 		Force:      apr.Contains(cli.ForceFlag),
 		Name:       name,
 		Email:      email,
-	})
+		Metadata:   metadata,
+		// Otherwise the commit is stamped with the time it's written, which changes its hash
+		FixedTimestamp: apr.Contains(cli.ReproducibleFlag),
+	}, recordCommit, nil
+}
+
+// commitWritten does what dolt_commit does once a commit made with |props| is written to |headRef|, moving it from
+// |prevHash| to |newHash|: it records the commit for the commit rate limit, schedules any automatic GC, and writes a
+// reflog entry if --reflog is given. The commit can't be undone by then, so nothing here fails it.
+func commitWritten(ctx *sql.Context, apr *argparser.ArgParseResults, dSess *dsess.DoltSession, dbName string, headRef ref.DoltRef, prevHash, newHash hash.Hash, props actions.CommitStagedProps, recordCommit func()) {
+	recordCommit()
+	maybeScheduleGC(ctx, dbName)
+
+	if apr.Contains(cli.ReflogFlag) {
+		writeCommitReflogEntry(ctx, dSess, dbName, headRef, prevHash, newHash, props.Amend, props.Name, props.Email)
+	}
+}
+
+// checkPrecomputedHead returns an error if the head root of |roots|, given to DoDoltCommitWithRoots, isn't the root of
+// the HEAD commit of the session's current branch, since the commit's changes are computed relative to it.
+func checkPrecomputedHead(ctx *sql.Context, dSess *dsess.DoltSession, dbName string, roots doltdb.Roots) error {
+	if roots.Head == nil || roots.Staged == nil || roots.Working == nil {
+		return fmt.Errorf("error: precomputed roots must include the head, staged and working roots")
+	}
+	headCommit, err := dSess.GetHeadCommit(ctx, dbName)
 	if err != nil {
-		return "", false, err
+		return err
+	}
+	headRoot, err := headCommit.GetRootValue(ctx)
+	if err != nil {
+		return err
+	}
+	headHash, err := headRoot.HashOf()
+	if err != nil {
+		return err
 	}
+	givenHash, err := roots.Head.HashOf()
+	if err != nil {
+		return err
+	}
+	if givenHash != headHash {
+		return fmt.Errorf("error: the precomputed head root %s is not the root of HEAD %s", givenHash.String(), headHash.String())
+	}
+	return nil
+}
 
-	// Nothing to commit, and we didn't pass --allowEmpty
-	if pendingCommit == nil && apr.Contains(cli.SkipEmptyFlag) {
-		return "", true, nil
-	} else if pendingCommit == nil {
-		return "", false, errors.New("nothing to commit")
+// noPartialCommitError adds advice on committing with --no-partial to |err|, returned by
+// actions.CheckNoUnstagedChanges, if it lists the tables with unstaged changes.
+func noPartialCommitError(err error) error {
+	if !actions.IsTblError(err) {
+		return err
 	}
+	return fmt.Errorf("%w; --%s requires them to be staged, or stashed, before committing", err, cli.NoPartialFlag)
+}
 
-	newCommit, err := dSess.DoltCommit(ctx, dbName, dSess.GetTransaction(), pendingCommit)
+// commitKeepingWorkingSet writes |pendingCommit|, with the HEAD commit of the session's current branch as its parent,
+// for dolt_commit --keep. The branch isn't moved, and the session's working set is left as it is, so the commit isn't
+// referenced by anything.
+func commitKeepingWorkingSet(ctx *sql.Context, dSess *dsess.DoltSession, dbName string, pendingCommit *doltdb.PendingCommit) (*doltdb.Commit, error) {
+	ddb, ok := dSess.GetDoltDB(ctx, dbName)
+	if !ok {
+		return nil, fmt.Errorf("Could not load database %s", dbName)
+	}
+	headCommit, err := dSess.GetHeadCommit(ctx, dbName)
 	if err != nil {
-		return "", false, err
+		return nil, err
+	}
+	headHash, err := headCommit.HashOf()
+	if err != nil {
+		return nil, err
 	}
 
-	h, err := newCommit.HashOf()
+	opts := pendingCommit.CommitOptions
+	opts.Parents = append([]hash.Hash{headHash}, opts.Parents...)
+	return ddb.CommitDangling(ctx, pendingCommit.Val, opts)
+}
+
+// sessionCommitIsMerge returns whether committing the session's current branch in the database named would create a
+// merge commit: if a merge is in progress, or if |amend| is true and HEAD is a merge commit.
+func sessionCommitIsMerge(ctx *sql.Context, dSess *dsess.DoltSession, dbName string, amend bool) (bool, error) {
+	ws, err := dSess.WorkingSet(ctx, dbName)
 	if err != nil {
-		return "", false, err
+		return false, err
+	}
+	if ws.MergeActive() {
+		return true, nil
+	}
+	if !amend {
+		return false, nil
+	}
+	headCommit, err := dSess.GetHeadCommit(ctx, dbName)
+	if err != nil {
+		return false, err
 	}
+	return headCommit.NumParents() > 1, nil
+}
 
-	return h.String(), false, nil
+// commitTreeRoots returns |roots| with the staged and working roots replaced by the root value whose hash is |treeStr|,
+// so that dolt_commit --tree commits it as is and leaves the working set matching the new commit. The working set must
+// have no changes, since they would be lost.
+func commitTreeRoots(ctx *sql.Context, dSess *dsess.DoltSession, dbName string, roots doltdb.Roots, treeStr string) (doltdb.Roots, error) {
+	h, ok := hash.MaybeParse(treeStr)
+	if !ok {
+		return roots, fmt.Errorf("error: invalid --%s hash '%s'", cli.TreeParam, treeStr)
+	}
+
+	headHash, err := roots.Head.HashOf()
+	if err != nil {
+		return roots, err
+	}
+	stagedHash, err := roots.Staged.HashOf()
+	if err != nil {
+		return roots, err
+	}
+	workingHash, err := roots.Working.HashOf()
+	if err != nil {
+		return roots, err
+	}
+	if stagedHash != headHash || workingHash != headHash {
+		return roots, fmt.Errorf("error: --%s cannot be used with staged or working changes, commit or reset them first", cli.TreeParam)
+	}
+
+	ddb, ok := dSess.GetDoltDB(ctx, dbName)
+	if !ok {
+		return roots, fmt.Errorf("Could not load database %s", dbName)
+	}
+	tree, err := ddb.ReadRootValue(ctx, h)
+	if err != nil {
+		return roots, fmt.Errorf("error: could not read the --%s root value %s: %w", cli.TreeParam, h.String(), err)
+	}
+
+	roots.Staged, roots.Working = tree, tree
+	return roots, nil
 }
 
+// violationsResolvedRoots returns |roots| with the staged root replaced by HEAD plus the working version of each table
+// which has constraint violations in HEAD but none in the working set, so that dolt_commit --violations-resolved
+// commits only those resolutions. It also returns the staged root to leave in the working set after the commit, which
+// is the staged root given with the resolved tables staged as well.
+func violationsResolvedRoots(ctx *sql.Context, roots doltdb.Roots) (doltdb.Roots, *doltdb.RootValue, error) {
+	headViolations, err := roots.Head.TablesWithConstraintViolations(ctx)
+	if err != nil {
+		return roots, nil, err
+	}
+	workingViolations, err := roots.Working.TablesWithConstraintViolations(ctx)
+	if err != nil {
+		return roots, nil, err
+	}
+	stillViolating := set.NewStrSet(workingViolations)
+
+	var resolved []string
+	for _, tblName := range headViolations {
+		if !stillViolating.Contains(tblName) {
+			resolved = append(resolved, tblName)
+		}
+	}
+
+	commitRoots, err := actions.StageTables(ctx, doltdb.Roots{Head: roots.Head, Staged: roots.Head, Working: roots.Working}, resolved, false)
+	if err != nil {
+		return roots, nil, err
+	}
+	stagedRoots, err := actions.StageTables(ctx, roots, resolved, false)
+	if err != nil {
+		return roots, nil, err
+	}
+	return commitRoots, stagedRoots.Staged, nil
+}
+
+// CommitEmailResolver returns the email to record for commits by the SQL user |user| when no author is given, and
+// whether it knows one.
+type CommitEmailResolver func(ctx *sql.Context, user string) (email string, ok bool, err error)
+
+// commitEmailResolver is consulted by CommitAuthorFromSession before it falls back to the user@address form. It's nil
+// unless set with SetCommitEmailResolver.
+var commitEmailResolver CommitEmailResolver
+
+// SetCommitEmailResolver sets the function used to find the email of the SQL user making a commit without --author.
+// Applications embedding Dolt use this to map users to canonical emails, since the client address in the default
+// user@address form changes between connections. Should be called during initialization.
+func SetCommitEmailResolver(resolver CommitEmailResolver) {
+	commitEmailResolver = resolver
+}
+
+// CommitAuthorFromSession returns the author to use for a commit when --author isn't given. This is the value of the
+// @@dolt_author session variable if it's set, which lets an application connected as a shared user record the real
+// actor, and otherwise the current SQL user, with the email given by the CommitEmailResolver, if any knows it.
+func CommitAuthorFromSession(ctx *sql.Context) (name, email string, err error) {
+	author, err := ctx.GetSessionVariable(ctx, dsess.DoltAuthor)
+	if err != nil {
+		return "", "", err
+	}
+
+	if authorStr, ok := author.(string); ok && authorStr != "" {
+		name, email, err = cli.ParseAuthor(authorStr)
+		if err != nil {
+			return "", "", fmt.Errorf("invalid value for @@%s: %w", dsess.DoltAuthor, err)
+		}
+		return name, email, nil
+	}
+
+	// In SQL mode, use the current SQL user as the commit author, instead of the `dolt config` configured values.
+	user := ctx.Client().User
+	if commitEmailResolver != nil {
+		email, ok, err := commitEmailResolver(ctx, user)
+		if err != nil {
+			return "", "", err
+		}
+		if ok {
+			return user, email, nil
+		}
+	}
+	// Without a known email address for the SQL user, use the MySQL user@address notation instead.
+	return user, fmt.Sprintf("%s@%s", user, ctx.Client().Address), nil
+}
+
+const (
+	commitSessionIDMetadataKey     = "session_id"
+	commitClientAddressMetadataKey = "client_address"
+)
+
+// addCommitSessionMetadata returns |metadata| with the id and client address of the session making the commit added,
+// if @@dolt_commit_record_session is enabled, so that a commit can be traced back to the connection which made it.
+// These replace any values given for the same keys with --meta, so they can't be forged.
+func addCommitSessionMetadata(ctx *sql.Context, metadata map[string]string) (map[string]string, error) {
+	if !dsess.RecordCommitSession() {
+		return metadata, nil
+	}
+
+	md := make(map[string]string, len(metadata)+2)
+	for k, v := range metadata {
+		md[k] = v
+	}
+	md[commitSessionIDMetadataKey] = strconv.FormatUint(uint64(ctx.Session.ID()), 10)
+	md[commitClientAddressMetadataKey] = ctx.Client().Address
+
+	if err := datas.ValidateCommitMetadata(md); err != nil {
+		return nil, err
+	}
+	return md, nil
+}
+
+const commitSchemaOnlyMetadataKey = "schema_only"
+
+// addSchemaOnlyMetadata returns |metadata| with schema_only=true added if the changes from |fromRoot| to |toRoot| only
+// change schemas and not rows, so that consumers of the commit can skip validating its data. Otherwise, any schema_only
+// entry, e.g. from an amended commit, is removed. A schema change which rewrites rows, such as adding a column with a
+// default value to a table with rows, also changes rows.
+func addSchemaOnlyMetadata(ctx *sql.Context, fromRoot, toRoot *doltdb.RootValue, metadata map[string]string) (map[string]string, error) {
+	deltas, err := diff.GetTableDeltas(ctx, fromRoot, toRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	schemaOnly := len(deltas) > 0
+	for _, td := range deltas {
+		dataChanged, err := td.HasDataChanged(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if dataChanged {
+			schemaOnly = false
+			break
+		}
+	}
+
+	if _, ok := metadata[commitSchemaOnlyMetadataKey]; !ok && !schemaOnly {
+		return metadata, nil
+	}
+	md := make(map[string]string, len(metadata)+1)
+	for k, v := range metadata {
+		md[k] = v
+	}
+	delete(md, commitSchemaOnlyMetadataKey)
+	if schemaOnly {
+		md[commitSchemaOnlyMetadataKey] = "true"
+	}
+	return md, nil
+}
+
+// headParentRoot returns the root value of the first parent of the HEAD commit of the session's current branch, or of
+// HEAD itself if it has no parents.
+func headParentRoot(ctx *sql.Context, dSess *dsess.DoltSession, dbName string) (*doltdb.RootValue, error) {
+	headCommit, err := dSess.GetHeadCommit(ctx, dbName)
+	if err != nil {
+		return nil, err
+	}
+	if headCommit.NumParents() == 0 {
+		return headCommit.GetRootValue(ctx)
+	}
+	parent, err := headCommit.GetParent(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	return parent.GetRootValue(ctx)
+}
+
+// ErrProtectedBranch is returned by dolt_commit when committing directly to a branch protected by
+// @@<db>_protected_branches.
+var ErrProtectedBranch = goerrors.NewKind("branch %s is protected by @@%s; use a pull request to change it")
+
+// checkProtectedBranch returns an error if |branch| matches one of the patterns in @@<db>_protected_branches, unless
+// the user is an admin of the branch in dolt_branch_control.
+func checkProtectedBranch(ctx *sql.Context, dbName, branch string) error {
+	protected, err := isProtectedBranch(dbName, branch)
+	if err != nil || !protected {
+		return err
+	}
+	if branch_control.CheckAccessForBranch(ctx, branch, branch_control.Permissions_Admin) == nil {
+		return nil
+	}
+	return ErrProtectedBranch.New(branch, dsess.ProtectedBranchesKey(dbName))
+}
+
+// isProtectedBranch returns whether |branch| matches one of the patterns in @@<db>_protected_branches.
+func isProtectedBranch(dbName, branch string) (bool, error) {
+	for _, pattern := range strings.Split(dsess.GetProtectedBranches(dbName), ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		matched, err := path.Match(pattern, branch)
+		if err != nil {
+			return false, fmt.Errorf("error: invalid branch pattern '%s' in @@%s", pattern, dsess.ProtectedBranchesKey(dbName))
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ErrMergeCommitNotAllowed is returned by dolt_commit when it would create a merge commit on a branch which must have a
+// linear history.
+var ErrMergeCommitNotAllowed = goerrors.NewKind("error: cannot create a merge commit on branch %s, which must have a linear history because of %s; rebase onto it instead")
+
+// checkLinearHistory returns an error if a commit to |branch| would be a merge commit, i.e. have more than one parent,
+// when merge commits aren't allowed there: when --no-merges is given, or when |branch| is protected by
+// @@<db>_protected_branches and @@dolt_protected_branches_linear_history is enabled.
+func checkLinearHistory(apr *argparser.ArgParseResults, dbName, branch string, isMerge bool) error {
+	if !isMerge {
+		return nil
+	}
+	if apr.Contains(cli.NoMergesFlag) {
+		return ErrMergeCommitNotAllowed.New(branch, "--"+cli.NoMergesFlag)
+	}
+	if !dsess.ProtectedBranchesRequireLinearHistory() {
+		return nil
+	}
+	protected, err := isProtectedBranch(dbName, branch)
+	if err != nil {
+		return err
+	}
+	if protected {
+		return ErrMergeCommitNotAllowed.New(branch, "@@"+dsess.ProtectedBranchesLinear)
+	}
+	return nil
+}
+
+// checkCommitMaxTables returns an error if the staged changes in |roots| change more tables than the maximum given with
+// --max-tables, or otherwise with @@<db>_commit_max_tables. A maximum of 0 means there's no limit.
+func checkCommitMaxTables(ctx *sql.Context, apr *argparser.ArgParseResults, dbName string, roots doltdb.Roots) error {
+	maxTables, ok := apr.GetUint(cli.MaxTablesParam)
+	source := "--" + cli.MaxTablesParam
+	if !ok {
+		n := dsess.GetCommitMaxTables(dbName)
+		if n <= 0 {
+			return nil
+		}
+		maxTables, source = uint64(n), "@@"+dsess.CommitMaxTablesKey(dbName)
+	}
+	if maxTables == 0 {
+		return nil
+	}
+
+	deltas, err := diff.GetTableDeltas(ctx, roots.Head, roots.Staged)
+	if err != nil {
+		return err
+	}
+	if uint64(len(deltas)) > maxTables {
+		return ErrCommitTooManyTables.New(len(deltas), maxTables, source)
+	}
+	return nil
+}
+
+// checkCommitSubjectLength returns an error if the first line of |msg| is longer than the maximum given with
+// --max-subject-length, or otherwise with @@dolt_commit_max_subject_length. A maximum of 0 means there's no limit.
+func checkCommitSubjectLength(ctx *sql.Context, apr *argparser.ArgParseResults, msg string) error {
+	maxLen, ok := apr.GetUint(cli.MaxSubjectLengthParam)
+	source := "--" + cli.MaxSubjectLengthParam
+	if !ok {
+		val, err := ctx.GetSessionVariable(ctx, dsess.CommitMaxSubjectLength)
+		if err != nil {
+			return err
+		}
+		n, ok := val.(int64)
+		if !ok || n <= 0 {
+			return nil
+		}
+		maxLen, source = uint64(n), "@@"+dsess.CommitMaxSubjectLength
+	}
+	if maxLen == 0 {
+		return nil
+	}
+
+	n := utf8.RuneCountInString(commitSubject(msg))
+	if uint64(n) > maxLen {
+		return ErrCommitSubjectTooLong.New(n, maxLen, source)
+	}
+	return nil
+}
+
+// writeCommitReflogEntry records the update of the branch head |headRef| from |from| to |to| made by a commit in the
+// reflog of the database named. The reflog isn't part of the database, so a failure to write it, or a crash before it's
+// written, leaves the commit without a reflog entry. A failure is reported as a warning.
+func writeCommitReflogEntry(ctx *sql.Context, dSess *dsess.DoltSession, dbName string, headRef ref.DoltRef, from, to hash.Hash, amend bool, name, email string) {
+	op := "commit"
+	if amend {
+		op = "commit (amend)"
+	}
+
+	err := dSess.AppendReflogEntry(dbName, env.ReflogEntry{
+		Ref:       headRef.String(),
+		Operation: op,
+		FromHash:  from.String(),
+		ToHash:    to.String(),
+		Name:      name,
+		Email:     email,
+		Timestamp: ctx.QueryTime(),
+	})
+	if err != nil {
+		warnReflogNotWritten(ctx, "commit", err)
+	}
+}
+
+// ErrInvalidDoltArgType is returned when an argument to a dolt procedure has a type that can't be converted to text
+// without loss.
+var ErrInvalidDoltArgType = goerrors.NewKind("invalid argument %d: values of type %s can't be used as arguments, use a string instead")
+
+// ErrNullDoltArg is returned when an argument to a dolt procedure is NULL.
+var ErrNullDoltArg = goerrors.NewKind("invalid argument %d: arguments can't be NULL")
+
+// DoltArgsError is returned by a dolt procedure given more than one invalid argument, and holds the error for each,
+// so that they can all be fixed at once.
+type DoltArgsError struct {
+	Errs []error
+}
+
+func (e *DoltArgsError) Error() string {
+	msgs := make([]string, len(e.Errs))
+	for i, err := range e.Errs {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// getDoltArgs evaluates |children| against |row| and returns them as the text arguments of a dolt procedure. Arguments
+// whose type can't be converted to text without loss, such as binary, JSON and spatial values, and NULL arguments are
+// rejected with an error giving their position, counting from 1. Every argument is checked, and if more than one is
+// invalid a *DoltArgsError listing them all is returned.
 func getDoltArgs(ctx *sql.Context, row sql.Row, children []sql.Expression) ([]string, error) {
 	args := make([]string, len(children))
+	var argErrs []error
 	for i := range children {
+		typ := children[i].Type()
+		_, isSpatial := typ.(sql.SpatialColumnType)
+		if types.IsBinaryType(typ) || types.IsJSON(typ) || isSpatial {
+			argErrs = append(argErrs, ErrInvalidDoltArgType.New(i+1, typ.String()))
+			continue
+		}
+
 		childVal, err := children[i].Eval(ctx, row)
 
 		if err != nil {
 			return nil, err
 		}
+		if childVal == nil {
+			argErrs = append(argErrs, ErrNullDoltArg.New(i+1))
+			continue
+		}
 
 		text, _, err := types.Text.Convert(childVal)
 
 		if err != nil {
-			return nil, err
+			argErrs = append(argErrs, fmt.Errorf("invalid argument %d: %w", i+1, err))
+			continue
 		}
 
 		args[i] = text.(string)
 	}
 
-	return args, nil
+	switch len(argErrs) {
+	case 0:
+		return args, nil
+	case 1:
+		return nil, argErrs[0]
+	default:
+		return nil, &DoltArgsError{Errs: argErrs}
+	}
 }
@@ -0,0 +1,114 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// commitDetailedSchema is the schema of the result of dolt_commit_detailed. The hash is NULL if the commit was skipped.
+// The row count columns are NULL unless --row-stats or --exact-row-stats is given.
+var commitDetailedSchema = sql.Schema{
+	&sql.Column{Name: "commit_hash", Type: types.LongText, Nullable: true},
+	&sql.Column{Name: "skipped", Type: types.Boolean, Nullable: false},
+	&sql.Column{Name: "tables_committed", Type: types.Int64, Nullable: false},
+	&sql.Column{Name: "rows_added", Type: types.Int64, Nullable: true},
+	&sql.Column{Name: "rows_modified", Type: types.Int64, Nullable: true},
+	&sql.Column{Name: "rows_deleted", Type: types.Int64, Nullable: true},
+}
+
+// doltCommitDetailed is a version of dolt_commit which takes the same arguments, and returns whether the commit was
+// skipped and the number of tables it changed along with its hash. With --row-stats or --exact-row-stats, it also
+// returns the number of rows the commit added, modified and deleted.
+func doltCommitDetailed(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	apr, err := cli.CreateCommitArgParser().Parse(args)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats *actions.CommitRowStats
+	if apr.Contains(cli.RowStatsFlag) || apr.Contains(cli.ExactRowStatsFlag) {
+		stats = &actions.CommitRowStats{}
+	}
+	commitHash, skipped, err := doDoltCommitWithRoots(ctx, args, nil, stats)
+	if err != nil {
+		return nil, err
+	}
+	if skipped {
+		return rowToIter(nil, true, int64(0), nil, nil, nil), nil
+	}
+
+	tables, err := countTablesCommitted(ctx, ctx.GetCurrentDatabase(), commitHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if stats == nil {
+		return rowToIter(commitHash, false, int64(tables), nil, nil, nil), nil
+	}
+	return rowToIter(commitHash, false, int64(tables), int64(stats.Added), int64(stats.Modified), int64(stats.Deleted)), nil
+}
+
+// countTablesCommitted returns the number of tables changed by the commit with the hash given, relative to its first
+// parent.
+func countTablesCommitted(ctx *sql.Context, dbName, commitHash string) (int, error) {
+	deltas, err := commitTableDeltas(ctx, dbName, commitHash)
+	if err != nil {
+		return 0, err
+	}
+	return len(deltas), nil
+}
+
+// commitTableDeltas returns the deltas of the tables changed by the commit with the hash given, relative to its first
+// parent. The commit is looked up by hash, since with --working-set it isn't the head of the session's branch.
+func commitTableDeltas(ctx *sql.Context, dbName, commitHash string) ([]diff.TableDelta, error) {
+	dSess := dsess.DSessFromSess(ctx.Session)
+	ddb, ok := dSess.GetDoltDB(ctx, dbName)
+	if !ok {
+		return nil, fmt.Errorf("Could not load database %s", dbName)
+	}
+
+	commit, err := ddb.ReadCommit(ctx, hash.Parse(commitHash))
+	if err != nil {
+		return nil, err
+	}
+	toRoot, err := commit.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fromRoot := toRoot
+	if commit.NumParents() > 0 {
+		parent, err := commit.GetParent(ctx, 0)
+		if err != nil {
+			return nil, err
+		}
+		fromRoot, err = parent.GetRootValue(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return diff.GetTableDeltas(ctx, fromRoot, toRoot)
+}
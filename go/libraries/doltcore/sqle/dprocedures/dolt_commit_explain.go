@@ -0,0 +1,84 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"strconv"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+)
+
+// The kinds of rows returned by dolt_commit_explain.
+const (
+	explainArgKind        = "arg"
+	explainFlagKind       = "flag"
+	explainOptionKind     = "option"
+	explainPositionalKind = "positional"
+)
+
+var commitExplainSchema = stringSchema("kind", "name", "value")
+
+// doltCommitExplain is a diagnostic stored procedure which shows how dolt_commit would interpret the arguments given
+// to it, without committing. It takes the same arguments as dolt_commit.
+func doltCommitExplain(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	rows, err := explainCommitArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// explainCommitArgs parses |args| with the parser used by dolt_commit and returns a row of (kind, name, value) for
+// each of them. The arguments are listed first, exactly as passed to the parser, as "arg" rows named by their
+// position counting from 1. Then each flag and option given is listed, in the order the parser declares them, as a
+// "flag" row with an empty value, or an "option" row with its value, one for each time a repeated option was given.
+// Positional arguments come last as "positional" rows. The arguments are only parsed, so combinations of them which
+// dolt_commit would refuse aren't reported, but a parse error is returned as it would be by dolt_commit.
+func explainCommitArgs(args []string) ([]sql.Row, error) {
+	ap := cli.CreateCommitArgParser()
+	apr, err := ap.Parse(args)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]sql.Row, 0, len(args)*2)
+	for i, arg := range args {
+		rows = append(rows, sql.Row{explainArgKind, strconv.Itoa(i + 1), arg})
+	}
+	for _, opt := range ap.Supported {
+		if !apr.Contains(opt.Name) {
+			continue
+		}
+		switch opt.OptType {
+		case argparser.OptionalFlag:
+			rows = append(rows, sql.Row{explainFlagKind, opt.Name, ""})
+		case argparser.OptionalRepeatedValue:
+			vals, _ := apr.GetValueRepeated(opt.Name)
+			for _, val := range vals {
+				rows = append(rows, sql.Row{explainOptionKind, opt.Name, val})
+			}
+		default:
+			val, _ := apr.GetValue(opt.Name)
+			rows = append(rows, sql.Row{explainOptionKind, opt.Name, val})
+		}
+	}
+	for i, arg := range apr.Args {
+		rows = append(rows, sql.Row{explainPositionalKind, strconv.Itoa(i + 1), arg})
+	}
+	return rows, nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/utils/argparser"
+	"github.com/dolthub/dolt/go/store/datas"
+)
+
+// workingSetCommitUnsupportedArgs are the dolt_commit arguments which can't be combined with --working-set, since
+// they operate on the session's current branch.
+var workingSetCommitUnsupportedArgs = []string{cli.AmendFlag, cli.AmendMetadataOnlyFlag, cli.RowStatsFlag, cli.ExactRowStatsFlag, cli.TreeParam, cli.ViolationsResolvedFlag}
+
+// doDoltCommitOnWorkingSet commits the staged changes of the working set named |wsName| to its branch, and updates
+// the working set, without changing the branch checked out by the session. This writes directly to the database
+// rather than through the session's transaction, so the working set must not be that of the session's current
+// branch.
+func doDoltCommitOnWorkingSet(ctx *sql.Context, apr *argparser.ArgParseResults, dbName, wsName string) (string, bool, error) {
+	for _, arg := range workingSetCommitUnsupportedArgs {
+		if apr.Contains(arg) {
+			return "", false, fmt.Errorf("error: --%s cannot be used with --%s", arg, cli.WorkingSetParam)
+		}
+	}
+
+	wsRef := ref.NewWorkingSetRef(wsName)
+	headRef, err := wsRef.ToHeadRef()
+	if err != nil {
+		return "", false, fmt.Errorf("invalid working set %s: %w", wsName, err)
+	}
+	if err := branch_control.CheckAccessForBranch(ctx, headRef.GetPath(), branch_control.Permissions_Write); err != nil {
+		return "", false, err
+	}
+	if err := checkProtectedBranch(ctx, dbName, headRef.GetPath()); err != nil {
+		return "", false, err
+	}
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	currentRef, err := dSess.CWBHeadRef(ctx, dbName)
+	if err != nil {
+		return "", false, err
+	}
+	if ref.Equals(currentRef, headRef) {
+		return "", false, fmt.Errorf("error: %s is checked out in this session, commit it without --%s", headRef.GetPath(), cli.WorkingSetParam)
+	}
+
+	ddb, ok := dSess.GetDoltDB(ctx, dbName)
+	if !ok {
+		return "", false, fmt.Errorf("Could not load database %s", dbName)
+	}
+
+	ws, err := ddb.ResolveWorkingSet(ctx, wsRef)
+	if err != nil {
+		return "", false, fmt.Errorf("could not load working set %s: %w", wsRef.GetPath(), err)
+	}
+	prevWsHash, err := ws.HashOf()
+	if err != nil {
+		return "", false, err
+	}
+
+	headCommit, err := ddb.ResolveCommitRef(ctx, headRef)
+	if err != nil {
+		return "", false, err
+	}
+	prevHash, err := headCommit.HashOf()
+	if err != nil {
+		return "", false, err
+	}
+	headRoot, err := headCommit.GetRootValue(ctx)
+	if err != nil {
+		return "", false, err
+	}
+
+	roots := doltdb.Roots{Head: headRoot, Staged: ws.StagedRoot(), Working: ws.WorkingRoot()}
+	roots, err = stageCommitRoots(ctx, apr, roots)
+	if err != nil {
+		return "", false, err
+	}
+
+	props, recordCommit, err := buildCommitProps(ctx, apr, commitTarget{
+		dbName:   dbName,
+		branch:   headRef.GetPath(),
+		roots:    roots,
+		fromRoot: roots.Head,
+		isMerge:  ws.MergeActive(),
+	})
+	if err != nil {
+		return "", false, err
+	}
+
+	var mergeParents []*doltdb.Commit
+	if ws.MergeActive() {
+		mergeParents = []*doltdb.Commit{ws.MergeState().Commit()}
+	}
+
+	pendingCommit, err := actions.GetCommitStaged(ctx, roots, ws, mergeParents, ddb, props)
+	if err != nil {
+		return "", false, err
+	}
+
+	// Nothing to commit, and we didn't pass --allowEmpty
+	if pendingCommit == nil && apr.Contains(cli.SkipEmptyFlag) {
+		return "", true, nil
+	} else if pendingCommit == nil {
+		return "", false, ErrNothingToCommit
+	}
+
+	// The session's transaction only covers its current branch, so the commit is written directly. It fails, rather than
+	// overwriting, if the working set changed since it was loaded.
+	newWs := ws.WithStagedRoot(pendingCommit.Roots.Staged).WithWorkingRoot(pendingCommit.Roots.Working).ClearMerge()
+	newCommit, err := ddb.CommitWithWorkingSet(ctx, headRef, wsRef, pendingCommit, newWs, prevWsHash, &datas.WorkingSetMeta{
+		Name:        dSess.Username(),
+		Email:       dSess.Email(),
+		Timestamp:   uint64(time.Now().Unix()),
+		Description: "dolt_commit --working-set",
+	}, nil)
+	if err != nil {
+		return "", false, err
+	}
+
+	h, err := newCommit.HashOf()
+	if err != nil {
+		return "", false, err
+	}
+	commitWritten(ctx, apr, dSess, dbName, headRef, prevHash, h, props, recordCommit)
+
+	return h.String(), false, nil
+}
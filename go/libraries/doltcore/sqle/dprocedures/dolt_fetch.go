@@ -18,6 +18,7 @@ import (
 	"fmt"
 
 	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/mysql"
 
 	"github.com/dolthub/dolt/go/cmd/dolt/cli"
 	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
@@ -67,9 +68,46 @@ func doDoltFetch(ctx *sql.Context, args []string) (int, error) {
 		return 1, err
 	}
 
-	err = actions.FetchRefSpecs(ctx, dbData, srcDB, refSpecs, remote, ref.UpdateMode{Force: true}, runProgFuncs, stopProgFuncs)
+	updates, err := actions.FetchRefSpecs(ctx, dbData, srcDB, refSpecs, remote, ref.UpdateMode{Force: true}, runProgFuncs, stopProgFuncs)
 	if err != nil {
 		return cmdFailure, fmt.Errorf("fetch failed: %w", err)
 	}
+	recordTrackingRefUpdates(ctx, sess, dbName, "fetch", updates...)
 	return cmdSuccess, nil
 }
+
+// recordTrackingRefUpdates records the |updates| to remote-tracking refs made by the operation |op| in the reflog of
+// the database named, so that status can tell when a remote branch was rewritten. The refs have already been updated
+// by then, so a failure to write the reflog is only reported as a warning.
+func recordTrackingRefUpdates(ctx *sql.Context, sess *dsess.DoltSession, dbName, op string, updates ...actions.TrackingRefUpdate) {
+	if len(updates) == 0 {
+		return
+	}
+	name, email, err := CommitAuthorFromSession(ctx)
+	if err != nil {
+		warnReflogNotWritten(ctx, op, err)
+		return
+	}
+	for _, u := range updates {
+		if u.From == u.To {
+			continue
+		}
+		err := sess.AppendReflogEntry(dbName, u.ReflogEntry(op, name, email, ctx.QueryTime()))
+		if err != nil {
+			warnReflogNotWritten(ctx, op, err)
+			return
+		}
+	}
+}
+
+// warnReflogNotWritten logs, and warns the client, that the reflog entry for an update made by the operation |op|
+// couldn't be written because of |err|.
+func warnReflogNotWritten(ctx *sql.Context, op string, err error) {
+	msg := fmt.Sprintf("the %s succeeded, but it couldn't be recorded in the reflog: %s", op, err.Error())
+	ctx.GetLogger().Warn(msg)
+	ctx.Session.Warn(&sql.Warning{
+		Level:   "Warning",
+		Code:    mysql.ERUnknownError,
+		Message: msg,
+	})
+}
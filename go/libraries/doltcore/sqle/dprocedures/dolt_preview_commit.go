@@ -0,0 +1,130 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// doltPreviewCommit is the stored procedure version for the CLI command `dolt commit`, but only reports the hash of the
+// commit that would be created, without creating it. The arguments are the same as for dolt_commit.
+func doltPreviewCommit(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	commitHash, err := doDoltPreviewCommit(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(commitHash), nil
+}
+
+// doDoltPreviewCommit builds the pending commit that dolt_commit would create with the |args| given and returns its
+// hash. Because the hash of a commit depends on its metadata, the author, date and message must all be given
+// explicitly, along with --reproducible so that dolt_commit uses the date given as the commit's timestamp, and the
+// commit must not amend HEAD.
+func doDoltPreviewCommit(ctx *sql.Context, args []string) (string, error) {
+	apr, err := cli.CreateCommitArgParser().Parse(args)
+	if err != nil {
+		return "", err
+	}
+
+	if err := cli.VerifyCommitArgs(apr); err != nil {
+		return "", err
+	}
+
+	if apr.Contains(cli.AmendFlag) || apr.Contains(cli.AmendMetadataOnlyFlag) {
+		return "", fmt.Errorf("error: dolt_preview_commit does not support amending a commit")
+	}
+
+	for _, param := range []string{cli.AuthorParam, cli.MessageArg} {
+		if !apr.Contains(param) {
+			return "", fmt.Errorf("error: --%s is required, since the commit hash depends on it", param)
+		}
+	}
+	if !apr.Contains(cli.DateParam) && !apr.Contains(cli.DateEpochNanosParam) {
+		return "", fmt.Errorf("error: --%s or --%s is required, since the commit hash depends on it", cli.DateParam, cli.DateEpochNanosParam)
+	}
+	if !apr.Contains(cli.ReproducibleFlag) {
+		return "", fmt.Errorf("error: --%s is required, since otherwise dolt_commit stamps the commit with the time it's written, which changes its hash", cli.ReproducibleFlag)
+	}
+
+	dbName := ctx.GetCurrentDatabase()
+	dSess := dsess.DSessFromSess(ctx.Session)
+	roots, ok := dSess.GetRoots(ctx, dbName)
+	if !ok {
+		return "", fmt.Errorf("Could not load database %s", dbName)
+	}
+
+	roots, err = stageCommitRoots(ctx, apr, roots)
+	if err != nil {
+		return "", err
+	}
+	if apr.Contains(cli.ViolationsResolvedFlag) {
+		ws, err := dSess.WorkingSet(ctx, dbName)
+		if err != nil {
+			return "", err
+		}
+		if ws.MergeActive() {
+			return "", fmt.Errorf("error: cannot use --%s while a merge is in progress", cli.ViolationsResolvedFlag)
+		}
+		roots, _, err = violationsResolvedRoots(ctx, roots)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	headRef, err := dSess.CWBHeadRef(ctx, dbName)
+	if err != nil {
+		return "", err
+	}
+	isMerge, err := sessionCommitIsMerge(ctx, dSess, dbName, false)
+	if err != nil {
+		return "", err
+	}
+
+	// The commit isn't written, so it isn't recorded for the commit rate limit
+	props, _, err := buildCommitProps(ctx, apr, commitTarget{
+		dbName:   dbName,
+		branch:   headRef.GetPath(),
+		roots:    roots,
+		fromRoot: roots.Head,
+		isMerge:  isMerge,
+	})
+	if err != nil {
+		return "", err
+	}
+	pendingCommit, err := dSess.NewPendingCommit(ctx, dbName, roots, props)
+	if err != nil {
+		return "", err
+	}
+	if pendingCommit == nil {
+		return "", ErrNothingToCommit
+	}
+
+	ddb, ok := dSess.GetDoltDB(ctx, dbName)
+	if !ok {
+		return "", fmt.Errorf("Could not load database %s", dbName)
+	}
+
+	h, err := ddb.PreviewCommitHash(ctx, headRef, pendingCommit)
+	if err != nil {
+		return "", err
+	}
+
+	return h.String(), nil
+}
@@ -128,10 +128,11 @@ func doDoltPull(ctx *sql.Context, args []string) (int, int, error) {
 			}
 
 			// TODO: this could be replaced with a canFF check to test for error
-			err = dbData.Ddb.FastForward(ctx, remoteTrackRef, srcDBCommit)
+			update, err := actions.FastForwardTrackingRef(ctx, dbData.Ddb, remoteTrackRef, srcDBCommit)
 			if err != nil {
 				return noConflictsOrViolations, threeWayMerge, fmt.Errorf("fetch failed; %w", err)
 			}
+			recordTrackingRefUpdates(ctx, sess, dbName, "pull", update)
 
 			// Only merge iff branch is current branch and there is an upstream set (pullSpec.Branch is set to nil if there is no upstream)
 			if branchRef != pullSpec.Branch {
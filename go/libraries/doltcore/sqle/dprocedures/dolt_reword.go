@@ -0,0 +1,252 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	goerrors "gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/cmd/dolt/cli"
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/ref"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/store/datas"
+)
+
+// ErrRewordSharedCommit is returned by dolt_reword when the commit to reword is reachable from another branch, and
+// --force wasn't given.
+var ErrRewordSharedCommit = goerrors.NewKind("commit %s is reachable from branch %s, use --force to reword it anyway")
+
+// doltReword is the stored procedure which replaces the message, and optionally the author, of a commit on the
+// current branch. It's called as dolt_reword(revision, message), and returns the hash of the new branch head.
+func doltReword(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	commitHash, err := doDoltReword(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(commitHash), nil
+}
+
+// doDoltReword rewrites the commit given by |args| with a new message, and rewrites each commit after it on the
+// first-parent history of the current branch to point at the rewritten commit, keeping their trees and metadata. The
+// branch is moved to the rewritten head. Other branches which reach the commit keep the original history, so this is
+// refused for them unless --force is given.
+func doDoltReword(ctx *sql.Context, args []string) (string, error) {
+	apr, err := cli.CreateRewordArgParser().Parse(args)
+	if err != nil {
+		return "", err
+	}
+	if apr.NArg() != 2 {
+		return "", fmt.Errorf("error: dolt_reword takes a commit and a commit message")
+	}
+	msg := strings.TrimSpace(apr.Arg(1))
+	if msg == "" {
+		return "", ErrMissingCommitMessage
+	}
+	var authorName, authorEmail string
+	if authorStr, ok := apr.GetValue(cli.AuthorParam); ok {
+		authorName, authorEmail, err = cli.ParseAuthor(authorStr)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	dbName := ctx.GetCurrentDatabase()
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+		return "", err
+	}
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	dbData, ok := dSess.GetDbData(ctx, dbName)
+	if !ok {
+		return "", fmt.Errorf("Could not load database %s", dbName)
+	}
+	ddb := dbData.Ddb
+	headRef, err := dSess.CWBHeadRef(ctx, dbName)
+	if err != nil {
+		return "", err
+	}
+	if err := checkProtectedBranch(ctx, dbName, headRef.GetPath()); err != nil {
+		return "", err
+	}
+
+	ws, err := dSess.WorkingSet(ctx, dbName)
+	if err != nil {
+		return "", err
+	}
+	if ws.MergeActive() {
+		return "", fmt.Errorf("error: cannot reword a commit while a merge is in progress")
+	}
+
+	cs, err := doltdb.NewCommitSpec(apr.Arg(0))
+	if err != nil {
+		return "", err
+	}
+	target, err := ddb.Resolve(ctx, cs, headRef)
+	if err != nil {
+		return "", err
+	}
+	headCommit, err := dSess.GetHeadCommit(ctx, dbName)
+	if err != nil {
+		return "", err
+	}
+	descendants, err := firstParentDescendants(ctx, headCommit, target)
+	if err != nil {
+		return "", err
+	}
+
+	if !apr.Contains(cli.ForceFlag) {
+		if err := checkRewordTargetUnshared(ctx, ddb, headRef, target); err != nil {
+			return "", err
+		}
+	}
+
+	meta, err := target.GetCommitMeta(ctx)
+	if err != nil {
+		return "", err
+	}
+	newMeta := *meta
+	newMeta.Description = msg
+	if authorName != "" {
+		newMeta.Name, newMeta.Email = authorName, authorEmail
+	}
+	parents, err := ddb.ResolveAllParents(ctx, target)
+	if err != nil {
+		return "", err
+	}
+	rewritten, err := rewriteCommit(ctx, ddb, target, parents, &newMeta)
+	if err != nil {
+		return "", err
+	}
+
+	for _, cm := range descendants {
+		parents, err := ddb.ResolveAllParents(ctx, cm)
+		if err != nil {
+			return "", err
+		}
+		parents[0] = rewritten
+		meta, err := cm.GetCommitMeta(ctx)
+		if err != nil {
+			return "", err
+		}
+		rewritten, err = rewriteCommit(ctx, ddb, cm, parents, meta)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if err := ddb.SetHeadToCommit(ctx, headRef, rewritten); err != nil {
+		return "", err
+	}
+	// The trees are unchanged, so the working set stays as it is, but the session has to pick up the new head commit
+	if err := dSess.SetWorkingSet(ctx, dbName, ws); err != nil {
+		return "", err
+	}
+
+	h, err := rewritten.HashOf()
+	if err != nil {
+		return "", err
+	}
+	return h.String(), nil
+}
+
+// firstParentDescendants returns the commits from |target| to |headCommit| along the first parents of |headCommit|,
+// oldest first, not including |target|. It returns an error if |target| isn't on that history.
+func firstParentDescendants(ctx *sql.Context, headCommit, target *doltdb.Commit) ([]*doltdb.Commit, error) {
+	targetHash, err := target.HashOf()
+	if err != nil {
+		return nil, err
+	}
+
+	var descendants []*doltdb.Commit
+	cm := headCommit
+	for {
+		h, err := cm.HashOf()
+		if err != nil {
+			return nil, err
+		}
+		if h == targetHash {
+			break
+		}
+		if cm.NumParents() == 0 {
+			return nil, fmt.Errorf("error: commit %s is not on the first-parent history of the current branch", targetHash.String())
+		}
+		descendants = append(descendants, cm)
+		cm, err = cm.GetParent(ctx, 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for i, j := 0, len(descendants)-1; i < j; i, j = i+1, j-1 {
+		descendants[i], descendants[j] = descendants[j], descendants[i]
+	}
+	return descendants, nil
+}
+
+// checkRewordTargetUnshared returns ErrRewordSharedCommit if |target| is reachable from a branch other than
+// |headRef|, since rewording it would leave that branch with the original history.
+func checkRewordTargetUnshared(ctx *sql.Context, ddb *doltdb.DoltDB, headRef ref.DoltRef, target *doltdb.Commit) error {
+	targetHash, err := target.HashOf()
+	if err != nil {
+		return err
+	}
+	branches, err := ddb.GetBranches(ctx)
+	if err != nil {
+		return err
+	}
+	for _, br := range branches {
+		if ref.Equals(br, headRef) {
+			continue
+		}
+		brCommit, err := ddb.ResolveCommitRef(ctx, br)
+		if err != nil {
+			return err
+		}
+		ancestor, err := doltdb.GetCommitAncestor(ctx, target, brCommit)
+		if errors.Is(err, doltdb.ErrNoCommonAncestor) {
+			continue
+		} else if err != nil {
+			return err
+		}
+		ancestorHash, err := ancestor.HashOf()
+		if err != nil {
+			return err
+		}
+		if ancestorHash == targetHash {
+			return ErrRewordSharedCommit.New(targetHash.String(), br.GetPath())
+		}
+	}
+	return nil
+}
+
+// rewriteCommit writes a copy of |cm|, with its tree, but with the |parents| and |meta| given, which isn't referenced
+// by any branch.
+func rewriteCommit(ctx *sql.Context, ddb *doltdb.DoltDB, cm *doltdb.Commit, parents []*doltdb.Commit, meta *datas.CommitMeta) (*doltdb.Commit, error) {
+	root, err := cm.GetRootValue(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pendingCommit, err := ddb.NewPendingCommit(ctx, doltdb.Roots{Staged: root}, parents, meta)
+	if err != nil {
+		return nil, err
+	}
+	return ddb.CommitDangling(ctx, pendingCommit.Val, pendingCommit.CommitOptions)
+}
@@ -0,0 +1,180 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	goerrors "gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/branch_control"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// ErrSquashMergeCommit is returned by dolt_squash when one of the commits to squash is a merge commit.
+var ErrSquashMergeCommit = goerrors.NewKind("cannot squash merge commit %s")
+
+// doltSquash is the stored procedure which squashes the last commits of the current branch into one. It's called as
+// dolt_squash(n, message), and returns the hash of the new commit.
+func doltSquash(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	commitHash, err := doDoltSquash(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	return rowToIter(commitHash), nil
+}
+
+// doDoltSquash replaces the last |n| commits of the current branch, given by |args| along with the message, with a
+// single commit of the tree of HEAD, whose parent is the commit before them. The staged and working changes are left
+// as they are.
+func doDoltSquash(ctx *sql.Context, args []string) (commitHash string, err error) {
+	if len(args) != 2 {
+		return "", fmt.Errorf("error: dolt_squash takes the number of commits to squash and a commit message")
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 1 {
+		return "", fmt.Errorf("error: invalid number of commits to squash '%s'", args[0])
+	}
+	msg := args[1]
+	if msg == "" {
+		return "", ErrMissingCommitMessage
+	}
+
+	dbName := ctx.GetCurrentDatabase()
+	if err := branch_control.CheckAccess(ctx, branch_control.Permissions_Write); err != nil {
+		return "", err
+	}
+
+	dSess := dsess.DSessFromSess(ctx.Session)
+	dbData, ok := dSess.GetDbData(ctx, dbName)
+	if !ok {
+		return "", fmt.Errorf("Could not load database %s", dbName)
+	}
+	headRef, err := dSess.CWBHeadRef(ctx, dbName)
+	if err != nil {
+		return "", err
+	}
+	if err := checkProtectedBranch(ctx, dbName, headRef.GetPath()); err != nil {
+		return "", err
+	}
+
+	ws, err := dSess.WorkingSet(ctx, dbName)
+	if err != nil {
+		return "", err
+	}
+	if ws.MergeActive() {
+		return "", fmt.Errorf("error: cannot squash commits while a merge is in progress")
+	}
+	roots, ok := dSess.GetRoots(ctx, dbName)
+	if !ok {
+		return "", fmt.Errorf("Could not load database %s", dbName)
+	}
+
+	headCommit, err := dSess.GetHeadCommit(ctx, dbName)
+	if err != nil {
+		return "", err
+	}
+	base, err := squashBase(ctx, headCommit, n)
+	if err != nil {
+		return "", err
+	}
+	baseRoot, err := base.GetRootValue(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	name, email, err := CommitAuthorFromSession(ctx)
+	if err != nil {
+		return "", err
+	}
+	metadata, err := addCommitSessionMetadata(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	err = validateCommit(CommitToValidate{
+		Database: dbName,
+		Branch:   headRef.GetPath(),
+		Message:  msg,
+		Metadata: metadata,
+		Name:     name,
+		Email:    email,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// The new commit's parent is the branch head when it's written, so move the branch back to the commit before those
+	// squashed first, as dolt_commit --amend does, and restore it if the commit fails
+	if err = dbData.Ddb.SetHeadToCommit(ctx, headRef, base); err != nil {
+		return "", err
+	}
+	defer func() {
+		if err != nil {
+			dbData.Ddb.SetHeadToCommit(ctx, headRef, headCommit)
+		}
+	}()
+
+	// Commit the tree of HEAD on top of |base|, leaving the staged and working roots as they were
+	pendingCommit, err := dSess.NewPendingCommit(ctx, dbName, doltdb.Roots{Head: baseRoot, Staged: roots.Head, Working: roots.Working}, actions.CommitStagedProps{
+		Message:    msg,
+		Date:       ctx.QueryTime(),
+		AllowEmpty: true,
+		Name:       name,
+		Email:      email,
+		Metadata:   metadata,
+	})
+	if err != nil {
+		return "", err
+	}
+	pendingCommit.NextStaged = roots.Staged
+
+	newCommit, err := dSess.DoltCommit(ctx, dbName, dSess.GetTransaction(), pendingCommit)
+	if err != nil {
+		return "", err
+	}
+	h, err := newCommit.HashOf()
+	if err != nil {
+		return "", err
+	}
+	return h.String(), nil
+}
+
+// squashBase returns the first parent of the |n|th commit back from |headCommit|, which is the parent of the commit
+// squashing the |n| commits. It returns an error if one of them is a merge commit or the initial commit.
+func squashBase(ctx *sql.Context, headCommit *doltdb.Commit, n int) (*doltdb.Commit, error) {
+	cm := headCommit
+	for i := 0; i < n; i++ {
+		if cm.NumParents() > 1 {
+			h, err := cm.HashOf()
+			if err != nil {
+				return nil, err
+			}
+			return nil, ErrSquashMergeCommit.New(h.String())
+		}
+		if cm.NumParents() == 0 {
+			return nil, fmt.Errorf("error: cannot squash %d commits, the branch only has %d commits after its initial commit", n, i)
+		}
+		parent, err := cm.GetParent(ctx, 0)
+		if err != nil {
+			return nil, err
+		}
+		cm = parent
+	}
+	return cm, nil
+}
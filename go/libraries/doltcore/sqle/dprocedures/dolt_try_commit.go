@@ -0,0 +1,66 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+const (
+	tryCommitCommitted = "committed"
+	tryCommitSkipped   = "skipped"
+)
+
+// tryCommitSchema is the schema of the result of dolt_try_commit. The status is committed, skipped, or the reason the
+// commit failed, and the message is the error for a failed commit.
+var tryCommitSchema = sql.Schema{
+	&sql.Column{Name: "hash", Type: types.LongText, Nullable: true},
+	&sql.Column{Name: "status", Type: types.LongText, Nullable: false},
+	&sql.Column{Name: "message", Type: types.LongText, Nullable: false},
+}
+
+// expectedCommitFailures are the reasons, as given by commitFailureReason, for which dolt_try_commit reports a failed
+// commit in its result rather than returning an error.
+var expectedCommitFailures = map[string]bool{
+	"nothing_to_commit":     true,
+	"empty_message":         true,
+	"conflicts":             true,
+	"constraint_violations": true,
+	"rate_limited":          true,
+	"validation_failed":     true,
+	"subject_too_long":      true,
+	"too_many_tables":       true,
+	"pre_commit_rejected":   true,
+}
+
+// doltTryCommit is a version of dolt_commit which reports expected failures, such as there being nothing to commit,
+// as a result row instead of an error. Some drivers abort the whole transaction when a procedure returns an error,
+// which this avoids for scripts that commit in a loop. Other errors, such as missing permissions or storage failures,
+// are still returned as errors.
+func doltTryCommit(ctx *sql.Context, args ...string) (sql.RowIter, error) {
+	commitHash, skipped, err := doDoltCommit(ctx, args)
+	if err != nil {
+		reason := commitFailureReason(err)
+		if !expectedCommitFailures[reason] {
+			return nil, err
+		}
+		return rowToIter(nil, reason, err.Error()), nil
+	}
+	if skipped {
+		return rowToIter(nil, tryCommitSkipped, ErrNothingToCommit.Error()), nil
+	}
+	return rowToIter(commitHash, tryCommitCommitted, ""), nil
+}
@@ -28,7 +28,10 @@ var DoltProcedures = []sql.ExternalStoredProcedureDetails{
 	{Name: "dolt_clean", Schema: int64Schema("status"), Function: doltClean},
 	{Name: "dolt_clone", Schema: int64Schema("status"), Function: doltClone},
 	{Name: "dolt_commit", Schema: stringSchema("hash"), Function: doltCommit},
+	{Name: "dolt_commit_detailed", Schema: commitDetailedSchema, Function: doltCommitDetailed},
+	{Name: "dolt_commit_explain", Schema: commitExplainSchema, Function: doltCommitExplain},
 	{Name: "dolt_commit_hash_out", Schema: stringSchema("hash"), Function: doltCommitHashOut},
+	{Name: "dolt_commit_hash_skipped_out", Schema: commitHashSkippedOutSchema, Function: doltCommitHashSkippedOut},
 	{Name: "dolt_conflicts_resolve", Schema: int64Schema("status"), Function: doltConflictsResolve},
 	{Name: "dolt_fetch", Schema: int64Schema("success"), Function: doltFetch},
 
@@ -36,12 +39,16 @@ var DoltProcedures = []sql.ExternalStoredProcedureDetails{
 	{Name: "dolt_gc", Schema: int64Schema("success"), Function: doltGC},
 
 	{Name: "dolt_merge", Schema: int64Schema("fast_forward", "conflicts"), Function: doltMerge},
+	{Name: "dolt_preview_commit", Schema: stringSchema("hash"), Function: doltPreviewCommit},
 	{Name: "dolt_pull", Schema: int64Schema("fast_forward", "conflicts"), Function: doltPull},
 	{Name: "dolt_push", Schema: int64Schema("success"), Function: doltPush},
 	{Name: "dolt_remote", Schema: int64Schema("status"), Function: doltRemote},
 	{Name: "dolt_reset", Schema: int64Schema("status"), Function: doltReset},
 	{Name: "dolt_revert", Schema: int64Schema("status"), Function: doltRevert},
+	{Name: "dolt_reword", Schema: stringSchema("hash"), Function: doltReword},
+	{Name: "dolt_squash", Schema: stringSchema("hash"), Function: doltSquash},
 	{Name: "dolt_tag", Schema: int64Schema("status"), Function: doltTag},
+	{Name: "dolt_try_commit", Schema: tryCommitSchema, Function: doltTryCommit},
 	{Name: "dolt_verify_constraints", Schema: int64Schema("violations"), Function: doltVerifyConstraints},
 
 	// Dolt stored procedure aliases
@@ -0,0 +1,125 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dprocedures
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/vitess/go/sqltypes"
+	goerrors "gopkg.in/src-d/go-errors.v1"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/diff"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+)
+
+// ErrPreCommitProcedureRejected is returned by dolt_commit when the procedure configured with
+// @@<db>_pre_commit_procedure raises an error, rejecting the commit.
+var ErrPreCommitProcedureRejected = goerrors.NewKind("commit rejected by pre-commit procedure %s: %s")
+
+// QueryRunner runs |query| in the session of |ctx|, and returns its rows.
+type QueryRunner func(ctx *sql.Context, query string) (sql.RowIter, error)
+
+// preCommitQueryRunner is used to call the procedure configured with @@<db>_pre_commit_procedure. It's nil unless set
+// by the SQL engine with SetQueryRunner.
+var preCommitQueryRunner QueryRunner
+
+// SetQueryRunner sets the function dolt_commit uses to call the procedure configured with @@<db>_pre_commit_procedure.
+// The SQL engine sets it to run queries with itself when it's created.
+func SetQueryRunner(runner QueryRunner) {
+	preCommitQueryRunner = runner
+}
+
+// inPreCommitProcedureKey is the context key marking a query run by the pre-commit procedure.
+type inPreCommitProcedureKey struct{}
+
+// runPreCommitProcedure calls the stored procedure configured with @@<db>_pre_commit_procedure for the database named,
+// if any, before committing |roots| to |branch|. The procedure is called with the name of the branch and a
+// comma-separated list of the tables changed by the commit, and can inspect the staged changes, e.g. with
+// dolt_diff('HEAD', 'STAGED', <table>). The commit is rejected if it raises an error, e.g. with SIGNAL.
+func runPreCommitProcedure(ctx *sql.Context, dbName, branch string, roots doltdb.Roots) error {
+	procName := dsess.GetPreCommitProcedure(dbName)
+	if procName == "" {
+		return nil
+	}
+	if ctx.Value(inPreCommitProcedureKey{}) != nil {
+		return fmt.Errorf("error: dolt_commit cannot be called by the pre-commit procedure %s", procName)
+	}
+	if preCommitQueryRunner == nil {
+		return fmt.Errorf("error: the pre-commit procedure %s, set by @@%s, can't be run here", procName, dsess.PreCommitProcedureKey(dbName))
+	}
+	if strings.Contains(procName, "`") {
+		return fmt.Errorf("error: invalid pre-commit procedure name %s in @@%s", procName, dsess.PreCommitProcedureKey(dbName))
+	}
+
+	tables, err := stagedTableNames(ctx, roots)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("CALL `%s`.`%s`(%s, %s)", dbName, procName, quoteSQLString(branch), quoteSQLString(strings.Join(tables, ",")))
+	procCtx := sql.NewContext(context.WithValue(ctx, inPreCommitProcedureKey{}, true), sql.WithSession(ctx.Session))
+	err = drainQuery(procCtx, query)
+	if err != nil {
+		return ErrPreCommitProcedureRejected.New(procName, err.Error())
+	}
+	return nil
+}
+
+// drainQuery runs |query| with the preCommitQueryRunner, reading and discarding its rows.
+func drainQuery(ctx *sql.Context, query string) error {
+	iter, err := preCommitQueryRunner(ctx, query)
+	if err != nil {
+		return err
+	}
+	for {
+		_, err = iter.Next(ctx)
+		if err == io.EOF {
+			return iter.Close(ctx)
+		}
+		if err != nil {
+			iter.Close(ctx)
+			return err
+		}
+	}
+}
+
+// stagedTableNames returns the sorted names of the tables staged in |roots|, i.e. changed between its HEAD and staged
+// roots. A renamed table is listed by its new name.
+func stagedTableNames(ctx *sql.Context, roots doltdb.Roots) ([]string, error) {
+	deltas, err := diff.GetTableDeltas(ctx, roots.Head, roots.Staged)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(deltas))
+	for i, td := range deltas {
+		names[i] = td.CurName()
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// quoteSQLString returns |s| as a quoted and escaped SQL string literal.
+func quoteSQLString(s string) string {
+	buf := &bytes.Buffer{}
+	sqltypes.NewVarChar(s).EncodeSQL(buf)
+	return buf.String()
+}
@@ -122,6 +122,30 @@ func (d *DoltSession) Provider() DoltDatabaseProvider {
 	return d.provider
 }
 
+// AppendReflogEntry records |entry| in the reflog of the database named. Revision databases share the reflog of their
+// base database.
+func (d *DoltSession) AppendReflogEntry(dbName string, entry env.ReflogEntry) error {
+	fs, err := d.provider.FileSystemForDatabase(baseDatabaseName(dbName))
+	if err != nil {
+		return err
+	}
+	return env.AppendReflogEntry(fs, entry)
+}
+
+// GetReflog returns the reflog entries of the database named, oldest first.
+func (d *DoltSession) GetReflog(dbName string) ([]env.ReflogEntry, error) {
+	fs, err := d.provider.FileSystemForDatabase(baseDatabaseName(dbName))
+	if err != nil {
+		return nil, err
+	}
+	return env.LoadReflog(fs)
+}
+
+// baseDatabaseName returns the name of the database without any revision qualifier.
+func baseDatabaseName(dbName string) string {
+	return strings.SplitN(dbName, DbRevisionDelimiter, 2)[0]
+}
+
 // EnableBatchedMode enables batched mode for this session. This is only safe to do during initialization.
 // Sessions operating in batched mode don't flush any edit buffers except when told to do so explicitly, or when a
 // transaction commits. Disable @@autocommit to prevent edit buffers from being flushed prematurely in this mode.
@@ -448,9 +472,10 @@ func (d *DoltSession) CommitTransaction(ctx *sql.Context, tx sql.Transaction) er
 	}
 }
 
-// isDirty returns whether the working set for the database named is dirty
+// IsDirty returns whether the working set for the database named has changes not yet committed by the current
+// transaction
 // TODO: remove the dbname parameter, return a global dirty bit
-func (d *DoltSession) isDirty(ctx *sql.Context, dbName string) (bool, error) {
+func (d *DoltSession) IsDirty(ctx *sql.Context, dbName string) (bool, error) {
 	dbState, _, err := d.LookupDbState(ctx, dbName)
 	if err != nil {
 		return false, err
@@ -462,7 +487,7 @@ func (d *DoltSession) isDirty(ctx *sql.Context, dbName string) (bool, error) {
 // CommitWorkingSet commits the working set for the transaction given, without creating a new dolt commit.
 // Clients should typically use CommitTransaction, which performs additional checks, instead of this method.
 func (d *DoltSession) CommitWorkingSet(ctx *sql.Context, dbName string, tx sql.Transaction) error {
-	dirty, err := d.isDirty(ctx, dbName)
+	dirty, err := d.IsDirty(ctx, dbName)
 	if err != nil {
 		return err
 	}
@@ -564,6 +589,13 @@ func (d *DoltSession) PendingCommitAllStaged(ctx *sql.Context, dbName string, pr
 // merge parent from an in progress merge as appropriate. The session working set is not updated with these new roots,
 // but they are set in the returned |doltdb.PendingCommit|. If there are no changes staged, this method returns nil.
 func (d *DoltSession) NewPendingCommit(ctx *sql.Context, dbName string, roots doltdb.Roots, props actions.CommitStagedProps) (*doltdb.PendingCommit, error) {
+	return d.NewPendingCommitWithParents(ctx, dbName, roots, nil, props)
+}
+
+// NewPendingCommitWithParents is like NewPendingCommit, but uses exactly the |parents| given as the parents of the new
+// commit instead of deriving them from the HEAD commit and any in progress merge. The current branch head is not added
+// unless it's among |parents|. If |parents| is empty, this behaves exactly like NewPendingCommit.
+func (d *DoltSession) NewPendingCommitWithParents(ctx *sql.Context, dbName string, roots doltdb.Roots, parents []*doltdb.Commit, props actions.CommitStagedProps) (*doltdb.PendingCommit, error) {
 	sessionState, _, err := d.LookupDbState(ctx, dbName)
 	if err != nil {
 		return nil, err
@@ -604,6 +636,10 @@ func (d *DoltSession) NewPendingCommit(ctx *sql.Context, dbName string, roots do
 		roots.Head = newRoots.Head
 	}
 
+	if len(parents) > 0 {
+		mergeParentCommits = parents
+	}
+
 	pendingCommit, err := actions.GetCommitStaged(ctx, roots, sessionState.WorkingSet, mergeParentCommits, sessionState.dbData.Ddb, props)
 	if err != nil {
 		if props.Amend {
@@ -617,6 +653,10 @@ func (d *DoltSession) NewPendingCommit(ctx *sql.Context, dbName string, roots do
 		}
 	}
 
+	if pendingCommit != nil && len(parents) > 0 {
+		pendingCommit.CommitOptions.ExactParents = true
+	}
+
 	return pendingCommit, nil
 }
 
@@ -628,7 +668,7 @@ func (d *DoltSession) Rollback(ctx *sql.Context, tx sql.Transaction) error {
 		return nil
 	}
 
-	dirty, err := d.isDirty(ctx, dbName)
+	dirty, err := d.IsDirty(ctx, dbName)
 	if err != nil {
 		return err
 	}
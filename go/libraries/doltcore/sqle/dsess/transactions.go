@@ -220,6 +220,9 @@ func doltCommit(ctx *sql.Context,
 	}
 
 	workingSet = workingSet.ClearMerge()
+	if pending.NextStaged != nil {
+		workingSet = workingSet.WithStagedRoot(pending.NextStaged)
+	}
 
 	var rsc doltdb.ReplicationStatusController
 	newCommit, err := tx.dbData.Ddb.CommitWithWorkingSet(ctx, headRef, tx.workingSetRef, &pending, workingSet, currHash, tx.getWorkingSetMeta(ctx), &rsc)
@@ -17,6 +17,7 @@ package dsess
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/types"
@@ -29,6 +30,16 @@ const (
 	WorkingKeySuffix       = "_working"
 	StagedKeySuffix        = "_staged"
 	DefaultBranchKeySuffix = "_default_branch"
+
+	CommitRateLimitKeySuffix    = "_commit_rate_limit"
+	CommitRateWindowKeySuffix   = "_commit_rate_window"
+	CommitValidatorsKeySuffix   = "_commit_validators"
+	CommitMaxTablesKeySuffix    = "_commit_max_tables"
+	CommitLogTableKeySuffix     = "_commit_log_table"
+	ProtectedBranchesKeySuffix  = "_protected_branches"
+	PreCommitProcedureKeySuffix = "_pre_commit_procedure"
+
+	AutoGCCommitThresholdKeySuffix = "_auto_gc_commit_threshold"
 )
 
 // General system variables
@@ -51,6 +62,12 @@ const (
 	AwsCredsRegion                = "aws_credentials_region"
 	ShowBranchDatabases           = "dolt_show_branch_databases"
 	DoltLogLevel                  = "dolt_log_level"
+	DoltAuthor                    = "dolt_author"
+	CommitRateLimitAction         = "dolt_commit_rate_limit_action"
+	CommitMaxSubjectLength        = "dolt_commit_max_subject_length"
+	CommitRecordSession           = "dolt_commit_record_session"
+	CommitAllowNoFlush            = "dolt_commit_allow_no_flush"
+	ProtectedBranchesLinear       = "dolt_protected_branches_linear_history"
 
 	DoltClusterRoleVariable         = "dolt_cluster_role"
 	DoltClusterRoleEpochVariable    = "dolt_cluster_role_epoch"
@@ -105,6 +122,70 @@ func DefineSystemVariablesForDB(name string) {
 				Type:              types.NewSystemStringType(DefaultBranchKey(name)),
 				Default:           "",
 			},
+			{
+				Name:              CommitRateLimitKey(name),
+				Scope:             sql.SystemVariableScope_Global,
+				Dynamic:           true,
+				SetVarHintApplies: false,
+				Type:              types.NewSystemIntType(CommitRateLimitKey(name), 0, 9223372036854775807, false),
+				Default:           int64(0),
+			},
+			{
+				Name:              CommitRateWindowKey(name),
+				Scope:             sql.SystemVariableScope_Global,
+				Dynamic:           true,
+				SetVarHintApplies: false,
+				Type:              types.NewSystemIntType(CommitRateWindowKey(name), 1, 9223372036854775807, false),
+				Default:           int64(60),
+			},
+			{
+				Name:              CommitValidatorsKey(name),
+				Scope:             sql.SystemVariableScope_Global,
+				Dynamic:           true,
+				SetVarHintApplies: false,
+				Type:              types.NewSystemStringType(CommitValidatorsKey(name)),
+				Default:           "",
+			},
+			{
+				Name:              CommitMaxTablesKey(name),
+				Scope:             sql.SystemVariableScope_Global,
+				Dynamic:           true,
+				SetVarHintApplies: false,
+				Type:              types.NewSystemIntType(CommitMaxTablesKey(name), 0, 9223372036854775807, false),
+				Default:           int64(0),
+			},
+			{
+				Name:              CommitLogTableKey(name),
+				Scope:             sql.SystemVariableScope_Global,
+				Dynamic:           true,
+				SetVarHintApplies: false,
+				Type:              types.NewSystemStringType(CommitLogTableKey(name)),
+				Default:           "",
+			},
+			{
+				Name:              ProtectedBranchesKey(name),
+				Scope:             sql.SystemVariableScope_Global,
+				Dynamic:           true,
+				SetVarHintApplies: false,
+				Type:              types.NewSystemStringType(ProtectedBranchesKey(name)),
+				Default:           "",
+			},
+			{
+				Name:              PreCommitProcedureKey(name),
+				Scope:             sql.SystemVariableScope_Global,
+				Dynamic:           true,
+				SetVarHintApplies: false,
+				Type:              types.NewSystemStringType(PreCommitProcedureKey(name)),
+				Default:           "",
+			},
+			{
+				Name:              AutoGCCommitThresholdKey(name),
+				Scope:             sql.SystemVariableScope_Global,
+				Dynamic:           true,
+				SetVarHintApplies: false,
+				Type:              types.NewSystemIntType(AutoGCCommitThresholdKey(name), 0, 9223372036854775807, false),
+				Default:           int64(0),
+			},
 		})
 	}
 }
@@ -129,6 +210,168 @@ func DefaultBranchKey(dbName string) string {
 	return dbName + DefaultBranchKeySuffix
 }
 
+func CommitRateLimitKey(dbName string) string {
+	return dbName + CommitRateLimitKeySuffix
+}
+
+func CommitRateWindowKey(dbName string) string {
+	return dbName + CommitRateWindowKeySuffix
+}
+
+// GetCommitRateLimit returns the maximum number of commits allowed on any one branch of the database named within the
+// window returned, as configured with the @@<db>_commit_rate_limit and @@<db>_commit_rate_window (in seconds) system
+// variables. A limit of 0, the default, means commits aren't limited.
+func GetCommitRateLimit(dbName string) (int64, time.Duration) {
+	dbName = baseDatabaseName(dbName)
+
+	_, limit, ok := sql.SystemVariables.GetGlobal(CommitRateLimitKey(dbName))
+	if !ok {
+		return 0, 0
+	}
+	_, window, ok := sql.SystemVariables.GetGlobal(CommitRateWindowKey(dbName))
+	if !ok {
+		return 0, 0
+	}
+
+	limitInt, ok := limit.(int64)
+	if !ok {
+		return 0, 0
+	}
+	windowSecs, ok := window.(int64)
+	if !ok {
+		return 0, 0
+	}
+
+	return limitInt, time.Duration(windowSecs) * time.Second
+}
+
+func CommitValidatorsKey(dbName string) string {
+	return dbName + CommitValidatorsKeySuffix
+}
+
+// GetCommitValidators returns the validators which commits to the database named must pass, as configured with the
+// @@<db>_commit_validators system variable: a comma-separated list of validator names, each optionally followed by
+// =<argument>. The default is the empty string, meaning commits aren't validated.
+func GetCommitValidators(dbName string) string {
+	dbName = baseDatabaseName(dbName)
+
+	_, validators, ok := sql.SystemVariables.GetGlobal(CommitValidatorsKey(dbName))
+	if !ok {
+		return ""
+	}
+	validatorsStr, ok := validators.(string)
+	if !ok {
+		return ""
+	}
+
+	return validatorsStr
+}
+
+func CommitMaxTablesKey(dbName string) string {
+	return dbName + CommitMaxTablesKeySuffix
+}
+
+// GetCommitMaxTables returns the maximum number of tables a single commit to the database named may change, as
+// configured with the @@<db>_commit_max_tables system variable. A maximum of 0, the default, means there's no limit.
+func GetCommitMaxTables(dbName string) int64 {
+	dbName = baseDatabaseName(dbName)
+
+	_, maxTables, ok := sql.SystemVariables.GetGlobal(CommitMaxTablesKey(dbName))
+	if !ok {
+		return 0
+	}
+	maxInt, ok := maxTables.(int64)
+	if !ok {
+		return 0
+	}
+	return maxInt
+}
+
+func CommitLogTableKey(dbName string) string {
+	return dbName + CommitLogTableKeySuffix
+}
+
+// GetCommitLogTable returns the name of the table dolt_commit appends a row to for each commit to the database named,
+// as configured with the @@<db>_commit_log_table system variable. The default is the empty string, meaning commits
+// aren't logged.
+func GetCommitLogTable(dbName string) string {
+	dbName = baseDatabaseName(dbName)
+
+	_, table, ok := sql.SystemVariables.GetGlobal(CommitLogTableKey(dbName))
+	if !ok {
+		return ""
+	}
+	tableStr, ok := table.(string)
+	if !ok {
+		return ""
+	}
+	return tableStr
+}
+
+func ProtectedBranchesKey(dbName string) string {
+	return dbName + ProtectedBranchesKeySuffix
+}
+
+// GetProtectedBranches returns the branches of the database named which can't be committed to directly, as configured
+// with the @@<db>_protected_branches system variable: a comma-separated list of branch names or glob patterns, e.g.
+// "main,release/*". The default is the empty string, meaning no branches are protected.
+func GetProtectedBranches(dbName string) string {
+	dbName = baseDatabaseName(dbName)
+
+	_, branches, ok := sql.SystemVariables.GetGlobal(ProtectedBranchesKey(dbName))
+	if !ok {
+		return ""
+	}
+	branchesStr, ok := branches.(string)
+	if !ok {
+		return ""
+	}
+	return branchesStr
+}
+
+func PreCommitProcedureKey(dbName string) string {
+	return dbName + PreCommitProcedureKeySuffix
+}
+
+// GetPreCommitProcedure returns the name of the stored procedure dolt_commit calls before each commit to the database
+// named, which can reject the commit by raising an error, as configured with the @@<db>_pre_commit_procedure system
+// variable. The default is the empty string, meaning no procedure is called.
+func GetPreCommitProcedure(dbName string) string {
+	dbName = baseDatabaseName(dbName)
+
+	_, proc, ok := sql.SystemVariables.GetGlobal(PreCommitProcedureKey(dbName))
+	if !ok {
+		return ""
+	}
+	procStr, ok := proc.(string)
+	if !ok {
+		return ""
+	}
+	return procStr
+}
+
+func AutoGCCommitThresholdKey(dbName string) string {
+	return dbName + AutoGCCommitThresholdKeySuffix
+}
+
+// GetAutoGCCommitThreshold returns the number of commits to the database named after which a background garbage
+// collection is scheduled, as configured with the @@<db>_auto_gc_commit_threshold system variable. A threshold of 0,
+// the default, means garbage collection is never scheduled automatically.
+func GetAutoGCCommitThreshold(dbName string) int64 {
+	dbName = baseDatabaseName(dbName)
+
+	_, threshold, ok := sql.SystemVariables.GetGlobal(AutoGCCommitThresholdKey(dbName))
+	if !ok {
+		return 0
+	}
+	thresholdInt, ok := threshold.(int64)
+	if !ok {
+		return 0
+	}
+
+	return thresholdInt
+}
+
 func IsHeadKey(key string) (bool, string) {
 	if strings.HasSuffix(key, HeadKeySuffix) {
 		return true, key[:len(key)-len(HeadKeySuffix)]
@@ -185,6 +428,37 @@ func IgnoreReplicationErrors() bool {
 	return skip == SysVarTrue
 }
 
+// RecordCommitSession returns true if the dolt_commit_record_session system variable is set to true, which means that
+// the id and client address of the session which made each commit are recorded in its metadata.
+func RecordCommitSession() bool {
+	_, record, ok := sql.SystemVariables.GetGlobal(CommitRecordSession)
+	if !ok {
+		return false
+	}
+	return record == SysVarTrue
+}
+
+// AllowCommitNoFlush returns true if the dolt_commit_allow_no_flush system variable is set to true, which means that
+// dolt_commit --no-flush may skip syncing the commit to disk.
+func AllowCommitNoFlush() bool {
+	_, allow, ok := sql.SystemVariables.GetGlobal(CommitAllowNoFlush)
+	if !ok {
+		return false
+	}
+	return allow == SysVarTrue
+}
+
+// ProtectedBranchesRequireLinearHistory returns true if the dolt_protected_branches_linear_history system variable is
+// set to true, which means that merge commits can't be made on the branches protected by @@<db>_protected_branches,
+// even by their admins.
+func ProtectedBranchesRequireLinearHistory() bool {
+	_, linear, ok := sql.SystemVariables.GetGlobal(ProtectedBranchesLinear)
+	if !ok {
+		return false
+	}
+	return linear == SysVarTrue
+}
+
 // WarnReplicationError logs a warning for the replication error given
 func WarnReplicationError(ctx *sql.Context, err error) {
 	ctx.GetLogger().Warn(fmt.Errorf("replication failure: %w", err))
@@ -24,6 +24,7 @@ import (
 	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
 	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions/commitwalk"
 	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+	"github.com/dolthub/dolt/go/store/datas"
 	"github.com/dolthub/dolt/go/store/hash"
 	"github.com/dolthub/dolt/go/store/prolly"
 )
@@ -84,6 +85,7 @@ func (dt *LogTable) Schema() sql.Schema {
 		{Name: "email", Type: types.Text, Source: doltdb.LogTableName, PrimaryKey: false},
 		{Name: "date", Type: types.Datetime, Source: doltdb.LogTableName, PrimaryKey: false},
 		{Name: "message", Type: types.Text, Source: doltdb.LogTableName, PrimaryKey: false},
+		{Name: "metadata", Type: types.JSON, Source: doltdb.LogTableName, PrimaryKey: false, Nullable: true},
 	}
 }
 
@@ -101,7 +103,11 @@ func (dt *LogTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
 func (dt *LogTable) PartitionRows(ctx *sql.Context, p sql.Partition) (sql.RowIter, error) {
 	switch p := p.(type) {
 	case *doltdb.CommitPart:
-		return sql.RowsToRowIter(sql.NewRow(p.Hash().String(), p.Meta().Name, p.Meta().Email, p.Meta().Time(), p.Meta().Description)), nil
+		md, err := commitMetadataValue(p.Meta())
+		if err != nil {
+			return nil, err
+		}
+		return sql.RowsToRowIter(sql.NewRow(p.Hash().String(), p.Meta().Name, p.Meta().Email, p.Meta().Time(), p.Meta().Description, md)), nil
 	default:
 		return NewLogItr(ctx, dt.ddb, dt.head)
 	}
@@ -227,7 +233,21 @@ func (itr *LogItr) Next(ctx *sql.Context) (sql.Row, error) {
 		return nil, err
 	}
 
-	return sql.NewRow(h.String(), meta.Name, meta.Email, meta.Time(), meta.Description), nil
+	md, err := commitMetadataValue(meta)
+	if err != nil {
+		return nil, err
+	}
+
+	return sql.NewRow(h.String(), meta.Name, meta.Email, meta.Time(), meta.Description, md), nil
+}
+
+// commitMetadataValue returns the user-defined metadata of the commit as a JSON object, or nil if it has none.
+func commitMetadataValue(meta *datas.CommitMeta) (interface{}, error) {
+	if len(meta.Metadata) == 0 {
+		return nil, nil
+	}
+	md, _, err := types.JSON.Convert(meta.Metadata)
+	return md, err
 }
 
 // Close closes the iterator.
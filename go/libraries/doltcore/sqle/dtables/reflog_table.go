@@ -0,0 +1,111 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dtables
+
+import (
+	"io"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/index"
+)
+
+var _ sql.Table = (*ReflogTable)(nil)
+
+// ReflogTable is a sql.Table implementation that implements a system table which shows the sequence of ref updates
+// recorded in the reflog of a database
+type ReflogTable struct {
+	dbName string
+}
+
+// NewReflogTable creates a ReflogTable
+func NewReflogTable(dbName string) sql.Table {
+	return &ReflogTable{dbName: dbName}
+}
+
+// Name is a sql.Table interface function which returns the name of the table which is defined by the constant
+// ReflogTableName
+func (rt *ReflogTable) Name() string {
+	return doltdb.ReflogTableName
+}
+
+// String is a sql.Table interface function which returns the name of the table which is defined by the constant
+// ReflogTableName
+func (rt *ReflogTable) String() string {
+	return doltdb.ReflogTableName
+}
+
+// Schema is a sql.Table interface function that gets the sql.Schema of the reflog system table.
+func (rt *ReflogTable) Schema() sql.Schema {
+	return []*sql.Column{
+		{Name: "ref", Type: types.Text, Source: doltdb.ReflogTableName, PrimaryKey: false},
+		{Name: "operation", Type: types.Text, Source: doltdb.ReflogTableName, PrimaryKey: false},
+		{Name: "from_hash", Type: types.Text, Source: doltdb.ReflogTableName, PrimaryKey: false},
+		{Name: "to_hash", Type: types.Text, Source: doltdb.ReflogTableName, PrimaryKey: false},
+		{Name: "committer", Type: types.Text, Source: doltdb.ReflogTableName, PrimaryKey: false},
+		{Name: "email", Type: types.Text, Source: doltdb.ReflogTableName, PrimaryKey: false},
+		{Name: "date", Type: types.Datetime, Source: doltdb.ReflogTableName, PrimaryKey: false},
+	}
+}
+
+// Collation implements the sql.Table interface.
+func (rt *ReflogTable) Collation() sql.CollationID {
+	return sql.Collation_Default
+}
+
+// Partitions is a sql.Table interface function that returns a partition of the data. Currently, the data is unpartitioned.
+func (rt *ReflogTable) Partitions(*sql.Context) (sql.PartitionIter, error) {
+	return index.SinglePartitionIterFromNomsMap(nil), nil
+}
+
+// PartitionRows is a sql.Table interface function that gets a row iterator for a partition
+func (rt *ReflogTable) PartitionRows(ctx *sql.Context, _ sql.Partition) (sql.RowIter, error) {
+	sess := dsess.DSessFromSess(ctx.Session)
+	entries, err := sess.GetReflog(rt.dbName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReflogItr{entries: entries, idx: len(entries) - 1}, nil
+}
+
+// ReflogItr is a sql.RowItr implementation which iterates over each reflog entry, most recent first.
+type ReflogItr struct {
+	entries []env.ReflogEntry
+	idx     int
+}
+
+// Next retrieves the next row. It will return io.EOF if it's the last row.
+func (itr *ReflogItr) Next(*sql.Context) (sql.Row, error) {
+	if itr.idx < 0 {
+		return nil, io.EOF
+	}
+
+	defer func() {
+		itr.idx--
+	}()
+
+	e := itr.entries[itr.idx]
+	return sql.NewRow(e.Ref, e.Operation, e.FromHash, e.ToHash, e.Name, e.Email, e.Timestamp), nil
+}
+
+// Close closes the iterator.
+func (itr *ReflogItr) Close(*sql.Context) error {
+	return nil
+}
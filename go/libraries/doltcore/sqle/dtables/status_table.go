@@ -48,6 +48,8 @@ func (s StatusTable) Schema() sql.Schema {
 		{Name: "table_name", Type: types.Text, Source: doltdb.StatusTableName, PrimaryKey: true, Nullable: false},
 		{Name: "staged", Type: types.Boolean, Source: doltdb.StatusTableName, PrimaryKey: false, Nullable: false},
 		{Name: "status", Type: types.Text, Source: doltdb.StatusTableName, PrimaryKey: false, Nullable: false},
+		{Name: "from_table", Type: types.Text, Source: doltdb.StatusTableName, PrimaryKey: false, Nullable: false},
+		{Name: "to_table", Type: types.Text, Source: doltdb.StatusTableName, PrimaryKey: false, Nullable: false},
 	}
 }
 
@@ -78,10 +80,14 @@ type StatusItr struct {
 	rows []statusTableRow
 }
 
+// statusTableRow is a single row of the status table. A renamed table is a single row with the names it was renamed
+// from and to, while every other row has the same fromTable and toTable.
 type statusTableRow struct {
 	tableName string
 	isStaged  bool
 	status    string
+	fromTable string
+	toTable   string
 }
 
 func newStatusItr(ctx *sql.Context, st *StatusTable) (*StatusItr, error) {
@@ -103,6 +109,8 @@ func newStatusItr(ctx *sql.Context, st *StatusTable) (*StatusItr, error) {
 			tableName: tableName(td),
 			isStaged:  true,
 			status:    statusString(td),
+			fromTable: fromTableName(td),
+			toTable:   td.CurName(),
 		})
 	}
 	for _, td := range unstagedTables {
@@ -110,6 +118,8 @@ func newStatusItr(ctx *sql.Context, st *StatusTable) (*StatusItr, error) {
 			tableName: tableName(td),
 			isStaged:  false,
 			status:    statusString(td),
+			fromTable: fromTableName(td),
+			toTable:   td.CurName(),
 		})
 	}
 
@@ -120,6 +130,8 @@ func newStatusItr(ctx *sql.Context, st *StatusTable) (*StatusItr, error) {
 				tableName: tbl,
 				isStaged:  false,
 				status:    "schema conflict",
+				fromTable: tbl,
+				toTable:   tbl,
 			})
 		}
 	}
@@ -132,6 +144,8 @@ func newStatusItr(ctx *sql.Context, st *StatusTable) (*StatusItr, error) {
 		rows = append(rows, statusTableRow{
 			tableName: tbl,
 			status:    mergeConflictStatus,
+			fromTable: tbl,
+			toTable:   tbl,
 		})
 	}
 
@@ -146,6 +160,14 @@ func tableName(td diff.TableDelta) string {
 	}
 }
 
+// fromTableName returns the name |td|'s table had before the change, which is its current name unless it was renamed.
+func fromTableName(td diff.TableDelta) string {
+	if td.IsRename() {
+		return td.FromName
+	}
+	return td.CurName()
+}
+
 func statusString(td diff.TableDelta) string {
 	if td.IsAdd() {
 		return "new table"
@@ -168,7 +190,7 @@ func (itr *StatusItr) Next(*sql.Context) (sql.Row, error) {
 	}
 	row := itr.rows[0]
 	itr.rows = itr.rows[1:]
-	return sql.NewRow(row.tableName, row.isStaged, row.status), nil
+	return sql.NewRow(row.tableName, row.isStaged, row.status, row.fromTable, row.toTable), nil
 }
 
 // Close closes the iterator.
@@ -577,7 +577,7 @@ var DoltRevisionDbScripts = []queries.ScriptTest{
 			},
 			{
 				Query:    "select * from dolt_status",
-				Expected: []sql.Row{{"t01", false, "modified"}},
+				Expected: []sql.Row{{"t01", false, "modified", "t01", "t01"}},
 			},
 			{
 				Query:    "call dolt_checkout('t01')",
@@ -3618,6 +3618,28 @@ var DoltCommitTests = []queries.ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "CALL DOLT_COMMIT_HASH_SKIPPED_OUT() reports whether the commit was skipped",
+		SetUpScript: []string{
+			"CREATE TABLE t (pk int primary key);",
+			"CALL DOLT_ADD('t');",
+			"CALL DOLT_COMMIT_HASH_SKIPPED_OUT(@hash, @skipped, '-m', 'add table t');",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query:    "SELECT IF(@skipped, 'skipped', 'committed'), IF(@hash = (SELECT commit_hash FROM dolt_log LIMIT 1), 'head', 'other');",
+				Expected: []sql.Row{{"committed", "head"}},
+			},
+			{
+				Query:            "CALL DOLT_COMMIT_HASH_SKIPPED_OUT(@hash, @skipped, '--skip-empty', '-m', 'nothing to commit');",
+				SkipResultsCheck: true,
+			},
+			{
+				Query:    "SELECT IF(@skipped, 'skipped', 'committed'), @hash;",
+				Expected: []sql.Row{{"skipped", ""}},
+			},
+		},
+	},
 }
 
 var DoltIndexPrefixScripts = []queries.ScriptTest{
@@ -241,7 +241,7 @@ var MergeScripts = []queries.ScriptTest{
 			},
 			{
 				Query:    "SELECT * from dolt_status",
-				Expected: []sql.Row{{"test", true, "modified"}, {"test", false, "conflict"}},
+				Expected: []sql.Row{{"test", true, "modified", "test", "test"}, {"test", false, "conflict", "test", "test"}},
 			},
 			{
 				Query:    "SELECT COUNT(*) FROM dolt_log",
@@ -571,7 +571,7 @@ var MergeScripts = []queries.ScriptTest{
 			},
 			{
 				Query:    "SELECT * from dolt_status",
-				Expected: []sql.Row{{"test", true, "modified"}, {"test", false, "conflict"}},
+				Expected: []sql.Row{{"test", true, "modified", "test", "test"}, {"test", false, "conflict", "test", "test"}},
 			},
 			{
 				Query:    "SELECT COUNT(*) FROM dolt_conflicts",
@@ -2961,7 +2961,7 @@ var SchemaConflictScripts = []queries.ScriptTest{
 			{
 				Query: "select * from dolt_status",
 				Expected: []sql.Row{
-					{"t", false, "schema conflict"},
+					{"t", false, "schema conflict", "t", "t"},
 				},
 			},
 		},
@@ -0,0 +1,105 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cmd "github.com/dolthub/dolt/go/cmd/dolt/commands"
+	"github.com/dolthub/dolt/go/libraries/doltcore/doltdb"
+	"github.com/dolthub/dolt/go/libraries/doltcore/dtestutils"
+	"github.com/dolthub/dolt/go/libraries/doltcore/env/actions"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle"
+	"github.com/dolthub/dolt/go/libraries/doltcore/sqle/dsess"
+	"github.com/dolthub/dolt/go/libraries/doltcore/table/editor"
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+func TestNewPendingCommitWithParentsOmittingHead(t *testing.T) {
+	ctx := context.Background()
+	dEnv := dtestutils.CreateTestEnv()
+	defer dEnv.DoltDB.Close()
+
+	cliCtx, _ := cmd.NewArgFreeCliContext(ctx, dEnv)
+	setup := []testCommand{
+		{cmd.SqlCmd{}, args{"-q", "create table test (pk int not null primary key);"}},
+		{cmd.AddCmd{}, args{"."}},
+		{cmd.CommitCmd{}, args{"-m", "first"}},
+		{cmd.SqlCmd{}, args{"-q", "insert into test values (1);"}},
+		{cmd.CommitCmd{}, args{"-am", "second"}},
+		{cmd.SqlCmd{}, args{"-q", "insert into test values (2);"}},
+		{cmd.AddCmd{}, args{"."}},
+	}
+	for _, c := range setup {
+		exitCode := c.cmd.Exec(ctx, c.cmd.Name(), c.args, dEnv, cliCtx)
+		require.Equal(t, 0, exitCode)
+	}
+
+	headCommit, err := dEnv.HeadCommit(ctx)
+	require.NoError(t, err)
+	firstCommit, err := headCommit.GetParent(ctx, 0)
+	require.NoError(t, err)
+	firstHash, err := firstCommit.HashOf()
+	require.NoError(t, err)
+
+	tmpDir, err := dEnv.TempTableFilesDir()
+	require.NoError(t, err)
+	opts := editor.Options{Deaf: dEnv.DbEaFactory(), Tempdir: tmpDir}
+	db, err := sqle.NewDatabase(ctx, "dolt", dEnv.DbData(), opts)
+	require.NoError(t, err)
+	_, sqlCtx, err := sqle.NewTestEngine(dEnv, ctx, db)
+	require.NoError(t, err)
+
+	dSess := dsess.DSessFromSess(sqlCtx.Session)
+	tx, err := dSess.StartTransaction(sqlCtx, sql.ReadWrite)
+	require.NoError(t, err)
+	sqlCtx.SetTransaction(tx)
+
+	roots, ok := dSess.GetRoots(sqlCtx, "dolt")
+	require.True(t, ok)
+
+	props := actions.CommitStagedProps{
+		Message: "graft onto first",
+		Date:    time.Now(),
+		Name:    "billy bob",
+		Email:   "bigbillieb@fake.horse",
+	}
+	pendingCommit, err := dSess.NewPendingCommitWithParents(sqlCtx, "dolt", roots, []*doltdb.Commit{firstCommit}, props)
+	require.NoError(t, err)
+	require.NotNil(t, pendingCommit)
+
+	newCommit, err := dSess.DoltCommit(sqlCtx, "dolt", tx, pendingCommit)
+	require.NoError(t, err)
+
+	parents, err := newCommit.ParentHashes(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []hash.Hash{firstHash}, parents)
+
+	headRef, err := dEnv.RepoStateReader().CWBHeadRef()
+	require.NoError(t, err)
+	newHead, err := dEnv.DoltDB.ResolveCommitRef(ctx, headRef)
+	require.NoError(t, err)
+	newHeadHash, err := newHead.HashOf()
+	require.NoError(t, err)
+	newCommitHash, err := newCommit.HashOf()
+	require.NoError(t, err)
+	assert.Equal(t, newCommitHash, newHeadHash)
+}
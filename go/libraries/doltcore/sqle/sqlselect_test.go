@@ -754,6 +754,7 @@ func BasicSelectTests() []SelectTest {
 					"bigbillieb@fake.horse",
 					time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC).In(LoadedLocalLocation()),
 					"Initialize data repository",
+					nil,
 				},
 			},
 			ExpectedSqlSchema: sql.Schema{
@@ -762,6 +763,7 @@ func BasicSelectTests() []SelectTest {
 				&sql.Column{Name: "email", Type: gmstypes.Text},
 				&sql.Column{Name: "date", Type: gmstypes.Datetime},
 				&sql.Column{Name: "message", Type: gmstypes.Text},
+				&sql.Column{Name: "metadata", Type: gmstypes.JSON},
 			},
 		},
 		{
@@ -158,6 +158,54 @@ func AddDoltSystemVariables() {
 			Type:              types.NewSystemStringType(dsess.AwsCredsRegion),
 			Default:           nil,
 		},
+		{
+			Name:              dsess.CommitRateLimitAction,
+			Scope:             sql.SystemVariableScope_Global,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemEnumType(dsess.CommitRateLimitAction, "reject", "warn"),
+			Default:           "reject",
+		},
+		{
+			Name:              dsess.CommitMaxSubjectLength,
+			Scope:             sql.SystemVariableScope_Both,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemIntType(dsess.CommitMaxSubjectLength, 0, 9223372036854775807, false),
+			Default:           int64(0),
+		},
+		{
+			Name:              dsess.CommitRecordSession,
+			Scope:             sql.SystemVariableScope_Global,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemBoolType(dsess.CommitRecordSession),
+			Default:           int8(0),
+		},
+		{
+			Name:              dsess.CommitAllowNoFlush,
+			Scope:             sql.SystemVariableScope_Global,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemBoolType(dsess.CommitAllowNoFlush),
+			Default:           int8(0),
+		},
+		{
+			Name:              dsess.ProtectedBranchesLinear,
+			Scope:             sql.SystemVariableScope_Global,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemBoolType(dsess.ProtectedBranchesLinear),
+			Default:           int8(0),
+		},
+		{
+			Name:              dsess.DoltAuthor,
+			Scope:             sql.SystemVariableScope_Both,
+			Dynamic:           true,
+			SetVarHintApplies: false,
+			Type:              types.NewSystemStringType(dsess.DoltAuthor),
+			Default:           "",
+		},
 		{
 			Name:              dsess.ShowBranchDatabases,
 			Scope:             sql.SystemVariableScope_Both,
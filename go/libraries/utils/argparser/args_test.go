@@ -201,7 +201,7 @@ func TestParsing(t *testing.T) {
 				parser.SupportOption(opt)
 			}
 
-			exp := &ArgParseResults{test.expectedOpts, test.expectedArgs, parser}
+			exp := &ArgParseResults{options: test.expectedOpts, repeated: map[string][]string{}, Args: test.expectedArgs, parser: parser}
 
 			res, err := parser.Parse(test.args)
 			if test.expectedErr != "" {
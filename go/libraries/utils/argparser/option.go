@@ -25,6 +25,10 @@ const (
 	OptionalFlag OptionType = iota
 	OptionalValue
 	OptionalEmptyValue
+	OptionalRepeatedValue
+	// OptionalAttachedValue is a flag which may be given a value, but only one attached to it, e.g. --name=value. The
+	// argument after it is never taken as its value.
+	OptionalAttachedValue
 )
 
 type ValidationFunc func(string) error
@@ -149,6 +149,15 @@ func (ap *ArgParser) SupportsStringList(name, abbrev, valDesc, desc string) *Arg
 	return ap
 }
 
+// SupportsRepeatedString adds support for a new string argument that may be given more than once, with the description
+// given. Use ArgParseResults.GetValueRepeated to get every value provided. See SupportOpt for details on params.
+func (ap *ArgParser) SupportsRepeatedString(name, abbrev, valDesc, desc string) *ArgParser {
+	opt := &Option{name, abbrev, valDesc, OptionalRepeatedValue, desc, nil, false}
+	ap.SupportOption(opt)
+
+	return ap
+}
+
 // SupportsOptionalString adds support for a new string argument with the description given and optional empty value.
 func (ap *ArgParser) SupportsOptionalString(name, abbrev, valDesc, desc string) *ArgParser {
 	opt := &Option{name, abbrev, valDesc, OptionalEmptyValue, desc, nil, false}
@@ -157,6 +166,15 @@ func (ap *ArgParser) SupportsOptionalString(name, abbrev, valDesc, desc string)
 	return ap
 }
 
+// SupportsFlagWithOptionalValue adds support for a new flag which may also be given a value attached to it, such as
+// --name=value. Given as just --name, its value is the empty string. See SupportOpt for details on params.
+func (ap *ArgParser) SupportsFlagWithOptionalValue(name, abbrev, valDesc, desc string) *ArgParser {
+	opt := &Option{name, abbrev, valDesc, OptionalAttachedValue, desc, nil, false}
+	ap.SupportOption(opt)
+
+	return ap
+}
+
 // SupportsValidatedString adds support for a new string argument with the description given and defined validation function.
 func (ap *ArgParser) SupportsValidatedString(name, abbrev, valDesc, desc string, validator ValidationFunc) *ArgParser {
 	opt := &Option{name, abbrev, valDesc, OptionalValue, desc, validator, false}
@@ -239,7 +257,7 @@ func (ap *ArgParser) matchModalOptions(arg string) (matches []*Option, rest stri
 func (ap *ArgParser) sortedValueOptions() []string {
 	vos := make([]string, 0, len(ap.Supported))
 	for s, opt := range ap.nameOrAbbrevToOpt {
-		if (opt.OptType == OptionalValue || opt.OptType == OptionalEmptyValue) && s != "" {
+		if (opt.OptType == OptionalValue || opt.OptType == OptionalEmptyValue || opt.OptType == OptionalRepeatedValue || opt.OptType == OptionalAttachedValue) && s != "" {
 			vos = append(vos, s)
 		}
 	}
@@ -274,6 +292,7 @@ func (ap *ArgParser) matchValueOption(arg string, isLongFormFlag bool) (match *O
 func (ap *ArgParser) ParseGlobalArgs(args []string) (apr *ArgParseResults, remaining []string, err error) {
 	list := make([]string, 0, 16)
 	results := make(map[string]string)
+	repeated := make(map[string][]string)
 
 	i := 0
 	for ; i < len(args); i++ {
@@ -285,11 +304,11 @@ func (ap *ArgParser) ParseGlobalArgs(args []string) (apr *ArgParseResults, remai
 
 		if arg[0] != '-' {
 			// This isn't a flag; assume it's the subcommand. Don't parse the remaining args.
-			return &ArgParseResults{results, nil, ap}, args[i:], nil
+			return &ArgParseResults{options: results, repeated: repeated, parser: ap}, args[i:], nil
 		}
 
 		var err error
-		i, list, results, err = ap.parseToken(args, i, list, results)
+		i, list, results, err = ap.parseToken(args, i, list, results, repeated)
 
 		if err != nil {
 			return nil, nil, err
@@ -305,6 +324,7 @@ func (ap *ArgParser) ParseGlobalArgs(args []string) (apr *ArgParseResults, remai
 func (ap *ArgParser) Parse(args []string) (*ArgParseResults, error) {
 	positionalArgs := make([]string, 0, 16)
 	namedArgs := make(map[string]string)
+	repeatedArgs := make(map[string][]string)
 
 	index := 0
 	for ; index < len(args); index++ {
@@ -316,7 +336,7 @@ func (ap *ArgParser) Parse(args []string) (*ArgParseResults, error) {
 		}
 
 		var err error
-		index, positionalArgs, namedArgs, err = ap.parseToken(args, index, positionalArgs, namedArgs)
+		index, positionalArgs, namedArgs, err = ap.parseToken(args, index, positionalArgs, namedArgs, repeatedArgs)
 
 		if err != nil {
 			return nil, err
@@ -331,10 +351,12 @@ func (ap *ArgParser) Parse(args []string) (*ArgParseResults, error) {
 		return nil, ap.TooManyArgsErrorFunc(positionalArgs)
 	}
 
-	return &ArgParseResults{namedArgs, positionalArgs, ap}, nil
+	return &ArgParseResults{options: namedArgs, repeated: repeatedArgs, Args: positionalArgs, parser: ap}, nil
 }
 
-func (ap *ArgParser) parseToken(args []string, index int, positionalArgs []string, namedArgs map[string]string) (newIndex int, newPositionalArgs []string, newNamedArgs map[string]string, err error) {
+// parseToken parses the option at |index| of |args|. Every value of an option supported with SupportsRepeatedString is
+// added to |repeatedArgs|, and the last one given is its value in |namedArgs|.
+func (ap *ArgParser) parseToken(args []string, index int, positionalArgs []string, namedArgs map[string]string, repeatedArgs map[string][]string) (newIndex int, newPositionalArgs []string, newNamedArgs map[string]string, err error) {
 	arg := args[index]
 
 	isLongFormFlag := len(arg) >= 2 && arg[:2] == "--"
@@ -372,12 +394,15 @@ func (ap *ArgParser) parseToken(args []string, index int, positionalArgs []strin
 		return 0, nil, nil, UnknownArgumentParam{name: arg}
 	}
 
-	if _, exists := namedArgs[opt.Name]; exists {
+	if _, exists := namedArgs[opt.Name]; exists && opt.OptType != OptionalRepeatedValue {
 		//already provided
 		return 0, nil, nil, errors.New("error: multiple values provided for `" + opt.Name + "'")
 	}
 
-	if value == nil {
+	if value == nil && opt.OptType == OptionalAttachedValue {
+		valueStr := ""
+		value = &valueStr
+	} else if value == nil {
 		index++
 		valueStr := ""
 		if index >= len(args) {
@@ -404,6 +429,9 @@ func (ap *ArgParser) parseToken(args []string, index int, positionalArgs []strin
 		}
 	}
 
+	if opt.OptType == OptionalRepeatedValue {
+		repeatedArgs[opt.Name] = append(repeatedArgs[opt.Name], *value)
+	}
 	namedArgs[opt.Name] = *value
 	return index, positionalArgs, namedArgs, nil
 }
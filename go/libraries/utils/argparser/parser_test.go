@@ -93,6 +93,20 @@ func TestArgParser(t *testing.T) {
 			map[string]string{},
 			[]string{},
 		},
+		{
+			NewArgParserWithVariableArgs("test").SupportsString("param", "p", "", ""),
+			[]string{"-p", "value1", "-p", "value2"},
+			errors.New("error: multiple values provided for `param'"),
+			map[string]string{},
+			[]string{},
+		},
+		{
+			NewArgParserWithVariableArgs("test").SupportsRepeatedString("param", "p", "", ""),
+			[]string{"-p", "value1", "--param", "value2", "arg1"},
+			nil,
+			map[string]string{"param": "value2"},
+			[]string{"arg1"},
+		},
 		{
 			NewArgParserWithMaxArgs("test", 1),
 			[]string{"foo", "bar"},
@@ -112,3 +126,54 @@ func TestArgParser(t *testing.T) {
 		}
 	}
 }
+
+func TestGetValueRepeated(t *testing.T) {
+	ap := NewArgParserWithVariableArgs("test").SupportsRepeatedString("message", "m", "", "")
+
+	apr, err := ap.Parse([]string{"-m", "subject", "-m", "body, with a comma"})
+	require.NoError(t, err)
+	vals, ok := apr.GetValueRepeated("message")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"subject", "body, with a comma"}, vals)
+
+	apr, err = ap.Parse([]string{"-m", "nul\x00in a value", "-m", "second"})
+	require.NoError(t, err)
+	vals, ok = apr.GetValueRepeated("message")
+	assert.True(t, ok)
+	assert.Equal(t, []string{"nul\x00in a value", "second"}, vals)
+
+	apr, err = ap.Parse([]string{})
+	require.NoError(t, err)
+	_, ok = apr.GetValueRepeated("message")
+	assert.False(t, ok)
+}
+
+func TestFlagWithOptionalValue(t *testing.T) {
+	ap := NewArgParserWithVariableArgs("test").
+		SupportsFlagWithOptionalValue("ignored", "", "mode", "").
+		SupportsFlag("staged", "", "")
+
+	apr, err := ap.Parse([]string{"--ignored", "--staged"})
+	require.NoError(t, err)
+	val, ok := apr.GetValue("ignored")
+	assert.True(t, ok)
+	assert.Equal(t, "", val)
+	assert.True(t, apr.Contains("staged"))
+
+	apr, err = ap.Parse([]string{"--ignored=matching", "arg"})
+	require.NoError(t, err)
+	val, ok = apr.GetValue("ignored")
+	assert.True(t, ok)
+	assert.Equal(t, "matching", val)
+	assert.Equal(t, []string{"arg"}, apr.Args)
+
+	apr, err = ap.Parse([]string{"--ignored", "arg"})
+	require.NoError(t, err)
+	val, _ = apr.GetValue("ignored")
+	assert.Equal(t, "", val)
+	assert.Equal(t, []string{"arg"}, apr.Args)
+
+	apr, err = ap.Parse([]string{})
+	require.NoError(t, err)
+	assert.False(t, apr.Contains("ignored"))
+}
@@ -24,8 +24,10 @@ import (
 
 type ArgParseResults struct {
 	options map[string]string
-	Args    []string
-	parser  *ArgParser
+	// repeated holds every value of the options supported with SupportsRepeatedString, in the order they were given
+	repeated map[string][]string
+	Args     []string
+	parser   *ArgParser
 }
 
 // Equals res and other are only considered equal if the order and contents of their arguments
@@ -47,6 +49,21 @@ func (res *ArgParseResults) Equals(other *ArgParseResults) bool {
 		}
 	}
 
+	if len(res.repeated) != len(other.repeated) {
+		return false
+	}
+	for k, vals := range res.repeated {
+		otherVals, ok := other.repeated[k]
+		if !ok || len(vals) != len(otherVals) {
+			return false
+		}
+		for i, v := range vals {
+			if otherVals[i] != v {
+				return false
+			}
+		}
+	}
+
 	return true
 }
 
@@ -109,6 +126,13 @@ func (res *ArgParseResults) GetValueList(name string) ([]string, bool) {
 	return strings.Split(val, ","), ok
 }
 
+// GetValueRepeated returns every value provided for an option supported with SupportsRepeatedString, in the order
+// they were given. GetValue returns just the last of them.
+func (res *ArgParseResults) GetValueRepeated(name string) ([]string, bool) {
+	vals, ok := res.repeated[name]
+	return vals, ok
+}
+
 func (res *ArgParseResults) GetValues(names ...string) map[string]string {
 	vals := make(map[string]string)
 
@@ -141,7 +141,7 @@ func NewCommitForValue(ctx context.Context, cs chunks.ChunkStore, vrw types.Valu
 	return newCommitForValue(ctx, cs, vrw, ns, v, opts)
 }
 
-func commit_flatbuffer(vaddr hash.Hash, opts CommitOptions, heights []uint64, parentsClosureAddr hash.Hash) (serial.Message, uint64) {
+func commit_flatbuffer(vaddr hash.Hash, opts CommitOptions, heights []uint64, parentsClosureAddr hash.Hash) (serial.Message, uint64, error) {
 	builder := flatbuffers.NewBuilder(1024)
 	vaddroff := builder.CreateByteVector(vaddr[:])
 
@@ -169,6 +169,12 @@ func commit_flatbuffer(vaddr hash.Hash, opts CommitOptions, heights []uint64, pa
 	nameoff := builder.CreateString(opts.Meta.Name)
 	emailoff := builder.CreateString(opts.Meta.Email)
 	descoff := builder.CreateString(opts.Meta.Description)
+	var mdoff flatbuffers.UOffsetT
+	if userMeta, err := encodeCommitMetadata(opts.Meta.Metadata); err != nil {
+		return nil, 0, err
+	} else if userMeta != "" {
+		mdoff = builder.CreateString(userMeta)
+	}
 	serial.CommitStart(builder)
 	serial.CommitAddRoot(builder, vaddroff)
 	serial.CommitAddHeight(builder, maxheight+1)
@@ -179,9 +185,12 @@ func commit_flatbuffer(vaddr hash.Hash, opts CommitOptions, heights []uint64, pa
 	serial.CommitAddDescription(builder, descoff)
 	serial.CommitAddTimestampMillis(builder, opts.Meta.Timestamp)
 	serial.CommitAddUserTimestampMillis(builder, opts.Meta.UserTimestamp)
+	if mdoff != 0 {
+		serial.CommitAddMetadata(builder, mdoff)
+	}
 
 	bytes := serial.FinishMessage(builder, serial.CommitEnd(builder), []byte(serial.CommitFileID))
-	return bytes, maxheight + 1
+	return bytes, maxheight + 1, nil
 }
 
 var commitKeyTupleDesc = val.NewTupleDescriptor(
@@ -194,6 +203,9 @@ func newCommitForValue(ctx context.Context, cs chunks.ChunkStore, vrw types.Valu
 	if opts.Meta == nil {
 		opts.Meta = &CommitMeta{}
 	}
+	if err := ValidateCommitMetadata(opts.Meta.Metadata); err != nil {
+		return nil, err
+	}
 
 	if vrw.Format().UsesFlatbuffers() {
 		r, err := vrw.WriteValue(ctx, v)
@@ -217,7 +229,10 @@ func newCommitForValue(ctx context.Context, cs chunks.ChunkStore, vrw types.Valu
 		if err != nil {
 			return nil, err
 		}
-		bs, height := commit_flatbuffer(r.TargetHash(), opts, heights, parentClosureAddr)
+		bs, height, err := commit_flatbuffer(r.TargetHash(), opts, heights, parentClosureAddr)
+		if err != nil {
+			return nil, err
+		}
 		v := types.SerialMessage(bs)
 		addr, err := v.Hash(vrw.Format())
 		if err != nil {
@@ -557,6 +572,10 @@ func GetCommitMeta(ctx context.Context, cv types.Value) (*CommitMeta, error) {
 		ret.Description = string(cmsg.Description())
 		ret.Timestamp = cmsg.TimestampMillis()
 		ret.UserTimestamp = cmsg.UserTimestampMillis()
+		ret.Metadata, err = decodeCommitMetadata(string(cmsg.Metadata()))
+		if err != nil {
+			return nil, err
+		}
 		return ret, nil
 	}
 	c, ok := cv.(types.Struct)
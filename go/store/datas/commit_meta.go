@@ -15,10 +15,14 @@
 package datas
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/dolthub/dolt/go/store/types"
 )
@@ -30,16 +34,39 @@ const (
 	commitMetaTimestampKey = "timestamp"
 	commitMetaUserTSKey    = "user_timestamp"
 	commitMetaVersionKey   = "metaversion"
+	commitMetaUserMetaKey  = "user_metadata"
 
 	commitMetaStName  = "metadata"
 	commitMetaVersion = "1.0"
 )
 
+const (
+	// MaxCommitMetadataKeyLen is the maximum length, in bytes, of a key of a commit's user-defined metadata.
+	MaxCommitMetadataKeyLen = 128
+	// MaxCommitMetadataSize is the maximum size, in bytes, of all the keys and values of a commit's user-defined
+	// metadata together.
+	MaxCommitMetadataSize = 16 * 1024
+)
+
+// ReservedMetadataKeyPrefix is the prefix of the metadata keys which Dolt records itself, such as TimeZoneMetadataKey.
+// ValidateUserCommitMetadata refuses keys with this prefix, so that the values Dolt reads back from them can't have
+// been given by a user with --meta.
+const ReservedMetadataKeyPrefix = "dolt_"
+
+// TimeZoneMetadataKey is the key of the metadata which records the time zone a commit was made in, when one was given,
+// as an IANA name such as America/New_York or a UTC offset such as +05:30.
+const TimeZoneMetadataKey = ReservedMetadataKeyPrefix + "timezone"
+
+// EpochNanosMetadataKey is the key of the metadata which records the time of a commit in nanoseconds since the Unix
+// epoch, when it was given that precisely. The commit's timestamps only have millisecond precision.
+const EpochNanosMetadataKey = ReservedMetadataKeyPrefix + "epoch_nanos"
+
 const defaultInitialCommitMessage = "Initialize data repository"
 
 var ErrNameNotConfigured = errors.New("Aborting commit due to empty committer name. Is your config set?")
 var ErrEmailNotConfigured = errors.New("Aborting commit due to empty committer email. Is your config set?")
 var ErrEmptyCommitMessage = errors.New("Aborting commit due to empty commit message.")
+var ErrCommitMetadataTooLarge = fmt.Errorf("commit metadata is larger than the limit of %d bytes", MaxCommitMetadataSize)
 
 var CommitNowFunc = time.Now
 var CommitLoc = time.Local
@@ -51,6 +78,8 @@ type CommitMeta struct {
 	Timestamp     uint64
 	Description   string
 	UserTimestamp int64
+	// Metadata is user-defined key/value metadata, such as the ID of a ticket or build, or nil if there is none.
+	Metadata map[string]string
 }
 
 // NewCommitMeta creates a CommitMeta instance from a name, email, and description and uses the current time for the
@@ -80,7 +109,92 @@ func NewCommitMetaWithUserTS(name, email, desc string, userTS time.Time) (*Commi
 	ms := uint64(CommitNowFunc().UnixMilli())
 	userMS := userTS.UnixMilli()
 
-	return &CommitMeta{n, e, ms, d, userMS}, nil
+	return &CommitMeta{Name: n, Email: e, Timestamp: ms, Description: d, UserTimestamp: userMS}, nil
+}
+
+// ValidateCommitMetadata returns an error if |md| isn't valid as the user-defined metadata of a commit. Keys must be
+// non-empty, no longer than MaxCommitMetadataKeyLen, and contain no whitespace, control characters or '=', and the
+// metadata must be no larger than MaxCommitMetadataSize in total.
+func ValidateCommitMetadata(md map[string]string) error {
+	size := 0
+	for k, v := range md {
+		if k == "" {
+			return errors.New("commit metadata keys cannot be empty")
+		}
+		if len(k) > MaxCommitMetadataKeyLen {
+			return fmt.Errorf("commit metadata key '%s' is longer than the limit of %d bytes", k, MaxCommitMetadataKeyLen)
+		}
+		for _, r := range k {
+			if unicode.IsControl(r) || unicode.IsSpace(r) || r == '=' {
+				return fmt.Errorf("commit metadata key %q contains an invalid character %q", k, r)
+			}
+		}
+		size += len(k) + len(v)
+	}
+	if size > MaxCommitMetadataSize {
+		return ErrCommitMetadataTooLarge
+	}
+	return nil
+}
+
+// ValidateUserCommitMetadata returns an error if |md|, given by a user, isn't valid as the metadata of a commit: if
+// ValidateCommitMetadata refuses it, or if any of its keys start with ReservedMetadataKeyPrefix.
+func ValidateUserCommitMetadata(md map[string]string) error {
+	for k := range md {
+		if strings.HasPrefix(k, ReservedMetadataKeyPrefix) {
+			return fmt.Errorf("commit metadata key '%s' is reserved: keys starting with '%s' are recorded by Dolt", k, ReservedMetadataKeyPrefix)
+		}
+	}
+	return ValidateCommitMetadata(md)
+}
+
+var utcOffsetRegex = regexp.MustCompile(`^([+-])([0-9]{2}):?([0-9]{2})?$`)
+
+// ParseTimeZone returns the time zone named by |tz|, which is either an IANA name such as America/New_York, or a UTC
+// offset such as +05:30, -0800 or +02.
+func ParseTimeZone(tz string) (*time.Location, error) {
+	if m := utcOffsetRegex.FindStringSubmatch(tz); m != nil {
+		hours, _ := strconv.Atoi(m[2])
+		minutes, _ := strconv.Atoi(m[3])
+		if hours > 14 || minutes > 59 {
+			return nil, fmt.Errorf("invalid time zone offset '%s'", tz)
+		}
+		secs := hours*60*60 + minutes*60
+		if m[1] == "-" {
+			secs = -secs
+		}
+		return time.FixedZone(tz, secs), nil
+	}
+
+	loc, err := time.LoadLocation(tz)
+	if err != nil || tz == "" || tz == "Local" {
+		return nil, fmt.Errorf("unknown time zone '%s'", tz)
+	}
+	return loc, nil
+}
+
+// encodeCommitMetadata returns |md| encoded as a JSON object, or the empty string if it's empty.
+func encodeCommitMetadata(md map[string]string) (string, error) {
+	if len(md) == 0 {
+		return "", nil
+	}
+	b, err := json.Marshal(md)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// decodeCommitMetadata decodes metadata encoded by encodeCommitMetadata, returning nil for the empty string.
+func decodeCommitMetadata(s string) (map[string]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var md map[string]string
+	if err := json.Unmarshal([]byte(s), &md); err != nil {
+		return nil, fmt.Errorf("invalid commit metadata: %w", err)
+	}
+	return md, nil
 }
 
 func getRequiredFromSt(st types.Struct, k string) (types.Value, error) {
@@ -126,12 +240,23 @@ func CommitMetaFromNomsSt(st types.Struct) (*CommitMeta, error) {
 		userTS = types.Int(int64(uint64(ts.(types.Uint))))
 	}
 
+	var md map[string]string
+	if userMeta, ok, err := st.MaybeGet(commitMetaUserMetaKey); err != nil {
+		return nil, err
+	} else if ok {
+		md, err = decodeCommitMetadata(string(userMeta.(types.String)))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &CommitMeta{
-		string(n.(types.String)),
-		string(e.(types.String)),
-		uint64(ts.(types.Uint)),
-		string(d.(types.String)),
-		int64(userTS.(types.Int)),
+		Name:          string(n.(types.String)),
+		Email:         string(e.(types.String)),
+		Timestamp:     uint64(ts.(types.Uint)),
+		Description:   string(d.(types.String)),
+		UserTimestamp: int64(userTS.(types.Int)),
+		Metadata:      md,
 	}, nil
 }
 
@@ -145,18 +270,45 @@ func (cm *CommitMeta) toNomsStruct(nbf *types.NomsBinFormat) (types.Struct, erro
 		commitMetaUserTSKey:    types.Int(cm.UserTimestamp),
 	}
 
+	// Only commits with user-defined metadata have the field, so that the struct of those without it is unchanged
+	userMeta, err := encodeCommitMetadata(cm.Metadata)
+	if err != nil {
+		return types.EmptyStruct(nbf), err
+	}
+	if userMeta != "" {
+		metadata[commitMetaUserMetaKey] = types.String(userMeta)
+	}
+
 	return types.NewStruct(nbf, commitMetaStName, metadata)
 }
 
-// Time returns the time at which the commit occurred
+// Time returns the time at which the commit occurred, to the nanosecond if it was recorded with EpochNanosMetadataKey
+// and agrees with the commit's timestamp.
 func (cm *CommitMeta) Time() time.Time {
+	if nanosStr, ok := cm.Metadata[EpochNanosMetadataKey]; ok {
+		if nanos, err := strconv.ParseInt(nanosStr, 10, 64); err == nil {
+			if t := time.Unix(0, nanos); t.UnixMilli() == cm.UserTimestamp {
+				return t
+			}
+		}
+	}
 	return time.UnixMilli(cm.UserTimestamp)
 }
 
+// Location returns the time zone recorded in the commit's metadata, or CommitLoc if none was recorded.
+func (cm *CommitMeta) Location() *time.Location {
+	if tz, ok := cm.Metadata[TimeZoneMetadataKey]; ok {
+		if loc, err := ParseTimeZone(tz); err == nil {
+			return loc
+		}
+	}
+	return CommitLoc
+}
+
 // FormatTS takes the internal timestamp and turns it into a human readable string in the time.RubyDate format
-// which looks like: "Mon Jan 02 15:04:05 -0700 2006"
+// which looks like: "Mon Jan 02 15:04:05 -0700 2006". The time is given in the time zone returned by Location.
 func (cm *CommitMeta) FormatTS() string {
-	return cm.Time().In(CommitLoc).Format(time.RubyDate)
+	return cm.Time().In(cm.Location()).Format(time.RubyDate)
 }
 
 // String returns the human readable string representation of the commit data
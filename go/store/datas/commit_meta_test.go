@@ -15,11 +15,16 @@
 package datas
 
 import (
+	"context"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/dolthub/dolt/go/store/hash"
 	"github.com/dolthub/dolt/go/store/types"
 )
 
@@ -37,3 +42,85 @@ func TestCommitMetaToAndFromNomsStruct(t *testing.T) {
 
 	t.Log(cm.String())
 }
+
+func TestCommitMetadata(t *testing.T) {
+	cm, err := NewCommitMeta("Bill Billerson", "bigbillieb@fake.horse", "This is a test commit")
+	require.NoError(t, err)
+	cm.Metadata = map[string]string{"ticket": "DOLT-123", "build": "42"}
+
+	cmSt, err := cm.toNomsStruct(types.Format_Default)
+	require.NoError(t, err)
+	result, err := CommitMetaFromNomsSt(cmSt)
+	require.NoError(t, err)
+	assert.Equal(t, cm, result)
+
+	bs, _, err := commit_flatbuffer(hash.Hash{}, CommitOptions{Meta: cm}, nil, hash.Hash{})
+	require.NoError(t, err)
+	result, err = GetCommitMeta(context.Background(), types.SerialMessage(bs))
+	require.NoError(t, err)
+	assert.Equal(t, cm, result)
+
+	cm.Metadata = nil
+	bs, _, err = commit_flatbuffer(hash.Hash{}, CommitOptions{Meta: cm}, nil, hash.Hash{})
+	require.NoError(t, err)
+	result, err = GetCommitMeta(context.Background(), types.SerialMessage(bs))
+	require.NoError(t, err)
+	assert.Nil(t, result.Metadata)
+}
+
+func TestValidateCommitMetadata(t *testing.T) {
+	assert.NoError(t, ValidateCommitMetadata(nil))
+	assert.NoError(t, ValidateCommitMetadata(map[string]string{"ticket": "DOLT-123", "build.number": ""}))
+	assert.Error(t, ValidateCommitMetadata(map[string]string{"": "value"}))
+	assert.Error(t, ValidateCommitMetadata(map[string]string{"tick\x00et": "value"}))
+	assert.Error(t, ValidateCommitMetadata(map[string]string{"tick et": "value"}))
+	assert.Error(t, ValidateCommitMetadata(map[string]string{strings.Repeat("k", MaxCommitMetadataKeyLen+1): "value"}))
+	assert.ErrorIs(t, ValidateCommitMetadata(map[string]string{"key": strings.Repeat("v", MaxCommitMetadataSize)}), ErrCommitMetadataTooLarge)
+}
+
+func TestValidateUserCommitMetadata(t *testing.T) {
+	assert.NoError(t, ValidateUserCommitMetadata(map[string]string{"ticket": "DOLT-123", "timezone": "+02:00"}))
+	assert.Error(t, ValidateUserCommitMetadata(map[string]string{TimeZoneMetadataKey: "+02:00"}))
+	assert.Error(t, ValidateUserCommitMetadata(map[string]string{EpochNanosMetadataKey: "1"}))
+	assert.Error(t, ValidateUserCommitMetadata(map[string]string{"tick et": "value"}))
+}
+
+func TestCommitMetaTimeZone(t *testing.T) {
+	loc, err := ParseTimeZone("+05:30")
+	require.NoError(t, err)
+	_, offset := time.Date(2023, 6, 7, 0, 0, 0, 0, loc).Zone()
+	assert.Equal(t, 5*60*60+30*60, offset)
+
+	loc, err = ParseTimeZone("-0800")
+	require.NoError(t, err)
+	_, offset = time.Date(2023, 6, 7, 0, 0, 0, 0, loc).Zone()
+	assert.Equal(t, -8*60*60, offset)
+
+	_, err = ParseTimeZone("+25:00")
+	assert.Error(t, err)
+	_, err = ParseTimeZone("Not/A_Zone")
+	assert.Error(t, err)
+
+	cm, err := NewCommitMetaWithUserTS("Bill Billerson", "bigbillieb@fake.horse", "This is a test commit", time.Date(2023, 6, 7, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	cm.Metadata = map[string]string{TimeZoneMetadataKey: "+02:00"}
+	assert.Equal(t, "Wed Jun 07 14:00:00 +0200 2023", cm.FormatTS())
+
+	// a user's own metadata with a similar key doesn't change the time zone
+	cm.Metadata = map[string]string{"timezone": "+02:00"}
+	assert.Equal(t, CommitLoc, cm.Location())
+}
+
+func TestCommitMetaEpochNanos(t *testing.T) {
+	nanos := time.Date(2023, 6, 7, 12, 0, 0, 123456789, time.UTC)
+	cm, err := NewCommitMetaWithUserTS("Bill Billerson", "bigbillieb@fake.horse", "This is a test commit", nanos)
+	require.NoError(t, err)
+	assert.Equal(t, nanos.Truncate(time.Millisecond).UnixNano(), cm.Time().UnixNano())
+
+	cm.Metadata = map[string]string{EpochNanosMetadataKey: "1686139200123456789"}
+	assert.Equal(t, nanos.UnixNano(), cm.Time().UnixNano())
+
+	// a recorded time which doesn't agree with the commit's timestamp, e.g. after amending its date, is ignored
+	cm.Metadata = map[string]string{EpochNanosMetadataKey: "1686139201123456789"}
+	assert.Equal(t, nanos.Truncate(time.Millisecond).UnixNano(), cm.Time().UnixNano())
+}
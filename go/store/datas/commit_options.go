@@ -32,5 +32,10 @@ type CommitOptions struct {
 	// parent.
 	Parents []hash.Hash
 
+	// ExactParents, if true, makes Parents the complete list of parents
+	// of the commit. Otherwise the existing dataset head is added as the
+	// first parent when it isn't already among them.
+	ExactParents bool
+
 	Meta *CommitMeta
 }
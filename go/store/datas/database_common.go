@@ -432,7 +432,7 @@ func (db *database) BuildNewCommit(ctx context.Context, ds Dataset, v types.Valu
 		if ok {
 			opts.Parents = []hash.Hash{headAddr}
 		}
-	} else {
+	} else if !opts.ExactParents {
 		curr, ok := ds.MaybeHeadAddr()
 		if ok {
 			if !hasParentHash(opts, curr) {
@@ -670,7 +670,8 @@ func assertDatasetHash(
 
 // CommitWithWorkingSet updates two Datasets atomically: the working set, and its corresponding HEAD. Uses the same
 // global locking mechanism as UpdateWorkingSet.
-// The current dataset head will be filled in as the first parent of the new commit if not already present.
+// The current dataset head will be filled in as the first parent of the new commit if not already present, unless
+// |opts.ExactParents| is set.
 func (db *database) CommitWithWorkingSet(
 	ctx context.Context,
 	commitDS, workingSetDS Dataset,
@@ -684,7 +685,7 @@ func (db *database) CommitWithWorkingSet(
 
 	// Prepend the current head hash to the list of parents if one was provided. This is only necessary if parents were
 	// provided because we fill it in automatically in buildNewCommit otherwise.
-	if len(opts.Parents) > 0 {
+	if len(opts.Parents) > 0 && !opts.ExactParents {
 		headHash, ok := commitDS.MaybeHeadAddr()
 		if ok {
 			if !hasParentHash(opts, headHash) {
@@ -52,6 +52,22 @@ var _ manifest = &chunkJournal{}
 var _ manifestGCGenUpdater = &chunkJournal{}
 var _ io.Closer = &chunkJournal{}
 
+// skipSyncKey is the context key set by WithoutSync.
+type skipSyncKey struct{}
+
+// WithoutSync returns a context with which manifest updates to a chunk journal write the new root to the journal file
+// without syncing it to disk. This is unsafe for crash recovery: the update, and any before it that weren't synced,
+// can be lost in a crash until a later update syncs the journal. Stores without a chunk journal ignore it.
+func WithoutSync(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipSyncKey{}, true)
+}
+
+// syncDisabled returns whether |ctx| was returned by WithoutSync.
+func syncDisabled(ctx context.Context) bool {
+	skip, _ := ctx.Value(skipSyncKey{}).(bool)
+	return skip
+}
+
 func newChunkJournal(ctx context.Context, nbfVers, dir string, m *journalManifest, p *fsTablePersister) (*chunkJournal, error) {
 	path, err := filepath.Abs(filepath.Join(dir, chunkJournalName))
 	if err != nil {
@@ -288,7 +304,11 @@ func (j *chunkJournal) Update(ctx context.Context, lastLock addr, next manifestC
 		}
 	}
 
-	if err := j.wr.commitRootHash(next.root); err != nil {
+	commit := j.wr.commitRootHash
+	if syncDisabled(ctx) {
+		commit = j.wr.commitRootHashWithoutSync
+	}
+	if err := commit(next.root); err != nil {
 		return manifestContents{}, err
 	}
 	j.contents = next
@@ -349,6 +349,17 @@ func (wr *journalWriter) writeCompressedChunk(cc CompressedChunk) error {
 
 // commitRootHash commits |root| to the journal and syncs the file to disk.
 func (wr *journalWriter) commitRootHash(root hash.Hash) error {
+	return wr.writeRootHash(root, true)
+}
+
+// commitRootHashWithoutSync commits |root| to the journal without syncing the file to disk. The root is written to
+// the file, but it can be lost in a crash until the journal is synced by a later commit.
+func (wr *journalWriter) commitRootHashWithoutSync(root hash.Hash) error {
+	return wr.writeRootHash(root, false)
+}
+
+// writeRootHash writes a root hash record for |root| to the journal, syncing the file to disk if |sync| is true.
+func (wr *journalWriter) writeRootHash(root hash.Hash, sync bool) error {
 	wr.lock.Lock()
 	defer wr.lock.Unlock()
 	buf, err := wr.getBytes(rootHashRecordSize())
@@ -359,8 +370,10 @@ func (wr *journalWriter) commitRootHash(root hash.Hash) error {
 	if err = wr.flush(); err != nil {
 		return err
 	}
-	if err = wr.journal.Sync(); err != nil {
-		return err
+	if sync {
+		if err = wr.journal.Sync(); err != nil {
+			return err
+		}
 	}
 	if wr.ranges.novelCount() > wr.maxNovel {
 		o := wr.offset() - int64(n) // pre-commit journal offset
@@ -232,6 +232,29 @@ func TestJournalWriterBootstrap(t *testing.T) {
 	}
 }
 
+func TestJournalWriterCommitWithoutSync(t *testing.T) {
+	ctx := context.Background()
+	path := newTestFilePath(t)
+	j := newTestJournalWriter(t, path)
+	data := randomCompressedChunks(64)
+	var last hash.Hash
+	for _, cc := range data {
+		require.NoError(t, j.writeCompressedChunk(cc))
+		last = cc.Hash()
+	}
+	require.NoError(t, j.commitRootHashWithoutSync(last))
+
+	j, _, err := openJournalWriter(ctx, path)
+	require.NoError(t, err)
+	root, err := j.bootstrapJournal(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, last, root)
+	validateAllLookups(t, j, data)
+
+	assert.False(t, syncDisabled(ctx))
+	assert.True(t, syncDisabled(WithoutSync(ctx)))
+}
+
 func validateAllLookups(t *testing.T, j *journalWriter, data map[addr]CompressedChunk) {
 	// move |data| to addr16-keyed map
 	prefixMap := make(map[addr16]CompressedChunk, len(data))
@@ -0,0 +1,115 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tree
+
+import (
+	"context"
+
+	"github.com/dolthub/dolt/go/store/hash"
+)
+
+// SizeDiff returns the total size of the nodes of the tree rooted at |to| which aren't in the tree rooted at |from|,
+// and the total size of the nodes of |from| which aren't in |to|. Either root may be the zero Node, for a tree that
+// doesn't exist. The trees are walked level by level, and subtrees they share are skipped without being read, so the
+// cost is proportional to the size of the difference rather than to the size of the trees. Nodes of other message
+// types referenced by the trees, such as out-of-band blobs, aren't included.
+func SizeDiff(ctx context.Context, ns NodeStore, from, to Node) (added, removed uint64, err error) {
+	var froms, tos []Node
+	if from.bytes() != nil {
+		froms = append(froms, from)
+	}
+	if to.bytes() != nil {
+		tos = append(tos, to)
+	}
+
+	for len(froms) > 0 || len(tos) > 0 {
+		fromLevel, toLevel := frontierLevel(froms), frontierLevel(tos)
+		if fromLevel == toLevel {
+			froms, tos = removeSharedNodes(froms, tos)
+		}
+		if fromLevel >= toLevel {
+			removed += frontierSize(froms)
+			if froms, err = frontierChildren(ctx, ns, froms); err != nil {
+				return 0, 0, err
+			}
+		}
+		if toLevel >= fromLevel {
+			added += frontierSize(tos)
+			if tos, err = frontierChildren(ctx, ns, tos); err != nil {
+				return 0, 0, err
+			}
+		}
+	}
+	return added, removed, nil
+}
+
+// frontierLevel returns the level of the nodes in |nodes|, which all have the same level, or -1 if it's empty.
+func frontierLevel(nodes []Node) int {
+	if len(nodes) == 0 {
+		return -1
+	}
+	return nodes[0].Level()
+}
+
+// frontierSize returns the total size of |nodes|.
+func frontierSize(nodes []Node) (sz uint64) {
+	for _, nd := range nodes {
+		sz += uint64(nd.Size())
+	}
+	return sz
+}
+
+// removeSharedNodes returns |froms| and |tos| without the nodes they have in common.
+func removeSharedNodes(froms, tos []Node) ([]Node, []Node) {
+	fromAddrs := make(map[hash.Hash]struct{}, len(froms))
+	for _, nd := range froms {
+		fromAddrs[nd.HashOf()] = struct{}{}
+	}
+	shared := make(map[hash.Hash]struct{})
+	newTos := tos[:0]
+	for _, nd := range tos {
+		addr := nd.HashOf()
+		if _, ok := fromAddrs[addr]; ok {
+			shared[addr] = struct{}{}
+			continue
+		}
+		newTos = append(newTos, nd)
+	}
+	newFroms := froms[:0]
+	for _, nd := range froms {
+		if _, ok := shared[nd.HashOf()]; !ok {
+			newFroms = append(newFroms, nd)
+		}
+	}
+	return newFroms, newTos
+}
+
+// frontierChildren returns the children of |nodes|, or nil if they're leaves.
+func frontierChildren(ctx context.Context, ns NodeStore, nodes []Node) ([]Node, error) {
+	if len(nodes) == 0 || nodes[0].IsLeaf() {
+		return nil, nil
+	}
+	var addrs hash.HashSlice
+	for _, nd := range nodes {
+		err := walkAddresses(ctx, nd, func(ctx context.Context, addr hash.Hash) error {
+			addrs = append(addrs, addr)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ns.ReadMany(ctx, addrs)
+}
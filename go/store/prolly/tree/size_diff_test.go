@@ -0,0 +1,95 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tree
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/dolt/go/store/prolly/message"
+)
+
+func TestSizeDiff(t *testing.T) {
+	ctx := context.Background()
+	ns := NewTestNodeStore()
+	items := randomTupleItemPairs(10_000, ns)
+	from := buildSizeDiffTree(t, ns, items)
+	require.True(t, from.Level() > 0)
+	total := treeSize(t, ns, from)
+
+	t.Run("same tree", func(t *testing.T) {
+		added, removed, err := SizeDiff(ctx, ns, from, from)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(0), added)
+		assert.Equal(t, uint64(0), removed)
+	})
+	t.Run("new tree", func(t *testing.T) {
+		added, removed, err := SizeDiff(ctx, ns, Node{}, from)
+		require.NoError(t, err)
+		assert.Equal(t, total, added)
+		assert.Equal(t, uint64(0), removed)
+	})
+	t.Run("dropped tree", func(t *testing.T) {
+		added, removed, err := SizeDiff(ctx, ns, from, Node{})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(0), added)
+		assert.Equal(t, total, removed)
+	})
+	t.Run("updated value", func(t *testing.T) {
+		updated := make([][2]Item, len(items))
+		copy(updated, items)
+		updated[len(updated)/2][1] = updated[0][1]
+		to := buildSizeDiffTree(t, ns, updated)
+
+		added, removed, err := SizeDiff(ctx, ns, from, to)
+		require.NoError(t, err)
+		assert.True(t, added > 0)
+		assert.True(t, removed > 0)
+		assert.True(t, added < total/2)
+		assert.True(t, removed < total/2)
+	})
+	t.Run("appended values", func(t *testing.T) {
+		short := buildSizeDiffTree(t, ns, items[:100])
+		added, removed, err := SizeDiff(ctx, ns, short, from)
+		require.NoError(t, err)
+		assert.True(t, added > total/2)
+		assert.True(t, removed <= treeSize(t, ns, short))
+	})
+}
+
+func buildSizeDiffTree(t *testing.T, ns NodeStore, items [][2]Item) Node {
+	ctx := context.Background()
+	serializer := message.NewProllyMapSerializer(valDesc, ns.Pool())
+	chkr, err := newEmptyChunker(ctx, ns, serializer)
+	require.NoError(t, err)
+	for _, item := range items {
+		require.NoError(t, chkr.AddPair(ctx, item[0], item[1]))
+	}
+	nd, err := chkr.Done(ctx)
+	require.NoError(t, err)
+	return nd
+}
+
+func treeSize(t *testing.T, ns NodeStore, nd Node) (sz uint64) {
+	err := WalkNodes(context.Background(), nd, ns, func(ctx context.Context, nd Node) error {
+		sz += uint64(nd.Size())
+		return nil
+	})
+	require.NoError(t, err)
+	return sz
+}
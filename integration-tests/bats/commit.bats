@@ -72,4 +72,77 @@ teardown() {
   # When no changes are staged, --skip-empty skips creating the commit
   dolt commit --skip-empty -m "commit message"
   [ $new_head = $(get_head_commit) ]
-}
\ No newline at end of file
+}
+
+@test "commit: --signoff adds a Signed-off-by trailer" {
+  dolt sql -q "create table t(pk int primary key);"
+  dolt add t
+  run dolt commit -s -m "add t" --author "John Doe <john@doe.com>"
+  [ $status -eq 0 ]
+
+  run dolt log -n 1
+  [ $status -eq 0 ]
+  [[ "$output" =~ "Signed-off-by: John Doe <john@doe.com>" ]] || false
+}
+
+@test "commit: --abbrev shortens the hash of the new commit" {
+  dolt sql -q "create table t(pk int primary key);"
+  dolt add t
+  run dolt commit -m "add t" --abbrev 8
+  [ $status -eq 0 ]
+
+  head=$(get_head_commit)
+  [[ "$output" =~ "commit ${head:0:8} " ]] || false
+  [[ ! "$output" =~ "$head" ]] || false
+
+  run dolt sql -q "call dolt_commit('--allow-empty', '-m', 'empty', '--abbrev', '8')"
+  [ $status -ne 0 ]
+}
+
+@test "commit: --reset-author makes the current user the author of an amended commit" {
+  dolt sql -q "create table t(pk int primary key);"
+  dolt add t
+  dolt commit -m "add t" --author "John Doe <john@doe.com>" --date "2023-01-01T00:00:00"
+
+  dolt sql -q "insert into t values (1)"
+  run dolt commit --amend-metadata-only --reset-author
+  [ $status -eq 0 ]
+
+  run dolt log -n 1
+  [ $status -eq 0 ]
+  [[ "$output" =~ "Author: Bats Tests <bats@email.fake>" ]] || false
+  [[ "$output" =~ "add t" ]] || false
+  [[ ! "$output" =~ "2023" ]] || false
+
+  # the tree is kept, so the insert is still uncommitted
+  run dolt status
+  [ $status -eq 0 ]
+  [[ "$output" =~ "modified:         t" ]] || false
+
+  run dolt commit --reset-author -m "x"
+  [ $status -ne 0 ]
+  [[ "$output" =~ "--reset-author can only be used with --amend or --amend-metadata-only" ]] || false
+
+  run dolt commit --amend --reset-author --author "John Doe <john@doe.com>"
+  [ $status -ne 0 ]
+  [[ "$output" =~ "cannot use both --reset-author and --author" ]] || false
+}
+
+@test "commit: --output json prints the result as a single JSON object" {
+  dolt sql -q "create table t(pk int primary key);"
+  dolt sql -q "create table u(pk int primary key);"
+  dolt add t u
+  run dolt commit -m "add t and u" --output json
+  [ $status -eq 0 ]
+
+  head=$(get_head_commit)
+  [ "$output" = "{\"commit\":\"$head\",\"skipped\":false,\"tables\":[\"t\",\"u\"]}" ]
+
+  run dolt commit -m "nothing" --skip-empty --output json
+  [ $status -eq 0 ]
+  [ "$output" = '{"commit":null,"skipped":true,"tables":[]}' ]
+
+  run dolt commit --allow-empty -m "x" --output yaml
+  [ $status -ne 0 ]
+  [[ "$output" =~ "invalid value 'yaml' for --output" ]] || false
+}
@@ -384,4 +384,67 @@ SQL
     [[ ! -z $(echo "$conflict" | grep "a_test") ]] || false
     [[ ! -z $(echo "$conflict" | grep "a_foo") ]] || false
 
-}
\ No newline at end of file
+}
+
+@test "ignore: dolt status --ignored=matching shows the pattern deciding each table" {
+
+    dolt sql <<SQL
+CREATE TABLE a_ignore (pk int);
+CREATE TABLE do_not_ignore (pk int);
+CREATE TABLE nomatch (pk int);
+SQL
+
+    run dolt status --ignored=matching
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "a_ignore (ignored by pattern '*_ignore')" ]] || false
+    [[ "$output" =~ "do_not_ignore (not ignored by pattern 'do_not_ignore')" ]] || false
+    [[ ! "$output" =~ "nomatch (" ]] || false
+
+    run dolt status --ignored
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "a_ignore" ]] || false
+    [[ ! "$output" =~ "by pattern" ]] || false
+
+    run dolt status --ignored=everything
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "invalid value 'everything' for --ignored" ]] || false
+}
+
+@test "ignore: dolt status warns about tracked tables matching an ignore pattern" {
+    dolt sql -q "CREATE TABLE ignoreme (pk int)"
+    dolt sql -q "CREATE TABLE a_ignore (pk int)"
+    dolt add --force ignoreme
+    dolt commit -m "track ignoreme"
+
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "warning: tracked table ignoreme matches the dolt_ignore pattern 'ignoreme'" ]] || false
+    [[ ! "$output" =~ "warning: tracked table a_ignore" ]] || false
+
+    dolt sql -q "DELETE FROM dolt_ignore WHERE pattern = 'ignoreme'"
+    dolt add dolt_ignore
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "warning: tracked table" ]] || false
+}
+
+@test "ignore: dolt status --fail-on-ignored fails when ignored tables have changes" {
+    dolt sql -q "CREATE TABLE nomatch (pk int)"
+
+    run dolt status --fail-on-ignored
+    [ "$status" -eq 0 ]
+
+    dolt sql -q "CREATE TABLE a_ignore (pk int)"
+
+    run dolt status --fail-on-ignored --ignored
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "Ignored tables:" ]] || false
+    [[ "$output" =~ "ignored tables have changes: a_ignore" ]] || false
+
+    run dolt status
+    [ "$status" -eq 0 ]
+
+    run dolt status --fail-on-ignored --worktrees
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "cannot use both --worktrees and --fail-on-ignored" ]] || false
+}
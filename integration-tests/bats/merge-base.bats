@@ -89,3 +89,21 @@ teardown() {
     [ "$status" -eq 0 ]
     [ "${lines[1]}" = "true" ]
 }
+
+@test "merge-base: dolt_merge_base() returns NULL for unrelated histories" {
+    mkdir -p remotes/other other
+    cd other
+    dolt init
+    dolt sql -q "CREATE TABLE other (pk int primary key);"
+    dolt add -A && dolt commit -m "unrelated commit"
+    dolt remote add origin file://../remotes/other
+    dolt push origin main
+    cd ..
+
+    dolt remote add other file://./remotes/other
+    dolt fetch other
+
+    run dolt sql -q "SELECT dolt_merge_base('main', 'other/main') IS NULL;" -r csv
+    [ "$status" -eq 0 ]
+    [ "${lines[1]}" = "true" ]
+}
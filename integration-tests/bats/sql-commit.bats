@@ -471,3 +471,1040 @@ SQL
   dolt sql -q "CALL DOLT_COMMIT('--skip-empty', '-m', 'commit message');"
   [ $new_head = $(get_head_commit) ]
 }
+
+@test "sql-commit: DOLT_COMMIT_DETAILED --row-stats and --exact-row-stats report changed row counts" {
+    run dolt sql -r csv -q "CALL DOLT_COMMIT_DETAILED('-m', 'no stats')"
+    [ $status -eq 0 ]
+    [[ "${lines[0]}" = "commit_hash,skipped,tables_committed,rows_added,rows_modified,rows_deleted" ]] || false
+    [[ "${lines[1]}" =~ ,1,,,$ ]] || false
+
+    dolt sql -q "INSERT INTO test VALUES (3),(4)"
+    run dolt sql -r csv -q "CALL DOLT_COMMIT_DETAILED('-am', 'estimated', '--row-stats')"
+    [ $status -eq 0 ]
+    [[ "${lines[1]}" =~ ,1,2,0,0$ ]] || false
+
+    dolt sql -q "DELETE FROM test WHERE pk = 0"
+    dolt sql -q "INSERT INTO test VALUES (5)"
+    run dolt sql -r csv -q "CALL DOLT_COMMIT_DETAILED('-am', 'exact', '--exact-row-stats')"
+    [ $status -eq 0 ]
+    [[ "${lines[1]}" =~ ,1,1,0,1$ ]] || false
+}
+
+@test "sql-commit: DOLT_COMMIT returns only the hash and rejects the row stats flags" {
+    run dolt sql -r csv -q "CALL DOLT_COMMIT('-m', 'hash only')"
+    [ $status -eq 0 ]
+    [ "${lines[0]}" = "hash" ]
+    head=$(get_head_commit)
+    [ "${lines[1]}" = "$head" ]
+
+    dolt sql -q "INSERT INTO test VALUES (3)"
+    run dolt sql -q "CALL DOLT_COMMIT('-am', 'stats', '--row-stats')"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "--row-stats can only be used with DOLT_COMMIT_DETAILED()" ]] || false
+}
+
+@test "sql-commit: --reflog records head updates in dolt_reflog" {
+    before=$(get_head_commit)
+    dolt sql -q "CALL DOLT_COMMIT('-m', 'first', '--reflog')"
+    first=$(get_head_commit)
+    dolt sql -q "CALL DOLT_COMMIT('--amend', '-m', 'amended', '--reflog')"
+    amended=$(get_head_commit)
+
+    run dolt sql -r csv -q "SELECT ref, operation, from_hash, to_hash FROM dolt_reflog"
+    [ $status -eq 0 ]
+    [ "${#lines[@]}" -eq 3 ]
+    [ "${lines[1]}" = "refs/heads/main,commit (amend),$first,$amended" ]
+    [ "${lines[2]}" = "refs/heads/main,commit,$before,$first" ]
+
+    # commits without --reflog are not recorded
+    dolt sql -q "CALL DOLT_COMMIT('--allow-empty', '-m', 'unlogged')"
+    run dolt sql -r csv -q "SELECT count(*) FROM dolt_reflog"
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "2" ]
+}
+
+@test "sql-commit: --reflog doesn't fail the commit when the reflog can't be written" {
+    # a directory in place of the reflog file makes appending to it fail
+    mkdir .dolt/reflog
+    before=$(get_head_commit)
+    run dolt sql -q "CALL DOLT_COMMIT('--allow-empty', '-m', 'unrecorded', '--reflog')"
+    [ $status -eq 0 ]
+    [ "$(get_head_commit)" != "$before" ]
+
+    run dolt log -n 1
+    [[ "$output" =~ "unrecorded" ]] || false
+}
+
+@test "sql-commit: --amend-metadata-only keeps the tree and leaves changes uncommitted" {
+    dolt sql -q "CALL DOLT_COMMIT('-am', 'tpyo in message')"
+    dolt sql -q "INSERT INTO test VALUES (10)"
+    dolt add test
+    dolt sql -q "INSERT INTO test VALUES (11)"
+
+    run dolt sql -q "CALL DOLT_COMMIT('--amend-metadata-only', '-m', 'typo in message')"
+    [ $status -eq 0 ]
+
+    run dolt log -n 1
+    [ $status -eq 0 ]
+    [[ "$output" =~ "typo in message" ]] || false
+
+    run dolt sql -r csv -q "SELECT count(*) FROM test AS OF 'HEAD' WHERE pk >= 10"
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "0" ]
+
+    run dolt status
+    [ $status -eq 0 ]
+    [[ "$output" =~ "Changes to be committed" ]] || false
+    [[ "$output" =~ "Changes not staged for commit" ]] || false
+
+    run dolt sql -q "CALL DOLT_COMMIT('-a', '--amend-metadata-only')"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "cannot stage tables with --amend-metadata-only" ]] || false
+}
+
+@test "sql-commit: multiple --message values are joined as paragraphs" {
+    run dolt sql -q "CALL DOLT_COMMIT('-m', 'subject line', '--message', 'body paragraph, with a comma')"
+    [ $status -eq 0 ]
+
+    run dolt sql -r csv -q "SELECT message FROM dolt_log LIMIT 1"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "subject line" ]] || false
+    [[ "$output" =~ "body paragraph, with a comma" ]] || false
+
+    run dolt log -n 1
+    [ $status -eq 0 ]
+    [[ "$output" =~ "subject line" ]] || false
+    [[ "$output" =~ "body paragraph, with a comma" ]] || false
+}
+
+@test "sql-commit: dolt_preview_commit returns the hash dolt_commit will create" {
+    run dolt sql -r csv -q "CALL DOLT_PREVIEW_COMMIT('-m', 'preview', '--author', 'John Doe <john@example.com>', '--date', '2023-01-01T00:00:00', '--reproducible')"
+    [ $status -eq 0 ]
+    preview="${lines[1]}"
+    [ "$(get_head_commit)" != "$preview" ]
+
+    dolt sql -q "CALL DOLT_COMMIT('-m', 'preview', '--author', 'John Doe <john@example.com>', '--date', '2023-01-01T00:00:00', '--reproducible')"
+    [ "$(get_head_commit)" = "$preview" ]
+
+    run dolt sql -q "CALL DOLT_PREVIEW_COMMIT('--allow-empty', '-m', 'preview', '--author', 'John Doe <john@example.com>')"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "--date is required" ]] || false
+
+    run dolt sql -q "CALL DOLT_PREVIEW_COMMIT('--allow-empty', '-m', 'preview', '--author', 'John Doe <john@example.com>', '--date', '2023-01-01T00:00:00')"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "--reproducible is required" ]] || false
+
+    run dolt sql -q "CALL DOLT_COMMIT('--allow-empty', '-m', 'preview', '--reproducible')"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "--reproducible requires --date or --date-epoch-nanos" ]] || false
+}
+
+@test "sql-commit: dolt_preview_commit applies the message cleanup and signoff that dolt_commit does" {
+    args="'--allow-empty', '-m', 'preview', '--signoff', '--author', 'John Doe <john@example.com>', '--date', '2023-01-01T00:00:00', '--reproducible'"
+    run dolt sql -r csv -q "CALL DOLT_PREVIEW_COMMIT($args)"
+    [ $status -eq 0 ]
+    preview="${lines[1]}"
+
+    dolt sql -q "CALL DOLT_COMMIT($args)"
+    [ "$(get_head_commit)" = "$preview" ]
+    run dolt log -n 1
+    [[ "$output" =~ "Signed-off-by: John Doe <john@example.com>" ]] || false
+}
+
+@test "sql-commit: DOLT_COMMIT --date without --reproducible stamps the commit with the time it's written" {
+    run dolt sql -r csv -q "CALL DOLT_PREVIEW_COMMIT('-m', 'dated', '--author', 'John Doe <john@example.com>', '--date', '2023-01-01T00:00:00', '--reproducible')"
+    [ $status -eq 0 ]
+    preview="${lines[1]}"
+
+    dolt sql -q "CALL DOLT_COMMIT('-m', 'dated', '--author', 'John Doe <john@example.com>', '--date', '2023-01-01T00:00:00')"
+    [ "$(get_head_commit)" != "$preview" ]
+    run dolt log -n 1
+    [[ "$output" =~ "2023" ]] || false
+}
+
+@test "sql-commit: @@dolt_author is used as the author when --author isn't given" {
+    dolt sql <<SQL
+SET @@dolt_author = 'Jane Doe <jane@example.com>';
+CALL DOLT_COMMIT('-m', 'session author');
+SQL
+    run dolt log -n 1
+    [ $status -eq 0 ]
+    [[ "$output" =~ "Jane Doe <jane@example.com>" ]] || false
+
+    dolt sql <<SQL
+SET @@dolt_author = 'Jane Doe <jane@example.com>';
+CALL DOLT_COMMIT('--allow-empty', '-m', 'explicit author', '--author', 'John Doe <john@example.com>');
+SQL
+    run dolt log -n 1
+    [ $status -eq 0 ]
+    [[ "$output" =~ "John Doe <john@example.com>" ]] || false
+
+    run dolt sql -q "SET @@dolt_author = 'not an author'; CALL DOLT_COMMIT('--allow-empty', '-m', 'bad author');"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "invalid value for @@dolt_author" ]] || false
+}
+
+@test "sql-commit: commit rate limit rejects or warns about excessive commits" {
+    run dolt sql << SQL
+SET @@GLOBAL.dolt_repo_$$_commit_rate_limit = 2;
+CALL DOLT_COMMIT('--allow-empty', '-m', 'one');
+CALL DOLT_COMMIT('--allow-empty', '-m', 'two');
+CALL DOLT_COMMIT('--allow-empty', '-m', 'three');
+SQL
+    [ $status -eq 1 ]
+    [[ "$output" =~ "commit rate limit exceeded" ]] || false
+
+    run dolt sql << SQL
+SET @@GLOBAL.dolt_repo_$$_commit_rate_limit = 1;
+SET @@GLOBAL.dolt_commit_rate_limit_action = 'warn';
+CALL DOLT_COMMIT('--allow-empty', '-m', 'one');
+CALL DOLT_COMMIT('--allow-empty', '-m', 'two');
+SHOW WARNINGS;
+SQL
+    [ $status -eq 0 ]
+    [[ "$output" =~ "commit rate limit exceeded" ]] || false
+}
+
+@test "sql-commit: commits past the auto gc threshold schedule a garbage collection" {
+    run dolt sql << SQL
+SET @@GLOBAL.dolt_repo_$$_auto_gc_commit_threshold = 2;
+INSERT INTO test VALUES (10);
+CALL DOLT_COMMIT('-am', 'one');
+INSERT INTO test VALUES (11);
+CALL DOLT_COMMIT('-am', 'two');
+INSERT INTO test VALUES (12);
+CALL DOLT_COMMIT('-am', 'three');
+SQL
+    [ $status -eq 0 ]
+
+    run dolt sql -q "SELECT count(*) FROM test WHERE pk >= 10" -r csv
+    [ $status -eq 0 ]
+    [[ "$output" =~ "3" ]] || false
+
+    run dolt log -n 1
+    [ $status -eq 0 ]
+    [[ "$output" =~ "three" ]] || false
+}
+
+@test "sql-commit: DOLT_COMMIT --signoff adds a Signed-off-by trailer" {
+    run dolt sql -q "CALL DOLT_COMMIT('-s', '-m', 'add test', '--author', 'John Doe <john@doe.com>')"
+    [ $status -eq 0 ]
+
+    run dolt sql -r csv -q "SELECT message FROM dolt_log LIMIT 1"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "Signed-off-by: John Doe <john@doe.com>" ]] || false
+
+    run dolt log -n 1
+    [ $status -eq 0 ]
+    [[ "$output" =~ "Signed-off-by: John Doe <john@doe.com>" ]] || false
+}
+
+@test "sql-commit: DOLT_COMMIT --working-set commits another branch's working set" {
+    dolt commit -m "add test"
+    dolt branch feature
+    dolt sql << SQL
+CALL DOLT_CHECKOUT('feature');
+INSERT INTO test VALUES (10);
+CALL DOLT_ADD('.');
+SQL
+
+    run dolt sql -q "CALL DOLT_COMMIT('--working-set', 'heads/feature', '-m', 'commit feature')"
+    [ $status -eq 0 ]
+
+    run dolt log feature -n 1
+    [ $status -eq 0 ]
+    [[ "$output" =~ "commit feature" ]] || false
+
+    run dolt log main -n 1
+    [ $status -eq 0 ]
+    [[ ! "$output" =~ "commit feature" ]] || false
+
+    run dolt branch --show-current
+    [ $status -eq 0 ]
+    [ "$output" = "main" ]
+
+    run dolt sql -r csv -q "CALL DOLT_CHECKOUT('feature'); SELECT count(*) FROM dolt_status;"
+    [ $status -eq 0 ]
+    [[ "${lines[-1]}" = "0" ]] || false
+
+    run dolt sql -q "CALL DOLT_COMMIT('--working-set', 'heads/main', '--allow-empty', '-m', 'current')"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "checked out in this session" ]] || false
+
+    run dolt sql -q "CALL DOLT_COMMIT('--working-set', 'heads/feature', '--amend', '-m', 'amend')"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "--amend cannot be used with --working-set" ]] || false
+}
+
+@test "sql-commit: DOLT_TRY_COMMIT reports expected failures as a result row" {
+    run dolt sql -r csv -q "CALL DOLT_TRY_COMMIT('-m', 'add test')"
+    [ $status -eq 0 ]
+    [[ "${lines[1]}" =~ ",committed," ]] || false
+
+    run dolt sql -r csv -q "CALL DOLT_TRY_COMMIT('-m', 'nothing')"
+    [ $status -eq 0 ]
+    [[ "${lines[1]}" =~ ",nothing_to_commit," ]] || false
+
+    run dolt sql -r csv -q "CALL DOLT_TRY_COMMIT('--skip-empty', '-m', 'nothing')"
+    [ $status -eq 0 ]
+    [[ "${lines[1]}" =~ ",skipped,nothing to commit" ]] || false
+
+    dolt sql -q "INSERT INTO test VALUES (10)"
+    run dolt sql -r csv -q "CALL DOLT_TRY_COMMIT('-a')"
+    [ $status -eq 0 ]
+    [[ "${lines[1]}" =~ ",empty_message,Must provide commit message." ]] || false
+
+    run dolt sql -q "CALL DOLT_TRY_COMMIT('--not-a-flag')"
+    [ $status -eq 1 ]
+}
+
+@test "sql-commit: DOLT_COMMIT --meta attaches metadata to the commit" {
+    run dolt sql -q "CALL DOLT_COMMIT('-m', 'Commit1', '--meta', 'ticket=DOLT-123', '--meta', 'build=42')"
+    [ $status -eq 0 ]
+
+    run dolt sql -r csv -q "SELECT JSON_UNQUOTE(JSON_EXTRACT(metadata, '\$.ticket')), JSON_UNQUOTE(JSON_EXTRACT(metadata, '\$.build')) FROM dolt_log LIMIT 1"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "DOLT-123,42" ]] || false
+
+    run dolt sql -r csv -q "SELECT COUNT(*) FROM dolt_log WHERE metadata IS NULL"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "1" ]] || false
+
+    # amending keeps the metadata unless new metadata is given
+    run dolt sql -q "CALL DOLT_COMMIT('--amend', '-m', 'Amended')"
+    [ $status -eq 0 ]
+    run dolt sql -r csv -q "SELECT JSON_EXTRACT(metadata, '\$.ticket') FROM dolt_log LIMIT 1"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "DOLT-123" ]] || false
+
+    run dolt sql -q "CALL DOLT_COMMIT('--allow-empty', '-m', 'bad', '--meta', 'no-value')"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "expected key=value" ]] || false
+
+    run dolt sql -q "CALL DOLT_COMMIT('--allow-empty', '-m', 'bad', '--meta', 'bad key=value')"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "invalid character" ]] || false
+}
+
+@test "sql-commit: DOLT_COMMIT normalizes line endings in the message" {
+    dolt sql -q "CALL DOLT_COMMIT('-m', 'Commit1\r\n\r\ndetails')"
+
+    run dolt sql -r csv -q "SELECT COUNT(*) FROM dolt_log WHERE message = 'Commit1\n\ndetails'"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "1" ]] || false
+
+    dolt sql -q "CALL DOLT_COMMIT('--allow-empty', '--keep-line-endings', '-m', 'Commit2\r\ndetails')"
+    run dolt sql -r csv -q "SELECT COUNT(*) FROM dolt_log WHERE message = 'Commit2\r\ndetails'"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "1" ]] || false
+}
+
+@test "sql-commit: DOLT_COMMIT --retry" {
+    run dolt sql -q "CALL DOLT_COMMIT('--retry', '3', '-m', 'Commit1')"
+    [ $status -eq 0 ]
+
+    run dolt log -n 1
+    [ $status -eq 0 ]
+    [[ "$output" =~ "Commit1" ]] || false
+
+    run dolt sql -q "CALL DOLT_COMMIT('--retry', '-1', '--allow-empty', '-m', 'bad')"
+    [ $status -eq 1 ]
+
+    run dolt sql <<SQL
+SET autocommit = 0;
+INSERT INTO test VALUES (3);
+CALL DOLT_COMMIT('-a', '--retry', '3', '-m', 'Commit2');
+SQL
+    [ $status -eq 1 ]
+    [[ "$output" =~ "--retry cannot be used in a transaction with uncommitted changes" ]] || false
+
+    run dolt commit --allow-empty --retry 3 -m "Commit3"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "--retry is only supported by DOLT_COMMIT()" ]] || false
+}
+
+@test "sql-commit: commit validators reject commits which break the configured policy" {
+    run dolt sql << SQL
+SET @@GLOBAL.dolt_repo_$$_commit_validators = 'conventional_commits=feat|fix, max_subject_length=30';
+CALL DOLT_COMMIT('--allow-empty', '-m', 'added a table');
+SQL
+    [ $status -eq 1 ]
+    [[ "$output" =~ "commit rejected by validator conventional_commits" ]] || false
+
+    run dolt sql << SQL
+SET @@GLOBAL.dolt_repo_$$_commit_validators = 'conventional_commits=feat|fix, max_subject_length=30';
+CALL DOLT_COMMIT('--allow-empty', '-m', 'chore: tidy up');
+SQL
+    [ $status -eq 1 ]
+    [[ "$output" =~ "commit type 'chore' isn't one of feat, fix" ]] || false
+
+    run dolt sql << SQL
+SET @@GLOBAL.dolt_repo_$$_commit_validators = 'conventional_commits=feat|fix, max_subject_length=30';
+CALL DOLT_COMMIT('--allow-empty', '-m', 'feat: a subject which is much too long to allow');
+SQL
+    [ $status -eq 1 ]
+    [[ "$output" =~ "commit rejected by validator max_subject_length" ]] || false
+
+    run dolt sql << SQL
+SET @@GLOBAL.dolt_repo_$$_commit_validators = 'require_issue_key=PROJ';
+CALL DOLT_COMMIT('--allow-empty', '-m', 'no key', '--meta', 'ticket=PROJ-42');
+CALL DOLT_TRY_COMMIT('--allow-empty', '-m', 'still no key');
+SQL
+    [ $status -eq 0 ]
+    [[ "$output" =~ "validation_failed" ]] || false
+
+    run dolt sql -q "SET @@GLOBAL.dolt_repo_$$_commit_validators = 'no_such_validator'; CALL DOLT_COMMIT('--allow-empty', '-m', 'x');"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "unknown commit validator 'no_such_validator'" ]] || false
+
+    run dolt log -n 1
+    [[ "$output" =~ "no key" ]] || false
+}
+
+@test "sql-commit: DOLT_COMMIT --tree commits the given root value" {
+    dolt sql -q "CALL DOLT_COMMIT('-m', 'create test')"
+    dolt sql -q "INSERT INTO test VALUES (10), (11)"
+    tree=$(dolt sql -r csv -q "SELECT @@dolt_repo_$$_working" | tail -n 1)
+    dolt reset --hard
+
+    run dolt sql -q "CALL DOLT_COMMIT('--tree', '$tree', '-m', 'from tree')"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "--tree requires --force" ]] || false
+
+    dolt sql -q "INSERT INTO test VALUES (12)"
+    run dolt sql -q "CALL DOLT_COMMIT('--force', '--tree', '$tree', '-m', 'from tree')"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "cannot be used with staged or working changes" ]] || false
+    dolt reset --hard
+
+    run dolt sql -q "CALL DOLT_COMMIT('--force', '--tree', '$tree', '-m', 'from tree')"
+    [ $status -eq 0 ]
+
+    run dolt sql -r csv -q "SELECT COUNT(*) FROM test AS OF 'HEAD' WHERE pk >= 10"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "2" ]] || false
+
+    run dolt status
+    [[ "$output" =~ "nothing to commit, working tree clean" ]] || false
+}
+
+@test "sql-commit: DOLT_COMMIT_DETAILED returns the hash, whether the commit was skipped and the tables committed" {
+    run dolt sql -r csv -q "CALL DOLT_COMMIT_DETAILED('-m', 'add test')"
+    [ $status -eq 0 ]
+    [[ "${lines[0]}" = "commit_hash,skipped,tables_committed" ]] || false
+    [[ "${lines[1]}" =~ ,1$ ]] || false
+    head=$(dolt log -n 1 --oneline | cut -d ' ' -f 1 | sed 's/\x1b\[[0-9;]*m//g')
+    [[ "${lines[1]}" =~ "$head" ]] || false
+
+    dolt sql -q "INSERT INTO test VALUES (10)"
+    dolt sql -q "CREATE TABLE test2 (pk int primary key)"
+    run dolt sql -r csv -q "CALL DOLT_COMMIT_DETAILED('-Am', 'two tables')"
+    [ $status -eq 0 ]
+    [[ "${lines[1]}" =~ ,2$ ]] || false
+
+    run dolt sql -r csv -q "CALL DOLT_COMMIT_DETAILED('--skip-empty', '-m', 'nothing')"
+    [ $status -eq 0 ]
+    [[ "${lines[1]}" =~ ^,.*,0$ ]] || false
+
+    run dolt sql -q "CALL DOLT_COMMIT_DETAILED('-m', 'nothing')"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "nothing to commit" ]] || false
+}
+
+@test "sql-commit: DOLT_COMMIT --max-subject-length rejects long subjects" {
+    run dolt sql -q "CALL DOLT_COMMIT('--max-subject-length', '10', '-m', 'a subject which is too long')"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "commit message subject is 27 characters long, longer than the maximum of 10 set by --max-subject-length" ]] || false
+
+    run dolt sql -q "CALL DOLT_COMMIT('--max-subject-length', '10', '-m', 'short', '-m', 'a body paragraph which can be as long as it likes')"
+    [ $status -eq 0 ]
+
+    dolt sql -q "INSERT INTO test VALUES (10)"
+    run dolt sql <<SQL
+SET @@dolt_commit_max_subject_length = 5;
+CALL DOLT_COMMIT('-am', 'too long');
+SQL
+    [ $status -eq 1 ]
+    [[ "$output" =~ "set by @@dolt_commit_max_subject_length" ]] || false
+
+    run dolt sql <<SQL
+SET @@dolt_commit_max_subject_length = 5;
+CALL DOLT_COMMIT('--max-subject-length', '0', '-am', 'no limit');
+SQL
+    [ $status -eq 0 ]
+
+    run dolt commit --max-subject-length 10 -m "cli"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "only supported by DOLT_COMMIT()" ]] || false
+}
+
+@test "sql-commit: @@dolt_commit_record_session records the session in the commit metadata" {
+    dolt sql -q "CALL DOLT_COMMIT('-m', 'not recorded')"
+    run dolt sql -r csv -q "SELECT COUNT(*) FROM dolt_log WHERE metadata IS NULL"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "2" ]] || false
+
+    run dolt sql -r csv <<SQL
+SET GLOBAL dolt_commit_record_session = 1;
+CALL DOLT_COMMIT('--allow-empty', '-m', 'recorded', '--meta', 'session_id=forged', '--meta', 'ticket=DOLT-1');
+SELECT IF(JSON_UNQUOTE(JSON_EXTRACT(metadata, '\$.session_id')) = CONNECTION_ID(), 'same', 'different'), JSON_UNQUOTE(JSON_EXTRACT(metadata, '\$.ticket')) FROM dolt_log LIMIT 1;
+SQL
+    [ $status -eq 0 ]
+    [[ "$output" =~ "same,DOLT-1" ]] || false
+}
+
+@test "sql-commit: DOLT_COMMIT --cleanup=scissors removes everything after the scissors line" {
+    run dolt sql -q "CALL DOLT_COMMIT('--cleanup', 'scissors', '-m', 'add test\n\n# ------------------------ >8 ------------------------\ndiff --dolt a/test b/test')"
+    [ $status -eq 0 ]
+
+    run dolt log -n 1
+    [ $status -eq 0 ]
+    [[ "$output" =~ "add test" ]] || false
+    [[ ! "$output" =~ ">8" ]] || false
+    [[ ! "$output" =~ "diff --dolt" ]] || false
+
+    run dolt sql -q "CALL DOLT_COMMIT('--allow-empty', '--cleanup', 'strip', '-m', 'bad')"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "invalid --cleanup mode 'strip'" ]] || false
+}
+
+@test "sql-commit: DOLT_COMMIT --max-tables rejects commits changing too many tables" {
+    dolt sql -q "CREATE TABLE test2 (pk int primary key)"
+    run dolt sql -q "CALL DOLT_COMMIT('-Am', 'two tables', '--max-tables', '1')"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "commit changes 2 tables, more than the maximum of 1 set by --max-tables" ]] || false
+
+    run dolt sql <<SQL
+SET @@GLOBAL.dolt_repo_$$_commit_max_tables = 1;
+CALL DOLT_COMMIT('-Am', 'two tables');
+SQL
+    [ $status -eq 1 ]
+    [[ "$output" =~ "set by @@dolt_repo_$$_commit_max_tables" ]] || false
+
+    run dolt sql -q "CALL DOLT_COMMIT('-Am', 'two tables', '--max-tables', '2')"
+    [ $status -eq 0 ]
+
+    run dolt sql -r csv -q "CALL DOLT_TRY_COMMIT('--allow-empty', '-m', 'empty', '--max-tables', '1')"
+    [ $status -eq 0 ]
+    [[ "${lines[1]}" =~ ",committed," ]] || false
+}
+
+@test "sql-commit: DOLT_COMMIT records schema_only in the metadata of commits which only change schemas" {
+    dolt sql -q "CALL DOLT_COMMIT('-m', 'add test with rows')"
+
+    dolt sql -q "CREATE TABLE test2 (pk int primary key)"
+    dolt sql -q "ALTER TABLE test2 ADD COLUMN c1 int"
+    dolt sql -q "CALL DOLT_COMMIT('-Am', 'schema only')"
+
+    dolt sql -q "INSERT INTO test2 VALUES (1)"
+    dolt sql -q "CALL DOLT_COMMIT('-am', 'data')"
+
+    run dolt sql -r csv -q "SELECT message, JSON_UNQUOTE(JSON_EXTRACT(metadata, '\$.schema_only')) FROM dolt_log LIMIT 3"
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "data," ]
+    [ "${lines[2]}" = "schema only,true" ]
+    [ "${lines[3]}" = "add test with rows," ]
+
+    # amending a schema only commit with data changes clears the flag
+    dolt sql -q "CREATE TABLE test3 (pk int primary key)"
+    dolt sql -q "CALL DOLT_COMMIT('-Am', 'schema only')"
+    dolt sql -q "INSERT INTO test3 VALUES (1)"
+    dolt sql -q "CALL DOLT_COMMIT('-a', '--amend', '-m', 'schema and data')"
+    run dolt sql -r csv -q "SELECT metadata IS NULL FROM dolt_log LIMIT 1"
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "true" ]
+}
+
+@test "sql-commit: @@<db>_commit_log_table logs each commit to a table" {
+    dolt sql -q "CREATE TABLE commit_log (commit_hash varchar(32) primary key, parents text, branch varchar(100), author varchar(100), tables text, note text)"
+    dolt sql -q "INSERT INTO dolt_ignore VALUES ('commit_log', true)"
+
+    run dolt sql -r csv <<SQL
+SET @@GLOBAL.dolt_repo_$$_commit_log_table = 'commit_log';
+CALL DOLT_COMMIT('-m', 'add test');
+SELECT commit_hash = (SELECT commit_hash FROM dolt_log LIMIT 1), parents = (SELECT parent_hash FROM dolt_commit_ancestors WHERE commit_hash = (SELECT commit_hash FROM dolt_log LIMIT 1)), branch, author, tables, note FROM commit_log;
+SQL
+    [ $status -eq 0 ]
+    [[ "$output" =~ "true,true,main,Bats Tests,test," ]] || false
+
+    # the log table is ignored, so it's never committed
+    run dolt status
+    [ $status -eq 0 ]
+    [[ ! "$output" =~ "commit_log" ]] || false
+
+    run dolt sql -q "SET @@GLOBAL.dolt_repo_$$_commit_log_table = 'no_such_table'; CALL DOLT_COMMIT('--allow-empty', '-m', 'x');"
+    [ $status -ne 0 ]
+    [[ "$output" =~ "commit log table no_such_table, set by @@dolt_repo_$$_commit_log_table, not found" ]] || false
+}
+
+@test "sql-commit: @@<db>_protected_branches rejects direct commits to protected branches" {
+    run dolt sql <<SQL
+SET @@GLOBAL.dolt_repo_$$_protected_branches = 'release/*, main';
+CALL DOLT_COMMIT('-m', 'direct commit');
+SQL
+    [ $status -ne 0 ]
+    [[ "$output" =~ "branch main is protected by @@dolt_repo_$$_protected_branches; use a pull request to change it" ]] || false
+
+    # other branches can still be committed to
+    run dolt sql <<SQL
+SET @@GLOBAL.dolt_repo_$$_protected_branches = 'release/*, main';
+CALL DOLT_CHECKOUT('-b', 'feature');
+CALL DOLT_COMMIT('-m', 'feature commit');
+SQL
+    [ $status -eq 0 ]
+
+    # admins of the branch may commit to it directly
+    dolt checkout main
+    dolt sql -q "INSERT INTO dolt_branch_control VALUES ('%', 'main', 'root', '%', 'admin')"
+    run dolt sql <<SQL
+SET @@GLOBAL.dolt_repo_$$_protected_branches = 'release/*, main';
+CALL DOLT_COMMIT('-m', 'admin commit');
+SQL
+    [ $status -eq 0 ]
+}
+
+@test "sql-commit: DOLT_COMMIT reports every invalid argument at once" {
+    run dolt sql -q "CALL DOLT_COMMIT('-m', NULL, '--author', CAST('x' AS BINARY))"
+    [ $status -ne 0 ]
+    [[ "$output" =~ "invalid argument 2: arguments can't be NULL" ]] || false
+    [[ "$output" =~ "invalid argument 4: values of type" ]] || false
+
+    run dolt sql -q "CALL DOLT_COMMIT('-m', NULL)"
+    [ $status -ne 0 ]
+    [[ "$output" =~ "invalid argument 2: arguments can't be NULL" ]] || false
+}
+
+@test "sql-commit: DOLT_COMMIT --timezone records the commit's time zone" {
+    dolt sql -q "CALL DOLT_COMMIT('-m', 'in india', '--date', '2023-06-07T12:00:00', '--timezone', '+05:30')"
+
+    run dolt log -n 1
+    [ $status -eq 0 ]
+    [[ "$output" =~ "Date:  Wed Jun 07 12:00:00 +0530 2023" ]] || false
+
+    run dolt sql -r csv -q "SELECT date, JSON_UNQUOTE(JSON_EXTRACT(metadata, '\$.dolt_timezone')) FROM dolt_log LIMIT 1"
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "2023-06-07 06:30:00,+05:30" ]
+
+    dolt sql -q "CALL DOLT_COMMIT('--allow-empty', '-m', 'in new york', '--date', '2023-01-15T09:00:00', '--timezone', 'America/New_York')"
+    run dolt log -n 1
+    [ $status -eq 0 ]
+    [[ "$output" =~ "Date:  Sun Jan 15 09:00:00 -0500 2023" ]] || false
+
+    run dolt sql -q "CALL DOLT_COMMIT('--allow-empty', '-m', 'x', '--timezone', 'Mars/Olympus_Mons')"
+    [ $status -ne 0 ]
+    [[ "$output" =~ "unknown time zone 'Mars/Olympus_Mons'" ]] || false
+}
+
+@test "sql-commit: DOLT_COMMIT --meta can't set the metadata keys recorded by dolt" {
+    run dolt sql -q "CALL DOLT_COMMIT('-m', 'spoofed', '--meta', 'dolt_timezone=+05:30')"
+    [ $status -ne 0 ]
+    [[ "$output" =~ "commit metadata key 'dolt_timezone' is reserved" ]] || false
+
+    run dolt sql -q "CALL DOLT_COMMIT('-m', 'spoofed', '--meta', 'dolt_epoch_nanos=1')"
+    [ $status -ne 0 ]
+    [[ "$output" =~ "commit metadata key 'dolt_epoch_nanos' is reserved" ]] || false
+
+    # a key without the prefix is just the user's own metadata
+    dolt sql -q "CALL DOLT_COMMIT('-m', 'utc', '--date', '2023-06-07T12:00:00', '--timezone', 'UTC', '--meta', 'timezone=+05:30')"
+    run dolt log -n 1
+    [ $status -eq 0 ]
+    [[ "$output" =~ "Date:  Wed Jun 07 12:00:00 +0000 2023" ]] || false
+}
+
+@test "sql-commit: DOLT_COMMIT --violations-resolved commits only the resolved tables" {
+    dolt sql <<SQL
+CREATE TABLE parent (pk int PRIMARY KEY, v1 int, INDEX (v1));
+CREATE TABLE child (pk int PRIMARY KEY, v1 int, CONSTRAINT fk_v1 FOREIGN KEY (v1) REFERENCES parent (v1));
+CREATE TABLE other (pk int PRIMARY KEY);
+INSERT INTO parent VALUES (1, 1);
+SET foreign_key_checks=0;
+INSERT INTO child VALUES (1, 1), (2, 2);
+SET foreign_key_checks=1;
+SQL
+    run dolt constraints verify child
+    [ $status -ne 0 ]
+    dolt add -A
+    dolt commit --force -m "commit with violations"
+
+    run dolt sql <<SQL
+INSERT INTO other VALUES (1);
+CALL DOLT_ADD('other');
+DELETE FROM child WHERE pk = 2;
+DELETE FROM dolt_constraint_violations_child;
+CALL DOLT_COMMIT('--violations-resolved', '-m', 'resolve violations');
+SQL
+    [ $status -eq 0 ]
+
+    run dolt sql -r csv -q "SELECT COUNT(*) FROM dolt_constraint_violations_child AS OF 'HEAD'"
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "0" ]
+
+    run dolt sql -r csv -q "SELECT to_table_name FROM dolt_diff_summary('HEAD~1', 'HEAD')"
+    [ $status -eq 0 ]
+    [ "${#lines[@]}" -eq 2 ]
+    [ "${lines[1]}" = "child" ]
+
+    # the unrelated change is still staged
+    run dolt status
+    [ $status -eq 0 ]
+    [[ "$output" =~ "Changes to be committed" ]] || false
+    [[ "$output" =~ "modified:         other" ]] || false
+    [[ ! "$output" =~ "child" ]] || false
+
+    run dolt sql -q "CALL DOLT_COMMIT('--violations-resolved', '-a', '-m', 'x')"
+    [ $status -ne 0 ]
+    [[ "$output" =~ "--all cannot be used with --violations-resolved" ]] || false
+}
+
+@test "sql-commit: @@<db>_pre_commit_procedure can reject a commit" {
+    dolt sql <<SQL
+CREATE TABLE accounts (id int PRIMARY KEY, balance int);
+DELIMITER //
+CREATE PROCEDURE check_balances(branch varchar(255), tables text)
+BEGIN
+    IF FIND_IN_SET('accounts', tables) > 0 AND (SELECT COUNT(*) FROM accounts WHERE balance < 0) > 0 THEN
+        SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'negative balance';
+    END IF;
+END//
+DELIMITER ;
+CALL DOLT_COMMIT('-Am', 'create accounts');
+SQL
+
+    run dolt sql <<SQL
+SET @@GLOBAL.dolt_repo_$$_pre_commit_procedure = 'check_balances';
+INSERT INTO accounts VALUES (1, -10);
+CALL DOLT_COMMIT('-am', 'overdrawn');
+SQL
+    [ $status -ne 0 ]
+    [[ "$output" =~ "commit rejected by pre-commit procedure check_balances: negative balance" ]] || false
+
+    run dolt sql <<SQL
+SET @@GLOBAL.dolt_repo_$$_pre_commit_procedure = 'check_balances';
+INSERT INTO accounts VALUES (1, 10);
+CALL DOLT_COMMIT('-am', 'in credit');
+SQL
+    [ $status -eq 0 ]
+
+    run dolt log -n 1
+    [ $status -eq 0 ]
+    [[ "$output" =~ "in credit" ]] || false
+}
+
+@test "sql-commit: DOLT_SQUASH squashes the last commits into one" {
+    dolt commit -m "create test"
+    dolt sql -q "INSERT INTO test VALUES (10)"
+    dolt commit -am "add 10"
+    dolt sql -q "INSERT INTO test VALUES (11)"
+    dolt commit -am "add 11"
+    dolt sql -q "INSERT INTO test VALUES (12)"
+    dolt commit -am "add 12"
+    dolt sql -q "INSERT INTO test VALUES (13)"
+    dolt add test
+
+    run dolt sql -q "CALL DOLT_SQUASH(3, 'add 10 to 12')"
+    [ $status -eq 0 ]
+
+    run dolt log --oneline -n 2
+    [ $status -eq 0 ]
+    [[ "${lines[0]}" =~ "add 10 to 12" ]] || false
+    [[ "${lines[1]}" =~ "create test" ]] || false
+
+    run dolt sql -r csv -q "SELECT count(*) FROM test AS OF 'HEAD' WHERE pk >= 10"
+    [ $status -eq 0 ]
+    [ "${lines[1]}" = "3" ]
+
+    # the staged change is still staged
+    run dolt status
+    [ $status -eq 0 ]
+    [[ "$output" =~ "Changes to be committed" ]] || false
+    [[ "$output" =~ "modified:         test" ]] || false
+
+    run dolt sql -q "CALL DOLT_SQUASH(100, 'too many')"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "cannot squash 100 commits" ]] || false
+
+    run dolt sql -q "CALL DOLT_SQUASH(0, 'none')"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "invalid number of commits to squash '0'" ]] || false
+}
+
+@test "sql-commit: DOLT_SQUASH refuses to squash merge commits" {
+    dolt commit -m "create test"
+    dolt checkout -b other
+    dolt sql -q "INSERT INTO test VALUES (10)"
+    dolt commit -am "add 10 on other"
+    dolt checkout main
+    dolt sql -q "INSERT INTO test VALUES (11)"
+    dolt commit -am "add 11 on main"
+    dolt merge other -m "merge other"
+
+    head=$(get_head_commit)
+    run dolt sql -q "CALL DOLT_SQUASH(2, 'squashed')"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "cannot squash merge commit" ]] || false
+
+    # the branch is left where it was
+    [ "$(get_head_commit)" = "$head" ]
+}
+
+@test "sql-commit: DOLT_COMMIT --message-line passes the message one line per argument" {
+    run dolt sql -q "CALL DOLT_COMMIT('--message-line', 'add rows', '--message-line', '', '--message-line', '- with a leading dash', '--message-line', '-m is not an option here')"
+    [ $status -eq 0 ]
+
+    run dolt sql -r csv -q "SELECT message FROM dolt_log LIMIT 1"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "add rows" ]] || false
+    [[ "$output" =~ "- with a leading dash" ]] || false
+    [[ "$output" =~ "-m is not an option here" ]] || false
+
+    run dolt sql -q "CALL DOLT_COMMIT('--allow-empty', '-m', 'subject', '--message-line', 'body')"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "cannot use both --message and --message-line" ]] || false
+
+    run dolt commit --allow-empty --message-line "subject"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "message-line" ]] || false
+}
+
+@test "sql-commit: DOLT_COMMIT --no-flush requires dolt_commit_allow_no_flush" {
+    run dolt sql -q "CALL DOLT_COMMIT('-am', 'no flush', '--no-flush')"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "--no-flush requires @@dolt_commit_allow_no_flush to be enabled" ]] || false
+
+    run dolt sql <<SQL
+SET @@GLOBAL.dolt_commit_allow_no_flush = 1;
+CALL DOLT_COMMIT('-am', 'no flush', '--no-flush');
+CALL DOLT_COMMIT('--allow-empty', '-m', 'flushed');
+SQL
+    [ $status -eq 0 ]
+
+    run dolt log -n 2
+    [ $status -eq 0 ]
+    [[ "$output" =~ "no flush" ]] || false
+    [[ "$output" =~ "flushed" ]] || false
+
+    run dolt commit --allow-empty -m "cli" --no-flush
+    [ $status -eq 1 ]
+    [[ "$output" =~ "no-flush" ]] || false
+}
+
+@test "sql-commit: DOLT_COMMIT_AUTHOR() reports the author of the session's commits" {
+    run dolt sql -r csv -q "SELECT JSON_UNQUOTE(JSON_EXTRACT(DOLT_COMMIT_AUTHOR(), '$.name')) AS name"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "root" ]] || false
+
+    run dolt sql -r csv <<SQL
+SET @@dolt_author = 'Jane Doe <jane@example.com>';
+SELECT JSON_UNQUOTE(JSON_EXTRACT(a, '$.name')) AS name, JSON_UNQUOTE(JSON_EXTRACT(a, '$.email')) AS email FROM (SELECT DOLT_COMMIT_AUTHOR() AS a) AS t;
+SQL
+    [ $status -eq 0 ]
+    [[ "$output" =~ "Jane Doe,jane@example.com" ]] || false
+
+    run dolt sql -q "SET @@dolt_author = 'not an author'; SELECT DOLT_COMMIT_AUTHOR();"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "invalid value for @@dolt_author" ]] || false
+}
+
+@test "sql-commit: DOLT_REWORD rewrites the message of an older commit" {
+    dolt commit -m "create test"
+    dolt sql -q "INSERT INTO test VALUES (10)"
+    dolt commit -am "add 10 wiht a typo"
+    dolt sql -q "INSERT INTO test VALUES (11)"
+    dolt commit -am "add 11"
+
+    run dolt sql -q "CALL DOLT_REWORD('HEAD~1', 'add 10', '--author', 'Jane Doe <jane@example.com>')"
+    [ $status -eq 0 ]
+
+    run dolt log
+    [ $status -eq 0 ]
+    [[ "$output" =~ "add 10" ]] || false
+    [[ ! "$output" =~ "wiht a typo" ]] || false
+    [[ "$output" =~ "add 11" ]] || false
+    [[ "$output" =~ "Jane Doe <jane@example.com>" ]] || false
+
+    run dolt sql -r csv -q "SELECT COUNT(*) FROM test"
+    [ $status -eq 0 ]
+    [[ "$output" =~ "5" ]] || false
+}
+
+@test "sql-commit: DOLT_REWORD refuses to rewrite commits shared with other branches unless forced" {
+    dolt commit -m "create test"
+    dolt branch other
+    dolt sql -q "INSERT INTO test VALUES (10)"
+    dolt commit -am "add 10"
+
+    head=$(get_head_commit)
+    run dolt sql -q "CALL DOLT_REWORD('HEAD~1', 'create the test table')"
+    [ $status -eq 1 ]
+    [[ "$output" =~ "is reachable from branch other" ]] || false
+    [ "$(get_head_commit)" = "$head" ]
+
+    run dolt sql -q "CALL DOLT_REWORD('HEAD~1', 'create the test table', '--force')"
+    [ $status -eq 0 ]
+    run dolt log
+    [[ "$output" =~ "create the test table" ]] || false
+
+    run dolt log other
+    [[ "$output" =~ "create test" ]] || false
+}
+
+@test "sql-commit: merge commits can be refused to keep history linear" {
+    dolt commit -m "create test"
+    dolt checkout -b other
+    dolt sql -q "INSERT INTO test VALUES (10)"
+    dolt commit -am "add 10 on other"
+    dolt checkout main
+    dolt sql -q "INSERT INTO test VALUES (11)"
+    dolt commit -am "add 11 on main"
+
+    run dolt sql <<SQL
+CALL DOLT_MERGE('other', '--no-commit');
+CALL DOLT_COMMIT('-m', 'merge other', '--no-merges');
+SQL
+    [ $status -ne 0 ]
+    [[ "$output" =~ "cannot create a merge commit on branch main, which must have a linear history because of --no-merges; rebase onto it instead" ]] || false
+
+    dolt sql -q "INSERT INTO dolt_branch_control VALUES ('%', 'main', 'root', '%', 'admin')"
+    run dolt sql <<SQL
+SET @@GLOBAL.dolt_repo_$$_protected_branches = 'main';
+SET @@GLOBAL.dolt_protected_branches_linear_history = 1;
+CALL DOLT_MERGE('other', '--no-commit');
+CALL DOLT_COMMIT('-m', 'merge other');
+SQL
+    [ $status -ne 0 ]
+    [[ "$output" =~ "because of @@dolt_protected_branches_linear_history" ]] || false
+
+    # without the policy, admins of a protected branch may still merge
+    run dolt sql <<SQL
+SET @@GLOBAL.dolt_repo_$$_protected_branches = 'main';
+CALL DOLT_MERGE('other', '--no-commit');
+CALL DOLT_COMMIT('-m', 'merge other');
+SQL
+    [ $status -eq 0 ]
+}
+
+@test "sql-commit: DOLT_COMMIT --date-epoch-nanos keeps the order of commits within a second" {
+    dolt sql -q "CALL DOLT_COMMIT('-Am', 'first', '--date-epoch-nanos', '1686139200123456789')"
+    dolt sql -q "INSERT INTO test VALUES (20)"
+    dolt sql -q "CALL DOLT_COMMIT('-am', 'second', '--date-epoch-nanos', '1686139200123999999')"
+
+    run dolt sql -r csv -q "SELECT message, JSON_UNQUOTE(JSON_EXTRACT(metadata, '\$.dolt_epoch_nanos')) FROM dolt_log ORDER BY date DESC LIMIT 2"
+    [ $status -eq 0 ]
+    [[ "${lines[1]}" = "second,1686139200123999999" ]] || false
+    [[ "${lines[2]}" = "first,1686139200123456789" ]] || false
+
+    run dolt sql -q "CALL DOLT_COMMIT('--allow-empty', '-m', 'both', '--date', '2023-01-01', '--date-epoch-nanos', '1')"
+    [ $status -ne 0 ]
+    [[ "$output" =~ "cannot use both --date and --date-epoch-nanos" ]] || false
+}
+
+@test "sql-commit: DOLT_COMMIT --keep commits without changing the branch or the working set" {
+    dolt sql -q "CALL DOLT_COMMIT('-m', 'create test')"
+    head=$(get_head_commit)
+    dolt sql -q "INSERT INTO test VALUES (3)"
+    dolt sql -q "CALL DOLT_ADD('test')"
+    dolt sql -q "INSERT INTO test VALUES (4)"
+
+    run dolt sql -r csv -q "CALL DOLT_COMMIT('--keep', '-m', 'checkpoint')"
+    [ $status -eq 0 ]
+    kept=${lines[1]}
+
+    # the branch and the staged and working changes are unchanged
+    [ "$(get_head_commit)" = "$head" ]
+    run dolt sql -r csv -q "SELECT COUNT(*) FROM dolt_status WHERE table_name = 'test' AND staged = 1"
+    [ "${lines[1]}" = "1" ]
+    run dolt sql -r csv -q "SELECT COUNT(*) FROM dolt_status WHERE table_name = 'test' AND staged = 0"
+    [ "${lines[1]}" = "1" ]
+    run dolt sql -r csv -q "SELECT COUNT(*) FROM test"
+    [ "${lines[1]}" = "5" ]
+
+    # the commit has the staged rows, and HEAD as its parent
+    run dolt sql -r csv -q "SELECT COUNT(*) FROM test AS OF '$kept'"
+    [ "${lines[1]}" = "4" ]
+    run dolt sql -r csv -q "SELECT parent_hash FROM dolt_commit_ancestors WHERE commit_hash = '$kept'"
+    [[ "$output" =~ "$head" ]] || false
+    run dolt log -n 1 "$kept"
+    [[ "$output" =~ "checkpoint" ]] || false
+
+    # tables staged with -a are committed, but not left staged
+    run dolt sql -r csv -q "CALL DOLT_COMMIT('--keep', '-am', 'checkpoint all')"
+    [ $status -eq 0 ]
+    kept=${lines[1]}
+    run dolt sql -r csv -q "SELECT COUNT(*) FROM test AS OF '$kept'"
+    [ "${lines[1]}" = "5" ]
+    run dolt sql -r csv -q "SELECT COUNT(*) FROM dolt_status WHERE staged = 0"
+    [ "${lines[1]}" = "1" ]
+    [ "$(get_head_commit)" = "$head" ]
+
+    run dolt sql -q "CALL DOLT_COMMIT('--keep', '--amend', '-m', 'amend')"
+    [ $status -ne 0 ]
+    [[ "$output" =~ "--amend cannot be used with --keep" ]] || false
+}
+
+@test "sql-commit: DOLT_COMMIT_DETAILED --keep with --row-stats counts the rows of the kept commit" {
+    dolt sql -q "CALL DOLT_COMMIT('-m', 'create test')"
+    dolt sql -q "INSERT INTO test VALUES (3),(4)"
+    dolt sql -q "CALL DOLT_ADD('test')"
+
+    run dolt sql -r csv -q "CALL DOLT_COMMIT_DETAILED('--keep', '-m', 'checkpoint', '--row-stats')"
+    [ $status -eq 0 ]
+    [[ "${lines[1]}" =~ ,2,0,0$ ]] || false
+
+    run dolt sql -r csv -q "CALL DOLT_COMMIT_DETAILED('--keep', '-m', 'checkpoint', '--exact-row-stats')"
+    [ $status -eq 0 ]
+    [[ "${lines[1]}" =~ ,2,0,0$ ]] || false
+}
+
+@test "sql-commit: DOLT_COMMIT with --no-partial fails if there are unstaged changes" {
+    dolt sql -q "INSERT INTO test VALUES (3)"
+    dolt sql -q "CREATE TABLE other (pk int PRIMARY KEY)"
+    head=$(get_head_commit)
+
+    run dolt sql -q "CALL DOLT_COMMIT('--no-partial', '-m', 'partial')"
+    [ $status -ne 0 ]
+    [[ "$output" =~ "the table(s) other, test have unstaged changes" ]] || false
+    [[ "$output" =~ "--no-partial" ]] || false
+    [ "$(get_head_commit)" = "$head" ]
+
+    # -a stages the modified table, but not the new one
+    run dolt sql -q "CALL DOLT_COMMIT('--no-partial', '-am', 'partial')"
+    [ $status -ne 0 ]
+    [[ "$output" =~ "the table(s) other have unstaged changes" ]] || false
+
+    # new tables ignored by dolt_ignore aren't counted, and -A doesn't stage them
+    dolt sql -q "INSERT INTO dolt_ignore VALUES ('other', true)"
+    run dolt sql -q "CALL DOLT_COMMIT('--no-partial', '-Am', 'whole working set')"
+    [ $status -eq 0 ]
+    [ "$(get_head_commit)" != "$head" ]
+
+    dolt sql -q "INSERT INTO test VALUES (4)"
+    run dolt commit --no-partial -m "partial"
+    [ $status -ne 0 ]
+    [[ "$output" =~ "the table(s) test have unstaged changes" ]] || false
+}
+
+@test "sql-commit: DOLT_COMMIT_EXPLAIN lists how the arguments are parsed without committing" {
+    head=$(get_head_commit)
+
+    run dolt sql -r csv -q "CALL DOLT_COMMIT_EXPLAIN('-am', 'first', '-m', 'second', '--author', 'John Doe <john@doe.com>')"
+    [ $status -eq 0 ]
+    [ "${lines[0]}" = "kind,name,value" ]
+    [ "${lines[1]}" = "arg,1,-am" ]
+    [ "${lines[2]}" = "arg,2,first" ]
+    [ "${lines[3]}" = "arg,3,-m" ]
+    [ "${lines[4]}" = "arg,4,second" ]
+    [ "${lines[5]}" = "arg,5,--author" ]
+    [ "${lines[6]}" = "arg,6,John Doe <john@doe.com>" ]
+    [ "${lines[7]}" = "option,message,first" ]
+    [ "${lines[8]}" = "option,message,second" ]
+    [ "${lines[9]}" = "option,author,John Doe <john@doe.com>" ]
+    [ "${lines[10]}" = "flag,all," ]
+    [ "${#lines[@]}" -eq 11 ]
+
+    # nothing is committed
+    [ "$(get_head_commit)" = "$head" ]
+
+    run dolt sql -q "CALL DOLT_COMMIT_EXPLAIN('--no-such-flag')"
+    [ $status -ne 0 ]
+    [[ "$output" =~ "no-such-flag" ]] || false
+}
@@ -1125,3 +1125,31 @@ get_head_commit() {
 get_working_hash() {
   dolt sql -q "select @@dolt_repo_$$_working" | sed -n 4p | sed -e 's/|//' -e 's/|//'  -e 's/ //'
 }
+
+@test "sql-merge: DOLT_MERGE_STATUS() reports merge artifacts" {
+    run dolt sql -r csv -q "SELECT JSON_EXTRACT(s, '$.is_merging'), JSON_EXTRACT(s, '$.has_conflicts') FROM (SELECT DOLT_MERGE_STATUS() AS s) AS t"
+    log_status_eq 0
+    [[ "${lines[1]}" = "false,false" ]] || false
+
+    run dolt sql -r csv << SQL
+CREATE TABLE one_pk (
+  pk1 BIGINT NOT NULL,
+  c1 BIGINT,
+  PRIMARY KEY (pk1)
+);
+CALL DOLT_ADD('.');
+call dolt_commit('-a', '-m', 'add tables');
+call dolt_checkout('-b', 'feature-branch');
+INSERT INTO one_pk VALUES (0,1);
+call dolt_commit('-a', '-m', 'changed feature branch');
+call dolt_checkout('main');
+INSERT INTO one_pk VALUES (0,0);
+call dolt_commit('-a', '-m', 'changed main');
+SET autocommit = off;
+call dolt_merge('feature-branch');
+SELECT JSON_EXTRACT(s, '$.is_merging'), JSON_EXTRACT(s, '$.has_conflicts'), JSON_UNQUOTE(JSON_EXTRACT(s, '$.data_conflict_tables[0]')) FROM (SELECT DOLT_MERGE_STATUS() AS s) AS t;
+call dolt_merge('--abort');
+SQL
+    log_status_eq 0
+    [[ "$output" =~ "true,true,one_pk" ]] || false
+}
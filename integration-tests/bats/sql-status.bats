@@ -48,8 +48,13 @@ teardown() {
     # Confirm table is now marked as renamed, test still staged
     run dolt sql -r csv -q "select * from dolt_status"
     [ "$status" -eq 0 ]
-    [[ "$output" =~ 'test,true,new table' ]] || false
-    [[ "$output" =~ 'test -> test2,false,renamed' ]] || false
+    [[ "$output" =~ 'test,true,new table,test,test' ]] || false
+    [[ "$output" =~ 'test -> test2,false,renamed,test,test2' ]] || false
+
+    run dolt sql -r csv -q "select from_table, to_table from dolt_status where status = 'renamed'"
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 2 ]
+    [ "${lines[1]}" = "test,test2" ]
 
     # Confirm table is now marked as staged
     dolt add test2
@@ -119,6 +119,35 @@ SQL
     [[ "$output" =~ "	both modified:    t" ]] || false
 }
 
+@test "status: unmerged tables are listed before the staged changes of a merge" {
+    dolt sql <<SQL
+CREATE TABLE t (pk int PRIMARY KEY, c0 int);
+INSERT INTO t VALUES (1,1);
+SQL
+    dolt add -A && dolt commit -m "created table t"
+    dolt checkout -b other
+    dolt sql -q "INSERT INTO t VALUES (2,12);"
+    dolt sql -q "CREATE TABLE u (pk int PRIMARY KEY);"
+    dolt add -A && dolt commit -m "added values and table u on branch other"
+    dolt checkout main
+    dolt sql -q "INSERT INTO t VALUES (2,2);"
+    dolt add -A && dolt commit -m "added values on branch main"
+    run dolt merge other
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "CONFLICT (content): Merge conflict in t" ]] || false
+
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "	both modified:    t" ]] || false
+    [[ "$output" =~ "	new table:        u" ]] || false
+    unmerged_line=$(echo "$output" | grep -n "Unmerged paths:" | cut -d: -f1)
+    conflict_line=$(echo "$output" | grep -n "both modified:    t" | cut -d: -f1)
+    staged_line=$(echo "$output" | grep -n "Changes to be committed:" | cut -d: -f1)
+    [ "$unmerged_line" -lt "$conflict_line" ]
+    [ "$conflict_line" -lt "$staged_line" ]
+    [[ ! "$output" =~ "Changes not staged for commit:" ]] || false
+}
+
 @test "status: renamed table" {
     dolt sql <<SQL
 CREATE TABLE test (pk int PRIMARY KEY);
@@ -137,6 +166,28 @@ SQL
     [[ "$output" =~ "renamed:          test -> quiz" ]] || false
 }
 
+@test "status: renamed and modified table is a single change" {
+    dolt sql <<SQL
+CREATE TABLE test (pk int PRIMARY KEY);
+SQL
+    dolt add test
+    dolt commit -m 'added table test'
+    dolt sql -q 'alter table test rename to quiz'
+    dolt sql -q 'insert into quiz values (1)'
+
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "renamed+modified: test -> quiz" ]] || false
+    [[ ! "$output" =~ "Untracked tables:" ]] || false
+    [[ ! "$output" =~ "deleted:" ]] || false
+
+    dolt add .
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "renamed+modified: test -> quiz" ]] || false
+    [[ ! "$output" =~ "Changes not staged for commit:" ]] || false
+}
+
 @test "status: unstaged changes after reset" {
     dolt sql <<SQL
 CREATE TABLE one (pk int PRIMARY KEY);
@@ -453,3 +504,779 @@ SQL
     mv .dolt/repo_state.backup .dolt/repo_state.json
     [ "$status" -eq 0 ]
 }
+
+@test "status: --type limits output to the given change types" {
+    dolt sql -q "CREATE TABLE t1 (pk int PRIMARY KEY)"
+    dolt sql -q "CREATE TABLE t2 (pk int PRIMARY KEY)"
+    dolt commit -Am "create tables"
+
+    dolt sql -q "INSERT INTO t1 VALUES (1)"
+    dolt sql -q "DROP TABLE t2"
+    dolt sql -q "CREATE TABLE t3 (pk int PRIMARY KEY)"
+
+    run dolt status --type=deleted
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "deleted:          t2" ]] || false
+    [[ ! "$output" =~ "t1" ]] || false
+    [[ ! "$output" =~ "t3" ]] || false
+
+    run dolt status --type=added,modified
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "modified:         t1" ]] || false
+    [[ "$output" =~ "new table:        t3" ]] || false
+    [[ ! "$output" =~ "t2" ]] || false
+
+    run dolt status --type=renamed
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "invalid value 'renamed' for --type" ]] || false
+}
+
+@test "status: --against compares the working set to the given commit" {
+    dolt sql -q "CREATE TABLE t1 (pk int PRIMARY KEY)"
+    dolt commit -Am "create t1"
+    dolt tag v1
+    dolt sql -q "CREATE TABLE t2 (pk int PRIMARY KEY)"
+    dolt commit -Am "create t2"
+
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "nothing to commit, working tree clean" ]] || false
+
+    run dolt status --against v1
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Comparing against v1" ]] || false
+    [[ "$output" =~ "Changes to be committed:" ]] || false
+    [[ "$output" =~ "new table:        t2" ]] || false
+    [[ ! "$output" =~ "t1" ]] || false
+
+    run dolt status --against doesnotexist
+    [ "$status" -eq 1 ]
+}
+
+@test "status: --show-hashes prints working set and root hashes" {
+    dolt sql -q "CREATE TABLE t (pk int primary key)"
+    head=$(get_head_commit)
+
+    run dolt status --show-hashes
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Working set:" ]] || false
+    [[ "$output" =~ "Working root:" ]] || false
+    [[ "$output" =~ "Staged root:" ]] || false
+    [[ "$output" =~ "HEAD commit:  $head" ]] || false
+
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "Working set:" ]] || false
+}
+
+@test "status: --abbrev shortens the hashes printed by --show-hashes" {
+    head=$(get_head_commit)
+
+    run dolt status --show-hashes --abbrev=10
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "HEAD commit:  ${head:0:10}" ]] || false
+    [[ ! "$output" =~ "$head" ]] || false
+
+    # hashes aren't abbreviated to fewer than 4 characters
+    run dolt status --show-hashes --abbrev=1
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "HEAD commit:  ${head:0:4}"$'\n' ]] || false
+}
+
+@test "status: --staged and --unstaged restrict output to one section" {
+    dolt sql -q "CREATE TABLE t1 (pk int PRIMARY KEY)"
+    dolt add t1
+    dolt sql -q "CREATE TABLE t2 (pk int PRIMARY KEY)"
+
+    run dolt status --staged
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "On branch main" ]] || false
+    [[ "$output" =~ "Changes to be committed:" ]] || false
+    [[ ! "$output" =~ "Untracked tables:" ]] || false
+    [[ ! "$output" =~ "t2" ]] || false
+
+    run dolt status --unstaged
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "On branch main" ]] || false
+    [[ ! "$output" =~ "Changes to be committed:" ]] || false
+    [[ "$output" =~ "Untracked tables:" ]] || false
+    [[ ! "$output" =~ "t1" ]] || false
+
+    run dolt status --staged --unstaged
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "cannot use both --staged and --unstaged" ]] || false
+}
+
+@test "status: --show-upstream-time shows the age of the upstream when behind" {
+    mkdir -p remotes/origin
+    dolt remote add origin file://./remotes/origin
+    dolt sql -q "CREATE TABLE t1 (pk int PRIMARY KEY)"
+    dolt commit -Am "create t1"
+    dolt push --set-upstream origin main
+    dolt sql -q "CREATE TABLE t2 (pk int PRIMARY KEY)"
+    dolt commit -Am "create t2"
+    dolt push origin main
+    dolt reset --hard HEAD~
+
+    run dolt status --show-upstream-time
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Your branch is behind 'origin/main' by 1 commit" ]] || false
+    [[ "$output" =~ "(upstream updated " ]] || false
+    [[ "$output" =~ " ago)" ]] || false
+
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "upstream updated" ]] || false
+}
+
+@test "status: --graph shows where the branch and its upstream diverge" {
+    mkdir -p remotes/origin
+    dolt remote add origin file://./remotes/origin
+    dolt sql -q "CREATE TABLE t1 (pk int PRIMARY KEY)"
+    dolt commit -Am "create t1"
+    dolt push --set-upstream origin main
+    dolt sql -q "CREATE TABLE t2 (pk int PRIMARY KEY)"
+    dolt commit -Am "create t2"
+    dolt push origin main
+    dolt reset --hard HEAD~
+    dolt sql -q "CREATE TABLE t3 (pk int PRIMARY KEY)"
+    dolt commit -Am "create t3"
+
+    run dolt status --graph
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "have diverged" ]] || false
+    [[ "$output" =~ "Pulling can't fast-forward your branch, and will create a merge commit." ]] || false
+    [[ "$output" =~ "* | " ]] || false
+    [[ "$output" =~ "(HEAD -> main) create t3" ]] || false
+    [[ "$output" =~ "| * " ]] || false
+    [[ "$output" =~ "(origin/main) create t2" ]] || false
+    [[ "$output" =~ "|/" ]] || false
+    [[ "$output" =~ "create t1" ]] || false
+
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "|/" ]] || false
+}
+
+@test "status: --since only counts upstream commits made within the window" {
+    mkdir -p remotes/origin
+    dolt remote add origin file://./remotes/origin
+    dolt sql -q "CREATE TABLE t1 (pk int PRIMARY KEY)"
+    dolt commit -Am "create t1"
+    dolt push --set-upstream origin main
+    dolt sql -q "CREATE TABLE t2 (pk int PRIMARY KEY)"
+    dolt commit -Am "create t2" --date "2020-01-01T00:00:00"
+    dolt sql -q "CREATE TABLE t3 (pk int PRIMARY KEY)"
+    dolt commit -Am "create t3"
+
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "ahead of 'origin/main' by 2 commits" ]] || false
+    [[ ! "$output" =~ "only counting commits" ]] || false
+
+    run dolt status --since 24h
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "ahead of 'origin/main' by 1 commit." ]] || false
+    [[ "$output" =~ "(only counting commits made since " ]] || false
+
+    run dolt status --since yesterday
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "invalid value 'yesterday' for --since" ]] || false
+}
+
+@test "status: --since keeps counting past an older commit from a merged branch" {
+    mkdir -p remotes/origin
+    dolt remote add origin file://./remotes/origin
+    dolt sql -q "CREATE TABLE t1 (pk int PRIMARY KEY)"
+    dolt commit -Am "create t1"
+    dolt push --set-upstream origin main
+
+    dolt checkout -b other
+    dolt sql -q "CREATE TABLE t2 (pk int PRIMARY KEY)"
+    dolt commit -Am "create t2"
+    dolt sql -q "CREATE TABLE t3 (pk int PRIMARY KEY)"
+    dolt commit -Am "create t3" --date "2020-01-01T00:00:00"
+    dolt checkout main
+    dolt sql -q "CREATE TABLE t4 (pk int PRIMARY KEY)"
+    dolt commit -Am "create t4"
+    dolt merge other -m "merge other"
+
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "ahead of 'origin/main' by 4 commits" ]] || false
+
+    run dolt status --since 24h
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "ahead of 'origin/main' by 3 commits" ]] || false
+}
+
+@test "status: --schema summarizes column changes" {
+    dolt sql -q "CREATE TABLE t (pk int primary key, a int, b int, c int)"
+    dolt add t
+    dolt commit -m "create t"
+
+    dolt sql -q "ALTER TABLE t ADD COLUMN d int"
+    dolt add t
+    dolt sql -q "ALTER TABLE t DROP COLUMN c"
+    dolt sql -q "ALTER TABLE t RENAME COLUMN b TO b2"
+
+    run dolt status --schema
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Schema changes to be committed:" ]] || false
+    [[ "$output" =~ "t: +d int" ]] || false
+    [[ "$output" =~ "Schema changes not staged for commit:" ]] || false
+    [[ "$output" =~ "t: b -> b2, -c" ]] || false
+
+    run dolt status --schema --staged
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Schema changes to be committed:" ]] || false
+    [[ ! "$output" =~ "Schema changes not staged for commit:" ]] || false
+
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "Schema changes" ]] || false
+}
+
+@test "status: --storage estimates the storage growth of each changed table" {
+    dolt sql -q "CREATE TABLE t (pk int primary key, c varchar(100), INDEX idx_c (c))"
+    dolt sql -q "CREATE TABLE unchanged (pk int primary key)"
+    dolt commit -Am "create tables"
+
+    dolt sql -q "INSERT INTO t SELECT x, concat('value ', x) FROM (WITH RECURSIVE s(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM s WHERE x < 5000) SELECT x FROM s) nums"
+    dolt sql -q "CREATE TABLE new_table (pk int primary key)"
+
+    run dolt status --storage
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Storage growth since HEAD:" ]] || false
+    [[ "$output" =~ "t: +" ]] || false
+    [[ "$output" =~ "new_table: +" ]] || false
+    [[ ! "$output" =~ "unchanged: +" ]] || false
+    [[ ! "$output" =~ "t: +0 B" ]] || false
+
+    run dolt status --storage --staged
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "Storage growth since HEAD:" ]] || false
+
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "Storage growth" ]] || false
+
+    run dolt status --storage --name-only
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "cannot use both --name-only and --storage" ]] || false
+}
+
+@test "status: reports the fetch and push remotes separately in a triangular workflow" {
+    mkdir -p remotes/origin remotes/fork
+    dolt remote add origin file://./remotes/origin
+    dolt remote add fork file://./remotes/fork
+    dolt sql -q "CREATE TABLE t1 (pk int PRIMARY KEY)"
+    dolt commit -Am "create t1"
+    dolt push --set-upstream origin main
+    dolt push fork main
+    dolt config --local --add remote.pushDefault fork
+
+    dolt sql -q "CREATE TABLE t2 (pk int PRIMARY KEY)"
+    dolt commit -Am "create t2"
+    dolt push fork main
+
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Relative to the fetch remote 'origin':" ]] || false
+    [[ "$output" =~ "Your branch is ahead of 'origin/main' by 1 commit." ]] || false
+    [[ "$output" =~ "Relative to the push remote 'fork':" ]] || false
+    [[ "$output" =~ "Your branch is up to date with 'fork/main'." ]] || false
+
+    # remote.pushDefault is only reported by dolt status, and dolt push still pushes to the upstream's remote
+    dolt push
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Your branch is up to date with 'origin/main'." ]] || false
+
+    dolt sql -q "CREATE TABLE t3 (pk int PRIMARY KEY)"
+    dolt commit -Am "create t3"
+    dolt config --local --unset remote.pushDefault
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "Relative to the" ]] || false
+    [[ "$output" =~ "Your branch is ahead of 'origin/main' by 1 commit." ]] || false
+}
+
+@test "status: --ahead-only and --behind-only print just the count" {
+    run dolt status --ahead-only
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "no upstream" ]] || false
+
+    mkdir -p remotes/origin
+    dolt remote add origin file://./remotes/origin
+    dolt sql -q "CREATE TABLE t1 (pk int PRIMARY KEY)"
+    dolt commit -Am "create t1"
+    dolt push --set-upstream origin main
+    dolt commit --allow-empty -m "empty 1"
+    dolt commit --allow-empty -m "empty 2"
+
+    run dolt status --ahead-only
+    [ "$status" -eq 0 ]
+    [ "$output" = "2" ]
+
+    run dolt status --behind-only
+    [ "$status" -eq 0 ]
+    [ "$output" = "0" ]
+
+    run dolt status --ahead-only --behind-only
+    [ "$status" -ne 0 ]
+}
+
+@test "status: --no-tracking leaves out the upstream" {
+    mkdir -p remotes/origin
+    dolt remote add origin file://./remotes/origin
+    dolt sql -q "CREATE TABLE t1 (pk int PRIMARY KEY)"
+    dolt commit -Am "create t1"
+    dolt push --set-upstream origin main
+    dolt commit --allow-empty -m "empty"
+    dolt sql -q "CREATE TABLE t2 (pk int PRIMARY KEY)"
+
+    run dolt status --no-tracking
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "On branch main" ]] || false
+    [[ ! "$output" =~ "origin/main" ]] || false
+    [[ "$output" =~ "t2" ]] || false
+
+    run dolt status --no-tracking --ahead-only
+    [ "$status" -ne 0 ]
+    [[ "$output" =~ "cannot use both --ahead-only and --no-tracking" ]] || false
+}
+
+@test "status: --worktrees lists each branch's working set" {
+    dolt sql -q "CREATE TABLE t1 (pk int PRIMARY KEY)"
+    dolt commit -Am "create t1"
+    dolt branch feature
+    dolt sql -q "CALL DOLT_CHECKOUT('feature'); INSERT INTO t1 VALUES (1);"
+
+    run dolt status --worktrees
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "* main" ]] || false
+    [[ "${lines[0]}" =~ "feature" ]] || false
+    [[ "${lines[0]}" =~ "dirty (0 staged, 1 unstaged)" ]] || false
+    [[ "${lines[1]}" =~ "clean" ]] || false
+}
+
+@test "status: --name-only prints just the tables which can be added" {
+    dolt sql -q "CREATE TABLE t1 (pk int PRIMARY KEY)"
+    dolt sql -q "CREATE TABLE t2 (pk int PRIMARY KEY)"
+    dolt commit -Am "create tables"
+    dolt sql -q "INSERT INTO t1 VALUES (1)"
+    dolt sql -q "CREATE TABLE t3 (pk int PRIMARY KEY)"
+    dolt sql -q "CREATE TABLE ignoreme (pk int PRIMARY KEY)"
+    dolt sql -q "INSERT INTO dolt_ignore VALUES ('ignoreme', true)"
+    dolt add t2 dolt_ignore
+    dolt sql -q "INSERT INTO t2 VALUES (1)"
+    dolt add t2
+
+    run dolt status --name-only
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 2 ]
+    [ "${lines[0]}" = "t1" ]
+    [ "${lines[1]}" = "t3" ]
+
+    run dolt status --name-only --staged
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "t2" ]] || false
+    [[ "$output" =~ "dolt_ignore" ]] || false
+    [[ ! "$output" =~ "t1" ]] || false
+
+    dolt status --name-only | xargs dolt add
+    run dolt status --name-only
+    [ "$status" -eq 0 ]
+    [ "$output" = "" ]
+}
+
+@test "status: a new table which is staged and then dropped is shown as both added and deleted" {
+    dolt sql -q "CREATE TABLE t1 (pk int PRIMARY KEY)"
+    dolt add t1
+    dolt sql -q "DROP TABLE t1"
+
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Changes to be committed:" ]] || false
+    [[ "$output" =~ "new table:        t1" ]] || false
+    [[ "$output" =~ "Changes not staged for commit:" ]] || false
+    [[ "$output" =~ "deleted:          t1" ]] || false
+    [[ ! "$output" =~ "Untracked tables:" ]] || false
+
+    run dolt status --name-only
+    [ "$status" -eq 0 ]
+    [ "$output" = "t1" ]
+
+    dolt add t1
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "nothing to commit, working tree clean" ]] || false
+}
+
+@test "status: --log-unpushed lists the commits which haven't been pushed" {
+    mkdir -p remotes/origin
+    dolt remote add origin file://./remotes/origin
+    dolt sql -q "CREATE TABLE t1 (pk int PRIMARY KEY)"
+    dolt commit -Am "create t1"
+    dolt push --set-upstream origin main
+
+    run dolt status --log-unpushed
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "Unpushed commits:" ]] || false
+
+    dolt sql -q "INSERT INTO t1 VALUES (1)"
+    dolt commit -am "first unpushed"
+    dolt sql -q "INSERT INTO t1 VALUES (2)"
+    dolt commit -am $'second unpushed\n\nwith a body'
+
+    run dolt status --log-unpushed
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "ahead of 'origin/main' by 2 commits" ]] || false
+    [[ "$output" =~ "Unpushed commits:" ]] || false
+    [[ "$output" =~ "second unpushed" ]] || false
+    [[ "$output" =~ "first unpushed" ]] || false
+    [[ ! "$output" =~ "with a body" ]] || false
+    [[ ! "$output" =~ "create t1" ]] || false
+}
+
+@test "status: --relative only shows tables in the namespace, without its prefix" {
+    dolt sql -q "CREATE TABLE acme_orders (pk int PRIMARY KEY)"
+    dolt sql -q "CREATE TABLE globex_orders (pk int PRIMARY KEY)"
+    dolt add acme_orders
+    dolt sql -q "CREATE TABLE acme_users (pk int PRIMARY KEY)"
+
+    run dolt status --relative acme_
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "new table:        orders" ]] || false
+    [[ "$output" =~ "new table:        users" ]] || false
+    [[ ! "$output" =~ "acme_" ]] || false
+    [[ ! "$output" =~ "globex" ]] || false
+
+    run dolt status --relative acme_ --name-only
+    [ "$status" -eq 0 ]
+    [ "$output" = "users" ]
+
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "acme_orders" ]] || false
+    [[ "$output" =~ "globex_orders" ]] || false
+}
+
+@test "status: warns when a fetch shows the branch diverged because its upstream was rewritten" {
+    mkdir -p remotes/origin
+    dolt remote add origin file://./remotes/origin
+    dolt sql -q "CREATE TABLE t1 (pk int PRIMARY KEY)"
+    dolt commit -Am "create t1"
+    dolt sql -q "INSERT INTO t1 VALUES (1)"
+    dolt commit -am "old upstream commit"
+    dolt push origin main
+
+    dolt clone "file://$(pwd)/remotes/origin" clone
+    cd clone
+    dolt sql -q "INSERT INTO t1 VALUES (10)"
+    dolt commit -am "my commit"
+    cd ..
+
+    # the upstream commit the clone has is replaced with a force push
+    dolt reset --hard HEAD~1
+    dolt sql -q "INSERT INTO t1 VALUES (2)"
+    dolt commit -am "new upstream commit"
+    dolt push -f origin main
+
+    cd clone
+    dolt fetch
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "have diverged" ]] || false
+    [[ "$output" =~ "'origin/main' was rewritten" ]] || false
+    [[ "$output" =~ "dolt reset --hard origin/main" ]] || false
+}
+
+@test "status: no rewrite warning when the branch and its upstream both have new commits" {
+    mkdir -p remotes/origin
+    dolt remote add origin file://./remotes/origin
+    dolt sql -q "CREATE TABLE t1 (pk int PRIMARY KEY)"
+    dolt commit -Am "create t1"
+    dolt push origin main
+
+    dolt clone "file://$(pwd)/remotes/origin" clone
+    cd clone
+    # a commit under another author is still a commit of the branch's own
+    dolt sql -q "INSERT INTO t1 VALUES (10)"
+    dolt commit -am "my commit" --author "Other User <other@example.com>"
+    cd ..
+
+    dolt sql -q "INSERT INTO t1 VALUES (1)"
+    dolt commit -am "new upstream commit"
+    dolt push origin main
+
+    cd clone
+    dolt fetch
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "have diverged" ]] || false
+    [[ ! "$output" =~ "was rewritten" ]] || false
+}
+
+@test "status: --ignore-autoincrement ignores tables whose auto increment value is all that changed" {
+    dolt sql -q "CREATE TABLE t1 (pk int PRIMARY KEY AUTO_INCREMENT, c1 int)"
+    dolt sql -q "INSERT INTO t1 (c1) VALUES (1)"
+    dolt commit -Am "create t1"
+
+    dolt sql -q "INSERT INTO t1 (c1) VALUES (2)"
+    dolt sql -q "DELETE FROM t1 WHERE c1 = 2"
+
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "modified:         t1" ]] || false
+
+    run dolt status --ignore-autoincrement
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "t1" ]] || false
+    [[ "$output" =~ "nothing to commit, working tree clean" ]] || false
+
+    # other changes are still reported
+    dolt sql -q "INSERT INTO t1 (c1) VALUES (3)"
+    run dolt status --ignore-autoincrement
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "modified:         t1" ]] || false
+}
+
+@test "status: --watch prints the status once when the output isn't a terminal" {
+    dolt sql -q "CREATE TABLE t1 (pk int PRIMARY KEY)"
+
+    run dolt status --watch
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "new table:        t1" ]] || false
+    [[ ! "$output" =~ $'\033[2J' ]] || false
+
+    run dolt status --watch --worktrees
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "cannot use both --worktrees and --watch" ]] || false
+}
+
+@test "status: --format prints the status with a Go template" {
+    dolt sql -q "CREATE TABLE t1 (pk int PRIMARY KEY)"
+    dolt add t1
+    dolt sql -q "CREATE TABLE t2 (pk int PRIMARY KEY)"
+
+    run dolt status --format '{{.Status}} {{.Name}} {{.Staged}}'
+    [ "$status" -eq 0 ]
+    [ "${#lines[@]}" -eq 2 ]
+    [ "${lines[0]}" = "added t1 true" ]
+    [ "${lines[1]}" = "added t2 false" ]
+
+    run dolt status --format '{{.Branch}}: {{len .Tables}} tables, clean={{.Clean}}' --format-once
+    [ "$status" -eq 0 ]
+    [ "$output" = "main: 2 tables, clean=false" ]
+
+    run dolt status --format '{{.Name'
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "invalid --format template" ]] || false
+
+    run dolt status --format-once
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "--format-once requires --format" ]] || false
+}
+
+@test "status: --worktree-vs-index only shows the changes dolt add would pick up" {
+    dolt sql -q "CREATE TABLE t1 (pk int PRIMARY KEY)"
+    dolt add t1
+    dolt sql -q "CREATE TABLE t2 (pk int PRIMARY KEY)"
+
+    run dolt status --worktree-vs-index
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "On branch main" ]] || false
+    [[ ! "$output" =~ "Changes to be committed:" ]] || false
+    [[ "$output" =~ "Untracked tables:" ]] || false
+    [[ "$output" =~ "t2" ]] || false
+    [[ ! "$output" =~ "t1" ]] || false
+
+    dolt add t2
+    run dolt status --worktree-vs-index
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "nothing to add, working tree matches the staging area" ]] || false
+
+    run dolt status --worktree-vs-index --staged
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "cannot use both --staged and --worktree-vs-index" ]] || false
+}
+
+@test "status: --schema-against reports schema drift from a reference branch" {
+    dolt sql -q "CREATE TABLE t1 (pk int PRIMARY KEY, c1 int)"
+    dolt sql -q "CREATE TABLE t2 (pk int PRIMARY KEY)"
+    dolt commit -Am "create tables"
+    dolt checkout -b feature
+
+    dolt sql -q "INSERT INTO t2 VALUES (1)"
+    run dolt status --schema-against main
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "no schema drift from main" ]] || false
+
+    dolt sql -q "ALTER TABLE t1 ADD COLUMN c2 varchar(20)"
+    dolt sql -q "CREATE TABLE t3 (pk int PRIMARY KEY)"
+    run dolt status --schema-against main
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Schema drift from main:" ]] || false
+    [[ "$output" =~ "t1: +c2 varchar(20)" ]] || false
+    [[ "$output" =~ "t3: not on main" ]] || false
+    [[ ! "$output" =~ "t2:" ]] || false
+
+    run dolt status --schema-against main --name-only
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "cannot use both --name-only and --schema-against" ]] || false
+}
+
+@test "status: --fast lists changed tables by comparing hashes" {
+    dolt sql -q "CREATE TABLE t1 (pk int PRIMARY KEY)"
+    dolt sql -q "CREATE TABLE t2 (pk int PRIMARY KEY)"
+    dolt commit -Am "create tables"
+
+    run dolt status --fast
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "nothing to commit, working tree clean" ]] || false
+
+    dolt sql -q "INSERT INTO t1 VALUES (1)"
+    dolt add t1
+    dolt sql -q "RENAME TABLE t2 TO t3"
+    run dolt status --fast
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "On branch main" ]] || false
+    [[ "$output" =~ "Changes to be committed:" ]] || false
+    [[ "$output" =~ "modified:         t1" ]] || false
+    [[ "$output" =~ "deleted:          t2" ]] || false
+    [[ "$output" =~ "new table:        t3" ]] || false
+    [[ "$output" =~ "tables are only compared by hash" ]] || false
+
+    run dolt status --fast --name-only
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "cannot use both --name-only and --fast" ]] || false
+}
+
+@test "status: --show-index-work annotates tables whose change builds indexes" {
+    dolt sql -q "CREATE TABLE t1 (pk int PRIMARY KEY, a int, b int, INDEX idx_a (a))"
+    dolt sql -q "CREATE TABLE t2 (pk int PRIMARY KEY, a int)"
+    dolt commit -Am "create tables"
+
+    dolt sql -q "ALTER TABLE t1 ADD INDEX idx_b (b)"
+    dolt add t1
+    dolt sql -q "ALTER TABLE t1 DROP INDEX idx_a"
+    dolt sql -q "ALTER TABLE t1 ADD INDEX idx_a (a, b)"
+    dolt sql -q "INSERT INTO t2 VALUES (1, 1)"
+
+    run dolt status --show-index-work
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "modified:         t1 (builds indexes: idx_b)" ]] || false
+    [[ "$output" =~ "modified:         t1 (builds indexes: idx_a)" ]] || false
+    [[ "$output" =~ "modified:         t2" ]] || false
+    [[ ! "$output" =~ "t2 (builds indexes" ]] || false
+
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "builds indexes" ]] || false
+
+    run dolt status --show-index-work --name-only
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "cannot use both --name-only and --show-index-work" ]] || false
+}
+
+@test "status: staged tables brought in by a merge are annotated" {
+    dolt sql -q "CREATE TABLE t1 (pk int PRIMARY KEY)"
+    dolt sql -q "CREATE TABLE t3 (pk int PRIMARY KEY)"
+    dolt commit -Am "create tables"
+    dolt checkout -b other
+    dolt sql -q "INSERT INTO t1 VALUES (1)"
+    dolt sql -q "CREATE TABLE t2 (pk int PRIMARY KEY)"
+    dolt commit -Am "change t1 and add t2 on other"
+    dolt checkout main
+    dolt sql -q "INSERT INTO t3 VALUES (2)"
+    dolt commit -am "change t3 on main"
+
+    dolt merge --no-commit other
+    dolt sql -q "INSERT INTO t3 VALUES (3)"
+    dolt add t3
+
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "modified:         t1 (from merge)" ]] || false
+    [[ "$output" =~ "new table:        t2 (from merge)" ]] || false
+    [[ "$output" =~ "modified:         t3" ]] || false
+    [[ ! "$output" =~ "t3 (from merge)" ]] || false
+
+    dolt commit -m "merge other"
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "(from merge)" ]] || false
+}
+
+@test "status: --conflicts-only lists the tables with conflicts and their counts" {
+    dolt sql -q "CREATE TABLE t1 (pk int PRIMARY KEY, c int)"
+    dolt sql -q "CREATE TABLE t2 (pk int PRIMARY KEY)"
+    dolt sql -q "INSERT INTO t1 VALUES (1, 1), (2, 2)"
+    dolt commit -Am "create tables"
+    dolt checkout -b other
+    dolt sql -q "UPDATE t1 SET c = c + 10"
+    dolt commit -am "update t1 on other"
+    dolt checkout main
+    dolt sql -q "UPDATE t1 SET c = c + 20"
+    dolt sql -q "INSERT INTO t2 VALUES (1)"
+    dolt commit -am "update t1 on main"
+
+    run dolt status --conflicts-only
+    [ "$status" -eq 1 ]
+    [ "$output" = "" ]
+
+    run dolt merge other
+
+    run dolt status --conflicts-only
+    [ "$status" -eq 0 ]
+    [ "$output" = "$(printf 't1\tfalse\t2\t0')" ]
+
+    run dolt status --conflicts-only --name-only
+    [ "$status" -eq 1 ]
+    [[ "$output" =~ "cannot use both --name-only and --conflicts-only" ]] || false
+}
+
+@test "status: --debug-timing prints how long each step took to stderr" {
+    dolt sql -q "CREATE TABLE t1 (pk int PRIMARY KEY)"
+
+    run dolt status --debug-timing
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "new table:        t1" ]] || false
+    [[ "$output" =~ "status timing: step=table_deltas duration_ms=" ]] || false
+    [[ "$output" =~ "status timing: step=merge_artifacts duration_ms=" ]] || false
+    [[ "$output" =~ "status timing: step=remote_tracking duration_ms=" ]] || false
+
+    run dolt status --debug-timing 2>/dev/null
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "status timing" ]] || false
+
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ ! "$output" =~ "status timing" ]] || false
+}
+
+@test "status: tables whose collation is all that changed are labeled collation-changed" {
+    dolt sql -q "CREATE TABLE t1 (pk int PRIMARY KEY, c varchar(20) COLLATE utf8mb4_0900_bin)"
+    dolt sql -q "CREATE TABLE t2 (pk int PRIMARY KEY, c varchar(20) COLLATE utf8mb4_0900_bin)"
+    dolt sql -q "INSERT INTO t1 VALUES (1, 'a')"
+    dolt sql -q "INSERT INTO t2 VALUES (1, 'a')"
+    dolt commit -Am "create tables"
+
+    dolt sql -q "ALTER TABLE t1 MODIFY c varchar(20) COLLATE utf8mb4_general_ci"
+    dolt sql -q "ALTER TABLE t2 MODIFY c varchar(20) COLLATE utf8mb4_general_ci"
+    dolt sql -q "INSERT INTO t2 VALUES (2, 'b')"
+
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "collation-changed: t1" ]] || false
+    [[ "$output" =~ "modified:         t2" ]] || false
+
+    dolt add t1
+    dolt sql -q "ALTER TABLE t1 COLLATE utf8mb4_general_ci"
+    run dolt status
+    [ "$status" -eq 0 ]
+    [[ "$output" =~ "Changes not staged for commit:" ]] || false
+    [[ $(echo "$output" | grep -c "collation-changed: t1") -eq 2 ]] || false
+}
@@ -26,6 +26,7 @@ export const logTests = [
         email: "dolt@dolthub.com",
         date: "",
         message: "Create table test",
+        metadata: null,
       },
       {
         commit_hash: "",
@@ -33,6 +34,7 @@ export const logTests = [
         email: "mysql-test-runner@liquidata.co",
         date: "",
         message: "Initialize data repository",
+        metadata: null,
       },
     ],
     matcher: logsMatcher,